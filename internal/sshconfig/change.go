@@ -0,0 +1,92 @@
+package sshconfig
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Change is a planned rewrite of a single config file, so it can be
+// previewed before anything is written
+type Change struct {
+	Path string // File the change applies to (the main config or an Include)
+	Old  string // Current file content ("" if the file doesn't exist yet)
+	New  string // Content after the change
+}
+
+// Diff returns the change as a unified diff, or "" if nothing changes
+func (c *Change) Diff() string {
+	return UnifiedDiff(c.Path, c.Path, c.Old, c.New)
+}
+
+// Apply writes the new content, refusing with ErrConfigChanged if the file
+// was modified since the change was planned
+func (c *Change) Apply() error {
+	current, err := readConfigFile(c.Path)
+	if err != nil {
+		return &WriteError{Path: c.Path, Op: "failed to read config file", Err: err}
+	}
+	if current != c.Old {
+		return fmt.Errorf("%w: %s", ErrConfigChanged, c.Path)
+	}
+	return writeConfigFile(c.Path, c.New)
+}
+
+// readConfigFile returns the content of the config file at path with LF
+// line endings (like RenderConfig), or "" if it doesn't exist
+func readConfigFile(path string) (string, error) {
+	resolved, err := resolveConfigPath(path)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(resolved)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	return strings.ReplaceAll(string(data), "\r\n", "\n"), nil
+}
+
+// planChange renders entries for path and pairs them with its current content
+func planChange(path string, entries []*HostEntry, standaloneComments []string) (*Change, error) {
+	old, err := readConfigFile(path)
+	if err != nil {
+		return nil, &WriteError{Path: path, Op: "failed to read config file", Err: err}
+	}
+	content, err := RenderConfig(entries, standaloneComments)
+	if err != nil {
+		return nil, &WriteError{Path: path, Op: "failed to render config", Err: err}
+	}
+	return &Change{Path: path, Old: old, New: content}, nil
+}
+
+// PlanAddEntry returns the change AddEntry would make
+func PlanAddEntry(path string, entry *HostEntry) (*Change, error) {
+	entries, standaloneComments, err := parseSingleFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	entries = append(entries, entry)
+	return planChange(path, entries, standaloneComments)
+}
+
+// PlanUpdateEntry returns the change UpdateEntry would make
+func PlanUpdateEntry(path string, oldHost string, newEntry *HostEntry) (*Change, error) {
+	file, err := sourceFileFor(path, oldHost)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, standaloneComments, err := parseSingleFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	i := FindEntry(entries, oldHost)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %q", ErrHostNotFound, oldHost)
+	}
+	entries[i] = newEntry
+
+	return planChange(file, entries, standaloneComments)
+}
@@ -0,0 +1,75 @@
+package sshconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlanUpdateEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	original := "Host prod\n    HostName prod.example.com\n    User deploy\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(path)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	updated := *entries[0]
+	updated.User = "root"
+
+	change, err := PlanUpdateEntry(path, "prod", &updated)
+	if err != nil {
+		t.Fatalf("PlanUpdateEntry failed: %v", err)
+	}
+	if change.Old != original {
+		t.Errorf("Old content mismatch: %q", change.Old)
+	}
+	diff := change.Diff()
+	if !strings.Contains(diff, "-    User deploy\n") || !strings.Contains(diff, "+    User root\n") {
+		t.Errorf("Unexpected diff:\n%s", diff)
+	}
+
+	// Planning doesn't write anything
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("Config was modified by planning: %q", data)
+	}
+
+	if err := change.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != change.New {
+		t.Errorf("Applied content mismatch: %q", data)
+	}
+
+	// A stale change is refused
+	if err := change.Apply(); !errors.Is(err, ErrConfigChanged) {
+		t.Errorf("Expected ErrConfigChanged for a stale change, got %v", err)
+	}
+
+	if _, err := PlanUpdateEntry(path, "missing", &updated); !errors.Is(err, ErrHostNotFound) {
+		t.Errorf("Expected ErrHostNotFound, got %v", err)
+	}
+}
+
+func TestPlanAddEntry_NewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	change, err := PlanAddEntry(path, &HostEntry{Host: "dev", HostName: "dev.example.com"})
+	if err != nil {
+		t.Fatalf("PlanAddEntry failed: %v", err)
+	}
+	if change.Old != "" {
+		t.Errorf("Old content of a missing file should be empty, got %q", change.Old)
+	}
+	if !strings.HasPrefix(change.Diff(), "--- "+path+"\n+++ "+path+"\n@@ -0,0 +1,") {
+		t.Errorf("Unexpected diff:\n%s", change.Diff())
+	}
+	if err := change.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package sshconfig
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	if got := UnifiedDiff("a", "b", "same\n", "same\n"); got != "" {
+		t.Errorf("Identical texts should give no diff, got %q", got)
+	}
+
+	old := "Host prod\n    HostName prod.example.com\n    User deploy\n"
+	new := "Host prod\n    HostName prod.example.com\n    User root\n    Port 2222\n"
+	want := "--- config\n+++ config\n" +
+		"@@ -1,3 +1,4 @@\n" +
+		" Host prod\n" +
+		"     HostName prod.example.com\n" +
+		"-    User deploy\n" +
+		"+    User root\n" +
+		"+    Port 2222\n"
+	if got := UnifiedDiff("config", "config", old, new); got != want {
+		t.Errorf("UnifiedDiff mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	// New file
+	want = "--- config\n+++ config\n@@ -0,0 +1,2 @@\n+Host dev\n+    HostName dev\n"
+	if got := UnifiedDiff("config", "config", "", "Host dev\n    HostName dev\n"); got != want {
+		t.Errorf("New file diff mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnifiedDiff_SeparateHunks(t *testing.T) {
+	var old, new string
+	for i := 1; i <= 20; i++ {
+		line := string(rune('a'+i-1)) + "\n"
+		old += line
+		switch i {
+		case 2:
+			new += "B\n"
+		case 18:
+			new += "R\n"
+		default:
+			new += line
+		}
+	}
+
+	want := "--- f\n+++ f\n" +
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+		"@@ -15,6 +15,6 @@\n o\n p\n q\n-r\n+R\n s\n t\n"
+	if got := UnifiedDiff("f", "f", old, new); got != want {
+		t.Errorf("UnifiedDiff mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package sshconfig
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrHostNotFound is returned when an operation targets a Host that isn't in the config
+var ErrHostNotFound = errors.New("host not found")
+
+// ErrSingleAlias is returned when splitting a Host line that has only one alias
+var ErrSingleAlias = errors.New("host has only one alias")
+
+// ParseError is returned when the config file can't be read or parsed.
+// Line is 1-based, or 0 when the error isn't tied to a specific line.
+type ParseError struct {
+	Path   string
+	Line   int
+	Reason string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	msg := e.Reason
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, msg)
+	}
+	return msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// WriteError is returned when the config file can't be written
+type WriteError struct {
+	Path string
+	Op   string // What was being done, e.g. "failed to create config file"
+	Err  error
+}
+
+func (e *WriteError) Error() string {
+	return e.Op + ": " + e.Err.Error()
+}
+
+func (e *WriteError) Unwrap() error {
+	return e.Err
+}
+
+// ErrConfigChanged is returned when applying a Change to a file that was
+// modified after the Change was planned
+var ErrConfigChanged = errors.New("config file changed on disk")
@@ -0,0 +1,85 @@
+package sshconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestErrHostNotFound(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	err := WriteConfig(configPath, []*HostEntry{{Host: "example", HostName: "example.com"}}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create initial config: %v", err)
+	}
+
+	err = UpdateEntry(configPath, "missing", &HostEntry{Host: "missing", HostName: "missing.com"})
+	if !errors.Is(err, ErrHostNotFound) {
+		t.Errorf("UpdateEntry: expected ErrHostNotFound, got %v", err)
+	}
+
+	err = DeleteEntry(configPath, "missing")
+	if !errors.Is(err, ErrHostNotFound) {
+		t.Errorf("DeleteEntry: expected ErrHostNotFound, got %v", err)
+	}
+
+	err = SplitEntry(configPath, "missing")
+	if !errors.Is(err, ErrHostNotFound) {
+		t.Errorf("SplitEntry: expected ErrHostNotFound, got %v", err)
+	}
+
+	err = SplitEntry(configPath, "example")
+	if !errors.Is(err, ErrSingleAlias) {
+		t.Errorf("SplitEntry: expected ErrSingleAlias, got %v", err)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	// A line longer than maxLineLength can't be read
+	content := "Host example\n    HostName example.com\n    # " + strings.Repeat("x", maxLineLength+1) + "\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	_, _, err := ParseConfig(configPath)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected ParseError, got %v", err)
+	}
+	if parseErr.Line != 3 {
+		t.Errorf("ParseError line: got %d, want 3", parseErr.Line)
+	}
+	if parseErr.Path != configPath {
+		t.Errorf("ParseError path: got %q, want %q", parseErr.Path, configPath)
+	}
+	if !strings.Contains(parseErr.Error(), ":3:") {
+		t.Errorf("ParseError message should include the line number, got %q", parseErr.Error())
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Use a regular file as the parent directory so creating the config fails
+	blocker := filepath.Join(tmpDir, "not-a-dir")
+	if err := os.WriteFile(blocker, []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to create blocker file: %v", err)
+	}
+	configPath := filepath.Join(blocker, "config")
+
+	err := WriteConfig(configPath, []*HostEntry{{Host: "example", HostName: "example.com"}}, nil)
+	var writeErr *WriteError
+	if !errors.As(err, &writeErr) {
+		t.Fatalf("Expected WriteError, got %v", err)
+	}
+	if writeErr.Path != configPath {
+		t.Errorf("WriteError path: got %q, want %q", writeErr.Path, configPath)
+	}
+}
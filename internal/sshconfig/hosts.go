@@ -0,0 +1,62 @@
+package sshconfig
+
+// DisplayHosts returns the entries that are actual hosts, leaving out
+// "Host *" blocks (global defaults rather than something to connect to).
+// The order of entries is kept.
+func DisplayHosts(entries []*HostEntry) []*HostEntry {
+	hosts := make([]*HostEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Host != "*" {
+			hosts = append(hosts, entry)
+		}
+	}
+	return hosts
+}
+
+// LoadHosts parses the config at path (following Include directives) and
+// returns its hosts in config file order, without "Host *" blocks
+func LoadHosts(path string) ([]*HostEntry, error) {
+	entries, _, err := ParseConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return DisplayHosts(entries), nil
+}
+
+// HostNames returns the Host value of each entry
+func HostNames(entries []*HostEntry) []string {
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Host
+	}
+	return names
+}
+
+// SortByHosts returns entries in the order of sortedHosts (Host values, e.g.
+// from VisitTracker.SortByVisits(HostNames(entries))). Entries missing from
+// sortedHosts are appended in their original order.
+func SortByHosts(entries []*HostEntry, sortedHosts []string) []*HostEntry {
+	// Queue per Host, so duplicate Host blocks each keep their place
+	byHost := make(map[string][]*HostEntry)
+	for _, entry := range entries {
+		byHost[entry.Host] = append(byHost[entry.Host], entry)
+	}
+
+	sorted := make([]*HostEntry, 0, len(entries))
+	placed := make(map[*HostEntry]bool, len(entries))
+	for _, host := range sortedHosts {
+		if queue := byHost[host]; len(queue) > 0 {
+			sorted = append(sorted, queue[0])
+			placed[queue[0]] = true
+			byHost[host] = queue[1:]
+		}
+	}
+
+	for _, entry := range entries {
+		if !placed[entry] {
+			sorted = append(sorted, entry)
+		}
+	}
+
+	return sorted
+}
@@ -0,0 +1,38 @@
+package sshconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadHosts(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	content := "Host *\n    ServerAliveInterval 30\n\nHost web\n    HostName web.example.com\n\nHost db\n    HostName db.example.com\n"
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	hosts, err := LoadHosts(configPath)
+	if err != nil {
+		t.Fatalf("LoadHosts failed: %v", err)
+	}
+	if got := HostNames(hosts); !reflect.DeepEqual(got, []string{"web", "db"}) {
+		t.Errorf("Expected web and db without Host *, got %v", got)
+	}
+}
+
+func TestSortByHosts(t *testing.T) {
+	web := &HostEntry{Host: "web"}
+	db := &HostEntry{Host: "db"}
+	cache := &HostEntry{Host: "cache"}
+	dup := &HostEntry{Host: "web", HostName: "second block"}
+	entries := []*HostEntry{web, db, cache, dup}
+
+	sorted := SortByHosts(entries, []string{"cache", "web", "web", "unknown"})
+	want := []*HostEntry{cache, web, dup, db}
+	if !reflect.DeepEqual(sorted, want) {
+		t.Errorf("SortByHosts: got %v, want %v", HostNames(sorted), HostNames(want))
+	}
+}
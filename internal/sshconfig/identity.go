@@ -0,0 +1,65 @@
+package sshconfig
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// publicKeyPrefixes are the leading tokens of OpenSSH/RFC4716 public key files
+var publicKeyPrefixes = []string{
+	"ssh-rsa ",
+	"ssh-dss ",
+	"ssh-ed25519 ",
+	"ecdsa-sha2-",
+	"sk-ssh-ed25519@openssh.com ",
+	"sk-ecdsa-sha2-",
+	"---- BEGIN SSH2 PUBLIC KEY ----",
+}
+
+// IsPublicKeyPath reports whether an IdentityFile value points to a public key,
+// either by its .pub extension or, if the file is readable, by its contents
+func IsPublicKeyPath(path string) bool {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return false
+	}
+	if strings.HasSuffix(strings.ToLower(path), ".pub") {
+		return true
+	}
+
+	if strings.HasPrefix(path, "~") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return false
+		}
+		path = strings.Replace(path, "~", homeDir, 1)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return false
+	}
+	firstLine := strings.TrimSpace(scanner.Text())
+	for _, prefix := range publicKeyPrefixes {
+		if strings.HasPrefix(firstLine, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IdentityFileWarning returns a non-blocking warning about the entry's
+// IdentityFile, or an empty string if it looks fine
+func (h *HostEntry) IdentityFileWarning() string {
+	if IsPublicKeyPath(h.IdentityFile) {
+		return "IdentityFile looks like a public key; ssh needs the private key"
+	}
+	return ""
+}
@@ -1,34 +1,133 @@
 package sshconfig
 
+import (
+	"net"
+	"strings"
+)
+
+// DefaultPort is the port ssh uses when no Port directive is set
+const DefaultPort = "22"
+
+// DefaultLogsCommand is the remote command used to tail logs when no # Logs: comment is set
+const DefaultLogsCommand = "journalctl -f"
+
 // HostEntry represents a single SSH host configuration entry
 type HostEntry struct {
-	Host         string   // Host alias
-	HostName     string   // HostName directive
-	User         string   // User directive
-	Port         string   // Port directive
-	IdentityFile string   // IdentityFile directive
-	Description  string   // Extracted from comment above Host entry
-	Tags         []string // Tags extracted from # Tags: comment
-	Comment      string   // Original comment block
-	RawLines     []string // Original lines for preservation
-	StartLine    int      // Starting line number in original file
-	EndLine      int      // Ending line number in original file
+	Host         string   `json:"host"`          // Host alias
+	HostName     string   `json:"hostname"`      // HostName directive
+	User         string   `json:"user"`          // User directive
+	Port         string   `json:"port"`          // Port directive
+	IdentityFile string   `json:"identity_file"` // IdentityFile directive
+	ProxyJump    string   `json:"proxy_jump"`    // ProxyJump directive (bastion host)
+	ProxyCommand string   `json:"proxy_command"` // ProxyCommand directive, kept verbatim (%h/%p tokens, spacing)
+	ForwardAgent string   `json:"forward_agent"` // ForwardAgent directive (yes/no)
+	LocalForward []string `json:"local_forward"` // LocalForward directives, one per forward
+	// RemoteForward directives, one per forward
+	RemoteForward []string `json:"remote_forward"`
+	// SetEnv directives, one per line, each kept verbatim (e.g. "FOO=bar BAZ=\"a b\"")
+	SetEnv []string `json:"set_env"`
+	// Agent directives, mostly set once in the Host * block
+	AddKeysToAgent string `json:"add_keys_to_agent"` // AddKeysToAgent directive (yes/no/ask/confirm)
+	UseKeychain    string `json:"use_keychain"`      // UseKeychain directive (yes/no, macOS only)
+	IdentitiesOnly string `json:"identities_only"`   // IdentitiesOnly directive (yes/no)
+	// ExtraDirectives holds the directives gosshit has no field for (e.g.
+	// "Compression yes"), trimmed. When nil, such lines are kept from RawLines
+	// as-is; when non-nil, it replaces them.
+	ExtraDirectives []string `json:"extra_directives"`
+	Description     string   `json:"description"`  // Extracted from comment above Host entry
+	Tags            []string `json:"tags"`         // Tags extracted from # Tags: comment
+	LogsCommand     string   `json:"logs_command"` // Remote log command extracted from # Logs: comment
+	Comment         string   `json:"comment"`      // Original comment block
+	RawLines        []string `json:"raw_lines"`    // Original lines for preservation
+	StartLine       int      `json:"start_line"`   // Starting line number in original file
+	EndLine         int      `json:"end_line"`     // Ending line number in original file
+	SourceFile      string   `json:"source_file"`  // Config file the entry was parsed from (differs from the main config for Included files)
+}
+
+// IsKnownDirective reports whether gosshit manages directive (lowercase) through a HostEntry field
+func IsKnownDirective(directive string) bool {
+	switch directive {
+	case "host", "hostname", "user", "port", "identityfile", "proxyjump", "proxycommand", "forwardagent", "localforward", "remoteforward",
+		"setenv", "addkeystoagent", "usekeychain", "identitiesonly":
+		return true
+	}
+	return false
 }
 
 // IsValid checks if the host entry has the minimum required fields
-// Host * entries are valid without HostName (they're global config blocks)
+// Pattern entries (Host *, Host *.internal, ...) are valid without HostName
+// (they're shared config blocks, not hosts you connect to)
 func (h *HostEntry) IsValid() bool {
 	if h.Host == "" {
 		return false
 	}
-	// Host * entries don't need HostName
-	if h.Host == "*" {
+	// Pattern entries don't need HostName
+	if h.IsPattern() {
 		return true
 	}
 	// Regular entries need HostName
 	return h.HostName != ""
 }
 
+// IsPattern reports whether the Host line uses wildcards or negation
+// (*, ? or !) and so matches other hosts rather than naming one
+func (h *HostEntry) IsPattern() bool {
+	return strings.ContainsAny(h.Host, "*?!")
+}
+
+// IsDefaultPort reports whether the entry connects on the default SSH port,
+// either because Port is unset or because it is explicitly 22
+func (h *HostEntry) IsDefaultPort() bool {
+	return h.Port == "" || h.Port == DefaultPort
+}
+
+// Aliases returns the individual patterns of a Host line (e.g. "a b c" -> [a b c]).
+// Host keeps the whole line so the block is written back on a single Host line.
+func (h *HostEntry) Aliases() []string {
+	return strings.Fields(h.Host)
+}
+
+// MatchesHost reports whether host names this entry: the whole Host value
+// or any one of its aliases, ignoring case and extra whitespace
+func (h *HostEntry) MatchesHost(host string) bool {
+	host = strings.Join(strings.Fields(host), " ")
+	if strings.EqualFold(strings.Join(h.Aliases(), " "), host) {
+		return true
+	}
+	for _, alias := range h.Aliases() {
+		if strings.EqualFold(alias, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindEntry returns the index of the entry host refers to, or -1. An exact
+// Host match wins over a case-insensitive or single-alias match, so "web"
+// finds "Host web" before "Host web web2".
+func FindEntry(entries []*HostEntry, host string) int {
+	for i, entry := range entries {
+		if entry.Host == host {
+			return i
+		}
+	}
+	for i, entry := range entries {
+		if entry.MatchesHost(host) {
+			return i
+		}
+	}
+	return -1
+}
+
+// PrimaryAlias returns the first alias of the Host line, the one used to
+// connect (ssh treats the whole "a b c" string as a single, unknown host)
+func (h *HostEntry) PrimaryAlias() string {
+	if aliases := h.Aliases(); len(aliases) > 0 {
+		return aliases[0]
+	}
+	return h.Host
+}
+
 // GetConnectionString returns the SSH connection string (user@hostname)
 func (h *HostEntry) GetConnectionString() string {
 	if h.User != "" {
@@ -37,12 +136,73 @@ func (h *HostEntry) GetConnectionString() string {
 	return h.HostName
 }
 
-// GetSSHCommand returns the full SSH command string
+// GetAddress returns HostName with the port appended when it isn't the
+// default, e.g. "example.com:2222" or "[2001:db8::1]:2222"
+func (h *HostEntry) GetAddress() string {
+	if h.IsDefaultPort() {
+		return h.HostName
+	}
+	return FormatHostPort(h.HostName, h.Port)
+}
+
+// GetSSHCommand returns the full SSH command string. The port is passed with
+// -p, so an IPv6 HostName stays bare: ssh takes "user@2001:db8::1" as is.
 func (h *HostEntry) GetSSHCommand() string {
 	cmd := "ssh"
-	if h.Port != "" {
+	if !h.IsDefaultPort() {
 		cmd += " -p " + h.Port
 	}
 	cmd += " " + h.GetConnectionString()
 	return cmd
 }
+
+// IsIPv6 reports whether host is an IPv6 literal, optionally in brackets or
+// with a zone (e.g. "fe80::1%eth0")
+func IsIPv6(host string) bool {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	host, _, _ = strings.Cut(host, "%")
+	return strings.Contains(host, ":") && net.ParseIP(host) != nil
+}
+
+// BracketIPv6 wraps an IPv6 literal in brackets ("[2001:db8::1]") so a port
+// can follow it; other hosts are returned unchanged
+func BracketIPv6(host string) string {
+	if IsIPv6(host) && !strings.HasPrefix(host, "[") {
+		return "[" + host + "]"
+	}
+	return host
+}
+
+// FormatHostPort joins host and port as "host:port", bracketing IPv6
+// literals ("[2001:db8::1]:22") so the port isn't read as part of the address
+func FormatHostPort(host, port string) string {
+	return BracketIPv6(host) + ":" + port
+}
+
+// GetLogsCommand returns the remote command used to tail this host's logs
+func (h *HostEntry) GetLogsCommand() string {
+	if h.LogsCommand != "" {
+		return h.LogsCommand
+	}
+	return DefaultLogsCommand
+}
+
+// UniqueTags returns tags with duplicates removed (case-insensitive),
+// keeping the first occurrence and the original order
+func UniqueTags(tags []string) []string {
+	if len(tags) == 0 {
+		return tags
+	}
+
+	seen := make(map[string]bool, len(tags))
+	unique := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, tag)
+	}
+	return unique
+}
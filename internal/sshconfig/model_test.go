@@ -31,6 +31,13 @@ func TestHostEntry_IsValid(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "valid pattern entry without hostname",
+			entry: &HostEntry{
+				Host: "*.internal !bastion.internal",
+			},
+			want: true,
+		},
 		{
 			name: "invalid - missing hostname",
 			entry: &HostEntry{
@@ -132,6 +139,15 @@ func TestHostEntry_GetSSHCommand(t *testing.T) {
 			},
 			want: "ssh example.com",
 		},
+		{
+			name: "with explicit default port",
+			entry: &HostEntry{
+				HostName: "example.com",
+				User:     "root",
+				Port:     "22",
+			},
+			want: "ssh root@example.com",
+		},
 		{
 			name: "with user and custom port",
 			entry: &HostEntry{
@@ -141,6 +157,23 @@ func TestHostEntry_GetSSHCommand(t *testing.T) {
 			},
 			want: "ssh -p 22000 admin@192.168.1.1",
 		},
+		{
+			name: "IPv6 without port",
+			entry: &HostEntry{
+				HostName: "2001:db8::1",
+				User:     "admin",
+			},
+			want: "ssh admin@2001:db8::1",
+		},
+		{
+			name: "IPv6 with port",
+			entry: &HostEntry{
+				HostName: "2001:db8::1",
+				User:     "admin",
+				Port:     "2222",
+			},
+			want: "ssh -p 2222 admin@2001:db8::1",
+		},
 	}
 
 	for _, tt := range tests {
@@ -151,3 +184,149 @@ func TestHostEntry_GetSSHCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestHostEntry_GetAddress(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry *HostEntry
+		want  string
+	}{
+		{"default port", &HostEntry{HostName: "example.com"}, "example.com"},
+		{"custom port", &HostEntry{HostName: "example.com", Port: "2222"}, "example.com:2222"},
+		{"IPv4 with port", &HostEntry{HostName: "10.0.0.1", Port: "2222"}, "10.0.0.1:2222"},
+		{"IPv6 without port", &HostEntry{HostName: "2001:db8::1"}, "2001:db8::1"},
+		{"IPv6 with port", &HostEntry{HostName: "2001:db8::1", Port: "2222"}, "[2001:db8::1]:2222"},
+		{"IPv6 with zone", &HostEntry{HostName: "fe80::1%eth0", Port: "2222"}, "[fe80::1%eth0]:2222"},
+		{"already bracketed", &HostEntry{HostName: "[2001:db8::1]", Port: "2222"}, "[2001:db8::1]:2222"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.GetAddress(); got != tt.want {
+				t.Errorf("HostEntry.GetAddress() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsIPv6(t *testing.T) {
+	for host, want := range map[string]bool{
+		"2001:db8::1":   true,
+		"::1":           true,
+		"[::1]":         true,
+		"fe80::1%eth0":  true,
+		"192.168.1.1":   false,
+		"example.com":   false,
+		"host:with:col": false,
+	} {
+		if got := IsIPv6(host); got != want {
+			t.Errorf("IsIPv6(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestUniqueTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"nil", nil, nil},
+		{"no duplicates", []string{"prod", "web"}, []string{"prod", "web"}},
+		{"consecutive duplicates", []string{"prod", "prod"}, []string{"prod"}},
+		{"case-insensitive", []string{"Prod", "web", "prod"}, []string{"Prod", "web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UniqueTags(tt.tags)
+			if len(got) != len(tt.want) {
+				t.Fatalf("UniqueTags(%v) = %v, want %v", tt.tags, got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("UniqueTags(%v) = %v, want %v", tt.tags, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestHostEntry_IsDefaultPort(t *testing.T) {
+	tests := []struct {
+		port string
+		want bool
+	}{
+		{"", true},
+		{"22", true},
+		{"2222", false},
+		{"443", false},
+	}
+
+	for _, tt := range tests {
+		entry := &HostEntry{Host: "example", HostName: "example.com", Port: tt.port}
+		if got := entry.IsDefaultPort(); got != tt.want {
+			t.Errorf("IsDefaultPort() with Port %q = %v, want %v", tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestHostEntry_PrimaryAlias(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "web", want: "web"},
+		{host: "web1 web2 web-prod", want: "web1"},
+		{host: "  web1\tweb2 ", want: "web1"},
+		{host: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		entry := &HostEntry{Host: tt.host}
+		if got := entry.PrimaryAlias(); got != tt.want {
+			t.Errorf("PrimaryAlias(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestHostEntry_IsPattern(t *testing.T) {
+	tests := map[string]bool{
+		"*":               true,
+		"*.internal":      true,
+		"web-??":          true,
+		"!prod-* staging": true,
+		"prod":            false,
+		"prod prod-alias": false,
+	}
+	for host, want := range tests {
+		entry := &HostEntry{Host: host}
+		if got := entry.IsPattern(); got != want {
+			t.Errorf("IsPattern(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestFindEntry(t *testing.T) {
+	entries := []*HostEntry{
+		{Host: "web web2"},
+		{Host: "web"},
+		{Host: "DB"},
+	}
+
+	tests := map[string]int{
+		"web":       1, // Exact match wins over the alias of the first entry
+		"web2":      0,
+		"WEB2":      0,
+		"web  web2": 0,
+		"Web Web2":  0,
+		"db":        2,
+		"cache":     -1,
+		"":          -1,
+	}
+	for host, want := range tests {
+		if got := FindEntry(entries, host); got != want {
+			t.Errorf("FindEntry(%q) = %d, want %d", host, got, want)
+		}
+	}
+}
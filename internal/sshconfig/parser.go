@@ -2,7 +2,9 @@ package sshconfig
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +12,12 @@ import (
 
 const (
 	sshConfigPath = "~/.ssh/config"
+
+	// maxLineLength is the longest config line ParseConfig will accept
+	maxLineLength = 1024 * 1024
+
+	// projectConfigDir holds a project-local config (<project>/.gosshit/config)
+	projectConfigDir = ".gosshit"
 )
 
 // GetSSHConfigPath returns the expanded path to the SSH config file
@@ -21,13 +29,57 @@ func GetSSHConfigPath() string {
 	return filepath.Join(homeDir, ".ssh", "config")
 }
 
-// ParseConfig reads and parses the SSH config file, returning a list of HostEntry
+// FindProjectConfig walks up from dir looking for a project-local
+// .gosshit/config file, returning its path or "" if there is none
+func FindProjectConfig(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		candidate := filepath.Join(dir, projectConfigDir, "config")
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ParseConfig reads and parses the SSH config file, returning a list of HostEntry.
+// Include directives are followed, so hosts from included files are returned too
+// (each with SourceFile set to the file it came from). The standalone comments
+// are those of the top-level file only.
 func ParseConfig(path string) ([]*HostEntry, []string, error) {
+	return parseFile(path, map[string]bool{})
+}
+
+// parseSingleFile parses one config file without following Include directives.
+// Writers use it so that rewriting a file never pulls in hosts from other files.
+func parseSingleFile(path string) ([]*HostEntry, []string, error) {
+	return parseFile(path, nil)
+}
+
+// pendingInclude is an Include directive whose files are parsed after the
+// current file, then spliced into the entries at insertAt
+type pendingInclude struct {
+	patterns []string
+	insertAt int
+}
+
+// parseFile parses a single config file. If visited is non-nil, Include
+// directives are followed recursively; visited guards against include cycles.
+func parseFile(path string, visited map[string]bool) ([]*HostEntry, []string, error) {
 	// Expand tilde in path
 	if strings.HasPrefix(path, "~") {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get home directory: %w", err)
+			return nil, nil, &ParseError{Path: path, Reason: "failed to get home directory", Err: err}
 		}
 		path = strings.Replace(path, "~", homeDir, 1)
 	}
@@ -38,25 +90,71 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 			// Return empty list if file doesn't exist
 			return []*HostEntry{}, []string{}, nil
 		}
-		return nil, nil, fmt.Errorf("failed to open config file: %w", err)
+		return nil, nil, &ParseError{Path: path, Reason: "failed to open config file", Err: err}
 	}
 	defer file.Close()
 
+	entries, standaloneComments, err := parseReader(file, path, visited, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return validEntries(entries), standaloneComments, nil
+}
+
+// ParseReader parses config text from r, e.g. a config piped on stdin. name
+// identifies the input in errors and SourceFile. Include directives are not
+// followed.
+func ParseReader(r io.Reader, name string) ([]*HostEntry, []string, error) {
+	entries, standaloneComments, err := parseReader(r, name, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return validEntries(entries), standaloneComments, nil
+}
+
+// validEntries returns the entries that pass IsValid (e.g. dropping a host
+// without HostName)
+func validEntries(entries []*HostEntry) []*HostEntry {
+	valid := make([]*HostEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsValid() {
+			valid = append(valid, entry)
+		}
+	}
+	return valid
+}
+
+// parseReader parses the config read from r; path is used in errors, as the
+// entries' SourceFile and to resolve relative Includes. Every Host block is
+// returned, including ones that fail IsValid. If visited is non-nil, Include
+// directives are followed recursively. If problems is non-nil, lines the
+// parser skips (e.g. a directive without a value) are reported there.
+func parseReader(r io.Reader, path string, visited map[string]bool, problems *[]Problem) ([]*HostEntry, []string, error) {
 	var entries []*HostEntry
 	var standaloneComments []string
 	var currentEntry *HostEntry
 	var commentBuffer []string
 	var rawLines []string
 	var currentHostLines []string
+	var includes []pendingInclude
 	lineNum := 0
 	inHostBlock := false
+	inMatchBlock := false // A Match block after the current host's directives
+	seenHost := false
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
+	// Allow long lines (e.g. huge ProxyCommand values) beyond bufio's 64KB default
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
 	for scanner.Scan() {
 		lineNum++
-		line := scanner.Text()
+		// Configs edited on Windows may use CRLF; the writer restores it
+		line := strings.TrimSuffix(scanner.Text(), "\r")
 		rawLines = append(rawLines, line)
 
+		if strings.ContainsRune(line, 0) {
+			return nil, nil, &ParseError{Path: path, Line: lineNum, Reason: "config appears to be binary, not a text SSH config"}
+		}
+
 		trimmed := strings.TrimSpace(line)
 
 		// Handle comments
@@ -70,11 +168,10 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 					if currentEntry != nil {
 						currentEntry.RawLines = currentHostLines
 						currentEntry.EndLine = lineNum - 1
-						if currentEntry.IsValid() {
-							entries = append(entries, currentEntry)
-						}
+						entries = append(entries, currentEntry)
 					}
 					inHostBlock = false
+					inMatchBlock = false
 					currentEntry = nil
 					currentHostLines = []string{}
 				}
@@ -99,6 +196,14 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 				if currentEntry != nil {
 					currentEntry.Comment += line + "\n"
 				}
+			} else if !seenHost && len(commentBuffer) > 0 && !hasMetadataComment(commentBuffer) {
+				// A comment block at the top of the file followed by a blank line
+				// is a file header, not a host description
+				if len(standaloneComments) > 0 {
+					standaloneComments = append(standaloneComments, "")
+				}
+				standaloneComments = append(standaloneComments, commentBuffer...)
+				commentBuffer = []string{}
 			} else {
 				// Keep empty lines in comment buffer - they might be between comment and Host
 				// Don't clear the buffer yet - wait for next non-empty, non-comment line
@@ -106,12 +211,17 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 			continue
 		}
 
-		// Parse directives
-		parts := strings.Fields(trimmed)
+		// Parse directives, ignoring any trailing "# comment" (it stays in
+		// the raw lines)
+		directiveText := stripInlineComment(trimmed)
+		parts := strings.Fields(directiveText)
 		if len(parts) < 2 {
 			if inHostBlock {
 				currentHostLines = append(currentHostLines, line)
 			}
+			if problems != nil {
+				*problems = append(*problems, Problem{Line: lineNum, Message: fmt.Sprintf("%s has no value, line ignored: %q", parts[0], trimmed)})
+			}
 			continue
 		}
 
@@ -124,13 +234,13 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 			if currentEntry != nil && inHostBlock {
 				currentEntry.RawLines = currentHostLines
 				currentEntry.EndLine = lineNum - 1
-				if currentEntry.IsValid() {
-					entries = append(entries, currentEntry)
-				}
+				entries = append(entries, currentEntry)
 			}
 
 			// Start new entry
 			inHostBlock = true
+			inMatchBlock = false
+			seenHost = true
 			currentHostLines = []string{}
 
 			// Add comment buffer to new entry (excluding trailing empty lines)
@@ -142,6 +252,7 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 
 			// Extract description and tags from comment buffer
 			desc := ""
+			logs := ""
 			var tags []string
 			for _, c := range commentBuffer {
 				trimmed := strings.TrimSpace(c)
@@ -162,6 +273,10 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 							}
 						}
 					}
+				} else if strings.HasPrefix(trimmed, "# Logs:") {
+					// Remote command for tailing logs
+					logs = strings.TrimPrefix(trimmed, "# Logs:")
+					logs = strings.TrimSpace(logs)
 				} else if strings.HasPrefix(trimmed, "# Description:") {
 					// Explicit description format
 					desc = strings.TrimPrefix(trimmed, "# Description:")
@@ -178,7 +293,8 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 			currentEntry = &HostEntry{
 				Host:        value,
 				Description: desc,
-				Tags:        tags,
+				Tags:        UniqueTags(tags),
+				LogsCommand: logs,
 				StartLine:   lineNum,
 				RawLines:    make([]string, 0),
 			}
@@ -200,6 +316,25 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 			commentBuffer = []string{}
 		}
 
+		// Remember Include directives so the referenced files can be parsed
+		// and their hosts placed where the Include appears
+		if directive == "include" {
+			insertAt := len(entries)
+			if inHostBlock && currentEntry != nil {
+				insertAt++ // after the current host, once it's saved
+			}
+			includes = append(includes, pendingInclude{patterns: parts[1:], insertAt: insertAt})
+		}
+
+		// A Match block ends the host's own directives. It's kept verbatim in
+		// the host's raw lines (and written back after them) rather than being
+		// read into the host's fields or extra directives.
+		if inHostBlock && currentEntry != nil && (inMatchBlock || directive == "match") {
+			inMatchBlock = true
+			currentHostLines = append(currentHostLines, line)
+			continue
+		}
+
 		// Handle other directives within a host block
 		if inHostBlock && currentEntry != nil {
 			currentHostLines = append(currentHostLines, line)
@@ -212,13 +347,35 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 				currentEntry.Port = value
 			case "identityfile":
 				currentEntry.IdentityFile = value
+			case "proxyjump":
+				currentEntry.ProxyJump = value
+			case "proxycommand":
+				currentEntry.ProxyCommand = rawDirectiveValue(directiveText)
+			case "forwardagent":
+				currentEntry.ForwardAgent = value
+			case "localforward":
+				currentEntry.LocalForward = append(currentEntry.LocalForward, value)
+			case "remoteforward":
+				currentEntry.RemoteForward = append(currentEntry.RemoteForward, value)
+			case "setenv":
+				currentEntry.SetEnv = append(currentEntry.SetEnv, rawDirectiveValue(directiveText))
+			case "addkeystoagent":
+				currentEntry.AddKeysToAgent = value
+			case "usekeychain":
+				currentEntry.UseKeychain = value
+			case "identitiesonly":
+				currentEntry.IdentitiesOnly = value
+			default:
+				currentEntry.ExtraDirectives = append(currentEntry.ExtraDirectives, directiveText)
 			}
 		} else {
-			// Directive outside host block - treat as standalone
+			// Directive outside host block (e.g. a top-level Include) - keep it
+			// with the standalone lines so it's written back at the top
 			if len(commentBuffer) > 0 {
 				standaloneComments = append(standaloneComments, commentBuffer...)
 				commentBuffer = []string{}
 			}
+			standaloneComments = append(standaloneComments, line)
 		}
 	}
 
@@ -226,9 +383,7 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 	if currentEntry != nil && inHostBlock {
 		currentEntry.RawLines = currentHostLines
 		currentEntry.EndLine = lineNum
-		if currentEntry.IsValid() {
-			entries = append(entries, currentEntry)
-		}
+		entries = append(entries, currentEntry)
 	}
 
 	// Add any remaining standalone comments
@@ -237,8 +392,102 @@ func ParseConfig(path string) ([]*HostEntry, []string, error) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, nil, fmt.Errorf("error reading config file: %w", err)
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, nil, &ParseError{Path: path, Line: lineNum + 1, Reason: fmt.Sprintf("config appears malformed: line longer than %d bytes", maxLineLength)}
+		}
+		return nil, nil, &ParseError{Path: path, Line: lineNum + 1, Reason: "error reading config file", Err: err}
+	}
+
+	for _, entry := range entries {
+		entry.SourceFile = path
+	}
+
+	if visited != nil {
+		visited[path] = true
+		// Splice included entries in reverse so earlier insert positions stay valid
+		for i := len(includes) - 1; i >= 0; i-- {
+			included, err := parseIncludes(path, includes[i].patterns, visited)
+			if err != nil {
+				return nil, nil, err
+			}
+			at := min(includes[i].insertAt, len(entries))
+			entries = append(entries[:at], append(included, entries[at:]...)...)
+		}
 	}
 
 	return entries, standaloneComments, nil
 }
+
+// parseIncludes expands Include patterns (globs, relative to the directory of
+// the including file) and parses each matching file
+func parseIncludes(parentPath string, patterns []string, visited map[string]bool) ([]*HostEntry, error) {
+	var entries []*HostEntry
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "~") {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return nil, &ParseError{Path: parentPath, Reason: "failed to get home directory", Err: err}
+			}
+			pattern = strings.Replace(pattern, "~", homeDir, 1)
+		}
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(parentPath), pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, &ParseError{Path: parentPath, Reason: "invalid Include pattern " + pattern, Err: err}
+		}
+		// Glob returns matches in lexical order, like ssh does
+
+		for _, match := range matches {
+			if visited[match] {
+				continue
+			}
+			if info, err := os.Stat(match); err != nil || info.IsDir() {
+				continue
+			}
+			included, _, err := parseFile(match, visited)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, included...)
+		}
+	}
+	return entries, nil
+}
+
+// stripInlineComment removes a trailing comment from a directive line, e.g.
+// "Port 2222 # non-standard" becomes "Port 2222". A "#" only starts a comment
+// after whitespace and outside double quotes.
+func stripInlineComment(line string) string {
+	inQuotes := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == '#' && !inQuotes && i > 0 && (line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return line
+}
+
+// rawDirectiveValue returns everything after the directive keyword, with the
+// internal spacing kept - ProxyCommand is a shell command line, so collapsing
+// its whitespace could change what it does
+func rawDirectiveValue(directiveText string) string {
+	keyword := strings.Fields(directiveText)[0]
+	return strings.TrimSpace(strings.TrimSpace(directiveText)[len(keyword):])
+}
+
+// hasMetadataComment reports whether a comment block contains gosshit metadata
+// (Description, Tags, ...) and therefore belongs to the following Host
+func hasMetadataComment(lines []string) bool {
+	for _, l := range lines {
+		if isMetadataComment(strings.TrimSpace(l)) {
+			return true
+		}
+	}
+	return false
+}
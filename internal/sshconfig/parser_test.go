@@ -1,8 +1,10 @@
 package sshconfig
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -198,3 +200,313 @@ func TestParseConfig_PreservesRawLines(t *testing.T) {
 		t.Error("RawLines should contain 'Host example'")
 	}
 }
+
+func TestParseConfig_LogsCommand(t *testing.T) {
+	configContent := `# Description: Web server
+# Logs: tail -f /var/log/nginx/error.log
+Host web
+    HostName web.example.com
+
+Host db
+    HostName db.example.com
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	if entries[0].Description != "Web server" {
+		t.Errorf("Description: got %q, want %q", entries[0].Description, "Web server")
+	}
+	if got := entries[0].GetLogsCommand(); got != "tail -f /var/log/nginx/error.log" {
+		t.Errorf("web logs command: got %q, want %q", got, "tail -f /var/log/nginx/error.log")
+	}
+	if got := entries[1].GetLogsCommand(); got != DefaultLogsCommand {
+		t.Errorf("db logs command: got %q, want %q", got, DefaultLogsCommand)
+	}
+}
+
+func TestParseConfig_MatchBlockAfterHost(t *testing.T) {
+	configContent := `Host web
+    HostName web.example.com
+
+Match host *.internal
+    User admin
+    Compression yes
+
+Host db
+    HostName db.example.com
+`
+	configPath := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Host != "db" || entries[1].HostName != "db.example.com" {
+		t.Fatalf("Expected web and db, got %d entries", len(entries))
+	}
+	web := entries[0]
+	if web.User != "" || len(web.ExtraDirectives) != 0 {
+		t.Errorf("Match block directives should not be the host's: User %q, extras %q", web.User, web.ExtraDirectives)
+	}
+
+	// Rewriting keeps the Match block where it was
+	if err := WriteConfig(configPath, entries, nil); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != configContent {
+		t.Errorf("Round trip changed the config:\n%s", data)
+	}
+}
+
+func TestParseConfig_PatternEntries(t *testing.T) {
+	configContent := `Host *.internal !bastion.internal
+    User ops
+    ProxyJump bastion.internal
+
+Host web
+    HostName web.example.com
+`
+	configPath := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected the pattern entry to be kept, got %d entries", len(entries))
+	}
+	if !entries[0].IsPattern() || entries[0].User != "ops" {
+		t.Errorf("Unexpected pattern entry: %+v", entries[0])
+	}
+
+	// Rewriting the config keeps the pattern block
+	if err := UpdateEntry(configPath, "web", &HostEntry{Host: "web", HostName: "web2.example.com"}); err != nil {
+		t.Fatalf("UpdateEntry failed: %v", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if !strings.Contains(string(data), "Host *.internal !bastion.internal\n    User ops\n") {
+		t.Errorf("Pattern block lost on rewrite:\n%s", data)
+	}
+}
+
+func TestParseConfig_InlineComments(t *testing.T) {
+	configContent := `Host web # frontend
+    HostName example.com # primary
+    Port 2222	# non-standard
+    ProxyCommand sh -c "nc %h %p #not-a-comment"
+    Compression yes # faster
+`
+	configPath := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Host != "web" || entry.HostName != "example.com" || entry.Port != "2222" {
+		t.Errorf("Inline comments should be stripped, got Host %q HostName %q Port %q", entry.Host, entry.HostName, entry.Port)
+	}
+	if entry.ProxyCommand != `sh -c "nc %h %p #not-a-comment"` {
+		t.Errorf("ProxyCommand: got %q", entry.ProxyCommand)
+	}
+	if len(entry.ExtraDirectives) != 1 || entry.ExtraDirectives[0] != "Compression yes" {
+		t.Errorf("ExtraDirectives: got %q", entry.ExtraDirectives)
+	}
+
+	// Unchanged values keep their comments; a changed value is rewritten
+	entry.User = "deploy"
+	entry.Port = "2200"
+	if err := UpdateEntry(configPath, "web", entry); err != nil {
+		t.Fatalf("UpdateEntry failed: %v", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	for _, want := range []string{"Host web # frontend\n", "    HostName example.com # primary\n", "    Port 2200\n", "    Compression yes # faster\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %q in rewritten config:\n%s", want, data)
+		}
+	}
+}
+
+func TestFindProjectConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	nestedDir := filepath.Join(projectDir, "src", "app")
+
+	if err := os.MkdirAll(filepath.Join(projectDir, ".gosshit"), 0755); err != nil {
+		t.Fatalf("Failed to create project dir: %v", err)
+	}
+	if err := os.MkdirAll(nestedDir, 0755); err != nil {
+		t.Fatalf("Failed to create nested dir: %v", err)
+	}
+
+	// No config file yet
+	if got := FindProjectConfig(nestedDir); got != "" {
+		t.Errorf("Expected no project config, got %q", got)
+	}
+
+	configPath := filepath.Join(projectDir, ".gosshit", "config")
+	if err := os.WriteFile(configPath, []byte("Host example\n    HostName example.com\n"), 0644); err != nil {
+		t.Fatalf("Failed to create project config: %v", err)
+	}
+
+	if got := FindProjectConfig(nestedDir); got != configPath {
+		t.Errorf("From nested dir: got %q, want %q", got, configPath)
+	}
+	if got := FindProjectConfig(projectDir); got != configPath {
+		t.Errorf("From project dir: got %q, want %q", got, configPath)
+	}
+	if got := FindProjectConfig(tmpDir); got != "" {
+		t.Errorf("From parent of project: expected no config, got %q", got)
+	}
+}
+
+func TestParseConfig_DuplicateTags(t *testing.T) {
+	configContent := `# Tags: prod, prod, web
+Host example
+    HostName example.com
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if len(entries[0].Tags) != 2 || entries[0].Tags[0] != "prod" || entries[0].Tags[1] != "web" {
+		t.Errorf("Tags: got %v, want [prod web]", entries[0].Tags)
+	}
+}
+
+func TestParseConfig_LongLine(t *testing.T) {
+	// Longer than bufio.Scanner's default 64KB token limit
+	longValue := strings.Repeat("x", 200*1024)
+	configContent := "Host example\n    HostName example.com\n    ProxyCommand " + longValue + "\n\nHost other\n    HostName other.com\n"
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed on long line: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(entries))
+	}
+}
+
+func TestParseConfig_BinaryFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte("Host example\n\x7fELF\x02\x01\x01\x00\x00\x00\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	_, _, err := ParseConfig(configPath)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected ParseError, got %v", err)
+	}
+	if parseErr.Line != 2 {
+		t.Errorf("ParseError line: got %d, want 2", parseErr.Line)
+	}
+}
+
+func TestParseConfig_Include(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+	confDir := filepath.Join(tmpDir, "config.d")
+
+	if err := os.MkdirAll(confDir, 0755); err != nil {
+		t.Fatalf("Failed to create config.d: %v", err)
+	}
+
+	mainContent := `Include config.d/*.conf
+
+Host main
+    HostName main.example.com
+`
+	files := map[string]string{
+		configPath:                         mainContent,
+		filepath.Join(confDir, "a.conf"):   "Host alpha\n    HostName alpha.example.com\n",
+		filepath.Join(confDir, "b.conf"):   "Host beta\n    HostName beta.example.com\n    Include ../config\n",
+		filepath.Join(confDir, "skip.txt"): "Host skipped\n    HostName skipped.example.com\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	entries, comments, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	wantHosts := []string{"alpha", "beta", "main"}
+	wantSources := []string{
+		filepath.Join(confDir, "a.conf"),
+		filepath.Join(confDir, "b.conf"),
+		configPath,
+	}
+	if len(entries) != len(wantHosts) {
+		t.Fatalf("Expected %d entries, got %d", len(wantHosts), len(entries))
+	}
+	for i := range wantHosts {
+		if entries[i].Host != wantHosts[i] {
+			t.Errorf("Entry %d: got host %q, want %q", i, entries[i].Host, wantHosts[i])
+		}
+		if entries[i].SourceFile != wantSources[i] {
+			t.Errorf("Entry %d: got source %q, want %q", i, entries[i].SourceFile, wantSources[i])
+		}
+	}
+
+	// The top-level Include line is kept so it is written back
+	if len(comments) != 1 || comments[0] != "Include config.d/*.conf" {
+		t.Errorf("Standalone lines: got %q, want the Include line", comments)
+	}
+}
@@ -0,0 +1,78 @@
+package sshconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Snapshot is the exact content of a single config file, taken before a
+// change so the file can be restored later
+type Snapshot struct {
+	Path    string
+	content string // The file's bytes before the change ("" if it didn't exist)
+
+	// The file as the change left it, recorded by Written
+	written bool
+	size    int64
+	modTime time.Time
+}
+
+// TakeSnapshot reads path's raw content into a Snapshot. A missing file gives
+// an empty snapshot, so restoring it empties the file.
+func TakeSnapshot(path string) (*Snapshot, error) {
+	resolved, err := resolveConfigPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(resolved)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return &Snapshot{Path: path, content: string(data)}, nil
+}
+
+// Written records the file as the change left it, so Restore can tell if it
+// was modified since
+func (s *Snapshot) Written() error {
+	info, err := s.stat()
+	if err != nil {
+		return err
+	}
+	s.written = true
+	s.size = info.Size()
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// Check returns ErrConfigChanged if the file's size or modification time
+// differs from what Written recorded, i.e. it was edited outside gosshit
+func (s *Snapshot) Check() error {
+	if !s.written {
+		return errors.New("the change was never recorded as written")
+	}
+	info, err := s.stat()
+	if err != nil || info.Size() != s.size || !info.ModTime().Equal(s.modTime) {
+		return fmt.Errorf("%w: %s", ErrConfigChanged, s.Path)
+	}
+	return nil
+}
+
+// Restore writes the snapshot's bytes back to its file, refusing with
+// ErrConfigChanged if the file changed after the recorded write
+func (s *Snapshot) Restore() error {
+	if err := s.Check(); err != nil {
+		return err
+	}
+	return replaceConfigFile(s.Path, s.content, false)
+}
+
+// stat returns the file info of the (symlink-resolved) snapshot path
+func (s *Snapshot) stat() (os.FileInfo, error) {
+	resolved, err := resolveConfigPath(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(resolved)
+}
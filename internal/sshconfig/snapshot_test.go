@@ -0,0 +1,71 @@
+package sshconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshot_RestoresDeletedEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	original := "# My hosts\r\n\n#comment  with   odd spacing\n\n\n# Description: Web server\nHost web\n    HostName web.example.com\n    ServerAliveInterval 30\n\nHost db\n    HostName db.example.com\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	snapshot, err := TakeSnapshot(path)
+	if err != nil {
+		t.Fatalf("TakeSnapshot failed: %v", err)
+	}
+
+	if err := DeleteEntry(path, "web"); err != nil {
+		t.Fatalf("DeleteEntry failed: %v", err)
+	}
+	if err := snapshot.Written(); err != nil {
+		t.Fatalf("Written failed: %v", err)
+	}
+	if err := snapshot.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if string(data) != original {
+		t.Errorf("Restored config differs\ngot:\n%s\nwant:\n%s", data, original)
+	}
+}
+
+func TestSnapshot_RefusesAfterOutsideEdit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	original := "Host web\n    HostName web.example.com\n\nHost db\n    HostName db.example.com\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	snapshot, err := TakeSnapshot(path)
+	if err != nil {
+		t.Fatalf("TakeSnapshot failed: %v", err)
+	}
+	if err := DeleteEntry(path, "web"); err != nil {
+		t.Fatalf("DeleteEntry failed: %v", err)
+	}
+	if err := snapshot.Written(); err != nil {
+		t.Fatalf("Written failed: %v", err)
+	}
+
+	// Edited in another terminal after the change
+	edited := "Host db\n    HostName db.example.com\n    User admin\n"
+	if err := os.WriteFile(path, []byte(edited), 0600); err != nil {
+		t.Fatalf("Failed to edit config: %v", err)
+	}
+
+	if err := snapshot.Restore(); !errors.Is(err, ErrConfigChanged) {
+		t.Fatalf("Expected ErrConfigChanged, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != edited {
+		t.Errorf("The outside edit should be kept, got:\n%s", data)
+	}
+}
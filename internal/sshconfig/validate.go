@@ -0,0 +1,48 @@
+package sshconfig
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Problem is a structural issue found by ValidateConfig. Line is the 1-based
+// line of the Host block it concerns.
+type Problem struct {
+	Line    int
+	Message string
+}
+
+// ValidateConfig parses the config read from r and reports structural
+// problems: lines the parser would skip, an alias defined by more than one
+// Host block (ssh only uses the first) and non-pattern hosts without HostName
+// (gosshit skips them). Problems are sorted by line; name identifies the
+// input in errors. Include directives are not followed.
+func ValidateConfig(r io.Reader, name string) ([]Problem, int, error) {
+	var problems []Problem
+	entries, _, err := parseReader(r, name, nil, &problems)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	firstLine := make(map[string]int) // Lowercased alias -> line of the first Host defining it
+	for _, entry := range entries {
+		if !entry.IsValid() {
+			problems = append(problems, Problem{Line: entry.StartLine, Message: fmt.Sprintf("Host %s has no HostName", entry.Host)})
+		}
+		for _, alias := range entry.Aliases() {
+			if strings.ContainsAny(alias, "*?!") {
+				continue // Repeating a pattern is normal (e.g. several Host * blocks)
+			}
+			key := strings.ToLower(alias)
+			if line, ok := firstLine[key]; ok {
+				problems = append(problems, Problem{Line: entry.StartLine, Message: fmt.Sprintf("duplicate alias %q (first defined on line %d)", alias, line)})
+				continue
+			}
+			firstLine[key] = entry.StartLine
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+	return problems, len(entries), nil
+}
@@ -0,0 +1,87 @@
+package sshconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	config := `Host web
+    HostName web.example.com
+
+Host nohostname
+    User deploy
+
+Host WEB db
+    HostName db.example.com
+
+Host *
+    ServerAliveInterval 30
+
+Host *
+    User root
+`
+	problems, hosts, err := ValidateConfig(strings.NewReader(config), "<stdin>")
+	if err != nil {
+		t.Fatalf("ValidateConfig failed: %v", err)
+	}
+	if hosts != 5 {
+		t.Errorf("Expected 5 Host blocks, got %d", hosts)
+	}
+	want := []Problem{
+		{Line: 4, Message: "Host nohostname has no HostName"},
+		{Line: 7, Message: `duplicate alias "WEB" (first defined on line 1)`},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("Problems = %+v, want %+v", problems, want)
+	}
+
+	problems, _, err = ValidateConfig(strings.NewReader("Host ok\n    HostName ok.example.com\n"), "<stdin>")
+	if err != nil || len(problems) != 0 {
+		t.Errorf("Expected no problems, got %+v (err %v)", problems, err)
+	}
+}
+
+func TestValidateConfig_SkippedLines(t *testing.T) {
+	config := `Host web
+    HostName web.example.com
+    Compression
+    User deploy # comment only after the directive
+
+Host
+`
+	problems, _, err := ValidateConfig(strings.NewReader(config), "<stdin>")
+	if err != nil {
+		t.Fatalf("ValidateConfig failed: %v", err)
+	}
+	want := []Problem{
+		{Line: 3, Message: `Compression has no value, line ignored: "Compression"`},
+		{Line: 6, Message: `Host has no value, line ignored: "Host"`},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("Problems = %+v, want %+v", problems, want)
+	}
+
+	// Parsing outside validation still skips them silently
+	entries, _, err := ParseReader(strings.NewReader(config), "<stdin>")
+	if err != nil || len(entries) != 1 || entries[0].User != "deploy" {
+		t.Errorf("Expected the web entry, got %+v (err %v)", entries, err)
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	config := "Include other.conf\n\nHost web\n    HostName web.example.com\n\nHost broken\n    User deploy\n"
+	entries, _, err := ParseReader(strings.NewReader(config), "<stdin>")
+	if err != nil {
+		t.Fatalf("ParseReader failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Host != "web" || entries[0].SourceFile != "<stdin>" {
+		t.Errorf("Expected only the valid web entry, got %+v", entries)
+	}
+
+	_, _, err = ParseReader(strings.NewReader("Host bin\n    HostName \x00\n"), "<stdin>")
+	if err == nil || !strings.Contains(err.Error(), "<stdin>:2:") {
+		t.Errorf("Expected an error naming the input and line, got %v", err)
+	}
+}
@@ -2,102 +2,327 @@ package sshconfig
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
-// WriteConfig writes the SSH config file with the given entries and standalone comments
+// WriteConfig writes the SSH config file with the given entries and standalone comments.
+// The file is replaced atomically (written to a temp file, then renamed over
+// the target) and the previous version is kept as <path>.bak.
 func WriteConfig(path string, entries []*HostEntry, standaloneComments []string) error {
-	// Expand tilde in path
+	content, err := RenderConfig(entries, standaloneComments)
+	if err != nil {
+		return &WriteError{Path: path, Op: "failed to render config", Err: err}
+	}
+	return writeConfigFile(path, content)
+}
+
+// RenderConfig returns the config file content WriteConfig would write for
+// the given entries and standalone comments
+func RenderConfig(entries []*HostEntry, standaloneComments []string) (string, error) {
+	var b strings.Builder
+
+	// Write standalone comments at the top
+	if len(standaloneComments) > 0 {
+		for _, comment := range standaloneComments {
+			b.WriteString(comment + "\n")
+		}
+		if len(entries) > 0 {
+			b.WriteString("\n")
+		}
+	}
+
+	// Write entries
+	for i, entry := range entries {
+		if err := writeEntry(&b, entry); err != nil {
+			return "", err
+		}
+		// Separate entries (except after the last one) by the blank lines
+		// that followed them in the original file
+		if i < len(entries)-1 {
+			b.WriteString(strings.Repeat("\n", entrySpacing(entry)))
+		}
+	}
+
+	return b.String(), nil
+}
+
+// entrySpacing returns the number of blank lines to write after entry: the
+// trailing blank lines of its RawLines, or one for new entries and entries
+// that were directly followed by the next Host line
+func entrySpacing(entry *HostEntry) int {
+	blank := 0
+	for i := len(entry.RawLines) - 1; i >= 0 && strings.TrimSpace(entry.RawLines[i]) == ""; i-- {
+		blank++
+	}
+	return max(blank, 1)
+}
+
+// resolveConfigPath expands a leading tilde and follows symlinks (e.g. a
+// config managed in a dotfiles repo) so the link target is written instead
+// of the link itself
+func resolveConfigPath(path string) (string, error) {
 	if strings.HasPrefix(path, "~") {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return fmt.Errorf("failed to get home directory: %w", err)
+			return "", &WriteError{Path: path, Op: "failed to get home directory", Err: err}
 		}
 		path = strings.Replace(path, "~", homeDir, 1)
 	}
 
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return path, nil
+}
+
+// writeConfigFile atomically replaces the file at path with content, keeping
+// a copy of the previous version in path.bak. Content uses LF line endings
+// and is converted to CRLF if the existing file uses them.
+func writeConfigFile(path string, content string) error {
+	return replaceConfigFile(path, content, true)
+}
+
+// replaceConfigFile is writeConfigFile, optionally writing content byte for
+// byte (e.g. to restore a snapshot) instead of matching the line endings
+func replaceConfigFile(path string, content string, matchLineEndings bool) error {
+	path, err := resolveConfigPath(path)
+	if err != nil {
+		return err
+	}
+
 	// Ensure .ssh directory exists
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0700); err != nil {
-		return fmt.Errorf("failed to create .ssh directory: %w", err)
+		return &WriteError{Path: path, Op: "failed to create .ssh directory", Err: err}
+	}
+
+	// Keep the original file mode and line endings, defaulting to 0600 and
+	// LF for new files
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+		if existing, err := os.ReadFile(path); err == nil && matchLineEndings && usesCRLF(string(existing)) {
+			content = strings.ReplaceAll(content, "\n", "\r\n")
+		}
+		if err := backupFile(path, path+".bak", mode); err != nil {
+			return &WriteError{Path: path, Op: "failed to back up config file", Err: err}
+		}
 	}
 
-	file, err := os.Create(path)
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
 	if err != nil {
-		return fmt.Errorf("failed to create config file: %w", err)
+		return &WriteError{Path: path, Op: "failed to create config file", Err: err}
 	}
-	defer file.Close()
+	tmpPath := file.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			file.Close()
+			os.Remove(tmpPath)
+		}
+	}()
 
-	// Write standalone comments at the top
-	if len(standaloneComments) > 0 {
-		for _, comment := range standaloneComments {
-			if _, err := file.WriteString(comment + "\n"); err != nil {
-				return fmt.Errorf("failed to write comment: %w", err)
-			}
+	if _, err := file.WriteString(content); err != nil {
+		return &WriteError{Path: path, Op: "failed to write config file", Err: err}
+	}
+	if err := file.Chmod(mode); err != nil {
+		return &WriteError{Path: path, Op: "failed to set config file mode", Err: err}
+	}
+	if err := file.Sync(); err != nil {
+		return &WriteError{Path: path, Op: "failed to sync config file", Err: err}
+	}
+	if err := file.Close(); err != nil {
+		return &WriteError{Path: path, Op: "failed to close config file", Err: err}
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return &WriteError{Path: path, Op: "failed to replace config file", Err: err}
+	}
+	committed = true
+
+	return nil
+}
+
+// CheckWritable reports whether the config file at path can be saved: the
+// file itself must be writable, and so must its directory, since saves write
+// a temporary file next to it and rename it into place. A missing directory
+// is created on the first save, so it isn't an error here.
+func CheckWritable(path string) error {
+	if file, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+		file.Close()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
 		}
-		if len(entries) > 0 {
-			if _, err := file.WriteString("\n"); err != nil {
-				return fmt.Errorf("failed to write newline: %w", err)
-			}
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// usesCRLF reports whether most lines of content end in CRLF rather than LF
+func usesCRLF(content string) bool {
+	crlf := strings.Count(content, "\r\n")
+	return crlf > strings.Count(content, "\n")-crlf
+}
+
+// backupFile copies src to dst with the given mode, replacing any previous backup
+func backupFile(src, dst string, mode os.FileMode) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dst, data, mode); err != nil {
+		return err
+	}
+	// WriteFile only applies the mode when creating the file
+	return os.Chmod(dst, mode)
+}
+
+// isMetadataComment reports whether a trimmed comment line is one of the
+// gosshit-managed metadata comments (Description, Tags, Logs)
+func isMetadataComment(trimmed string) bool {
+	return strings.Contains(trimmed, "# Description:") ||
+		strings.Contains(trimmed, "# Tags:") ||
+		strings.Contains(trimmed, "# Logs:")
+}
+
+// writeMetadataComments writes the Description, Tags and Logs comments above a Host line
+func writeMetadataComments(file io.StringWriter, entry *HostEntry) error {
+	if entry.Description != "" {
+		if _, err := file.WriteString("# Description: " + entry.Description + "\n"); err != nil {
+			return err
 		}
 	}
 
-	// Write entries
-	for i, entry := range entries {
-		if err := writeEntry(file, entry); err != nil {
-			return fmt.Errorf("failed to write entry: %w", err)
+	if len(entry.Tags) > 0 {
+		tagsStr := strings.Join(entry.Tags, ", ")
+		if _, err := file.WriteString("# Tags: " + tagsStr + "\n"); err != nil {
+			return err
 		}
-		// Add single blank line between entries (except after the last one)
-		if i < len(entries)-1 {
-			if _, err := file.WriteString("\n"); err != nil {
-				return fmt.Errorf("failed to write newline: %w", err)
-			}
+	}
+
+	if entry.LogsCommand != "" {
+		if _, err := file.WriteString("# Logs: " + entry.LogsCommand + "\n"); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-// writeEntry writes a single host entry to the file
-func writeEntry(file *os.File, entry *HostEntry) error {
-	// If we have raw lines, try to preserve them (with updates)
-	if len(entry.RawLines) > 0 {
-		// Write description comment if we have one (always write it first, skip it in raw lines)
-		if entry.Description != "" {
-			if _, err := file.WriteString("# Description: " + entry.Description + "\n"); err != nil {
-				return err
-			}
+// writeDirectiveLine writes an optional directive line taken from RawLines.
+// The line is kept exactly as-is when the value is unchanged, rewritten with
+// the original indentation and directive case when it changed, and dropped
+// when the new value is empty.
+func writeDirectiveLine(file io.StringWriter, line, prefix, oldValue, newValue string) error {
+	if newValue == "" {
+		// Directive was removed, skip this line
+		return nil
+	}
+	if oldValue == newValue {
+		_, err := file.WriteString(line + "\n")
+		return err
+	}
+	_, err := file.WriteString(prefix + " " + newValue + "\n")
+	return err
+}
+
+// detectIndent returns the indentation used by most directive lines in
+// rawLines (ties go to the one seen first), so directives added to a block
+// that mixes tabs and spaces line up with the majority. Comments, blank lines
+// and the Host line don't count. Defaults to 4 spaces.
+func detectIndent(rawLines []string) string {
+	counts := make(map[string]int)
+	var order []string
+	for _, l := range rawLines {
+		trimmed := strings.TrimSpace(l)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.ToLower(strings.Fields(trimmed)[0]) == "host" {
+			continue
+		}
+		// Get the leading whitespace (preserves tabs/spaces)
+		leading := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+		if leading == "" {
+			continue
+		}
+		if counts[leading] == 0 {
+			order = append(order, leading)
 		}
+		counts[leading]++
+	}
 
-		// Write tags comment if we have any
-		if len(entry.Tags) > 0 {
-			tagsStr := strings.Join(entry.Tags, ", ")
-			if _, err := file.WriteString("# Tags: " + tagsStr + "\n"); err != nil {
-				return err
-			}
+	indent := "    " // default to 4 spaces
+	best := 0
+	for _, leading := range order {
+		if counts[leading] > best {
+			indent, best = leading, counts[leading]
 		}
+	}
+	return indent
+}
 
-		// Detect indentation style from the first non-empty, non-comment, non-Host line
-		indent := "    " // default to 4 spaces
-		for _, l := range entry.RawLines {
-			trimmed := strings.TrimSpace(l)
-			if trimmed != "" && !strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(strings.ToLower(trimmed), "host ") {
-				// Get the leading whitespace (preserves tabs/spaces)
-				leading := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
-				if len(leading) > 0 {
-					indent = leading
-					break
-				}
+// splitMatchLines splits a host's raw lines at a Match block that follows its
+// directives, keeping the blank lines and comments right before the Match
+// line with the block. match is empty when there is no Match block.
+func splitMatchLines(lines []string) (own, match []string) {
+	for i, line := range lines {
+		parts := strings.Fields(stripInlineComment(strings.TrimSpace(line)))
+		if len(parts) == 0 || !strings.EqualFold(parts[0], "match") {
+			continue
+		}
+		start := i
+		for start > 0 {
+			trimmed := strings.TrimSpace(lines[start-1])
+			if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+				break
 			}
+			start--
+		}
+		return lines[:start], lines[start:]
+	}
+	return lines, nil
+}
+
+// writeEntry writes a single host entry to the file
+func writeEntry(file io.StringWriter, entry *HostEntry) error {
+	// If we have raw lines, try to preserve them (with updates)
+	if len(entry.RawLines) > 0 {
+		// Write metadata comments first (always, skipping them in raw lines)
+		if err := writeMetadataComments(file, entry); err != nil {
+			return err
 		}
 
+		// Newly added directives use the block's dominant indentation
+		indent := detectIndent(entry.RawLines)
+
 		// Track which directives we've written
 		writtenHostname := false
 		writtenUser := false
 		writtenPort := false
 		writtenIdentityFile := false
+		writtenProxyJump := false
+		writtenProxyCommand := false
+		writtenForwardAgent := false
+		writtenAddKeysToAgent := false
+		writtenUseKeychain := false
+		writtenIdentitiesOnly := false
+		writtenLocalForwards := 0
+		writtenRemoteForwards := 0
+		writtenSetEnvs := 0
+		// Extra directives still to be written; lines already in RawLines are
+		// kept in place, the rest appended below
+		pendingExtras := make(map[string]int)
+		for _, extra := range entry.ExtraDirectives {
+			pendingExtras[extra]++
+		}
 
 		// Write raw lines, updating values as needed
 		// First, strip trailing empty lines from RawLines to prevent accumulation
@@ -105,7 +330,7 @@ func writeEntry(file *os.File, entry *HostEntry) error {
 		for lastNonEmpty >= 0 && strings.TrimSpace(entry.RawLines[lastNonEmpty]) == "" {
 			lastNonEmpty--
 		}
-		rawLinesToWrite := entry.RawLines[:lastNonEmpty+1]
+		rawLinesToWrite, matchLines := splitMatchLines(entry.RawLines[:lastNonEmpty+1])
 
 		for _, line := range rawLinesToWrite {
 			trimmed := strings.TrimSpace(line)
@@ -117,8 +342,8 @@ func writeEntry(file *os.File, entry *HostEntry) error {
 				continue
 			}
 			if strings.HasPrefix(trimmed, "#") {
-				// Skip description and tags comments as we write them explicitly above
-				if strings.Contains(trimmed, "# Description:") || strings.Contains(trimmed, "# Tags:") {
+				// Skip metadata comments as we write them explicitly above
+				if isMetadataComment(trimmed) {
 					continue
 				}
 				// Preserve other comments
@@ -128,7 +353,10 @@ func writeEntry(file *os.File, entry *HostEntry) error {
 				continue
 			}
 
-			parts := strings.Fields(trimmed)
+			// Values are compared without any inline comment, so the
+			// comment survives as long as the value is unchanged
+			directiveText := stripInlineComment(trimmed)
+			parts := strings.Fields(directiveText)
 			if len(parts) < 2 {
 				if _, err := file.WriteString(line + "\n"); err != nil {
 					return err
@@ -145,7 +373,7 @@ func writeEntry(file *os.File, entry *HostEntry) error {
 			// Update directives if they've changed, preserving original indentation and case
 			switch directive {
 			case "host":
-				if _, err := file.WriteString("Host " + entry.Host + "\n"); err != nil {
+				if err := writeDirectiveLine(file, line, "Host", strings.Join(parts[1:], " "), entry.Host); err != nil {
 					return err
 				}
 			case "hostname":
@@ -164,63 +392,89 @@ func writeEntry(file *os.File, entry *HostEntry) error {
 				}
 			case "user":
 				writtenUser = true
-				newValue := strings.Join(parts[1:], " ")
-				if entry.User != "" {
-					if newValue != entry.User {
-						// Value changed, update it but preserve indentation and directive case
-						if _, err := file.WriteString(originalIndent + originalDirective + " " + entry.User + "\n"); err != nil {
-							return err
-						}
-					} else {
-						// Value unchanged, write original line exactly as-is
-						if _, err := file.WriteString(line + "\n"); err != nil {
-							return err
-						}
-					}
-				} else {
-					// User was removed, skip this line
-					continue
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, strings.Join(parts[1:], " "), entry.User); err != nil {
+					return err
 				}
 			case "port":
 				writtenPort = true
-				newValue := strings.Join(parts[1:], " ")
-				if entry.Port != "" {
-					if newValue != entry.Port {
-						// Value changed, update it but preserve indentation and directive case
-						if _, err := file.WriteString(originalIndent + originalDirective + " " + entry.Port + "\n"); err != nil {
-							return err
-						}
-					} else {
-						// Value unchanged, write original line exactly as-is
-						if _, err := file.WriteString(line + "\n"); err != nil {
-							return err
-						}
-					}
-				} else {
-					// Port was removed, skip this line
-					continue
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, strings.Join(parts[1:], " "), entry.Port); err != nil {
+					return err
 				}
 			case "identityfile":
 				writtenIdentityFile = true
-				newValue := strings.Join(parts[1:], " ")
-				if entry.IdentityFile != "" {
-					if newValue != entry.IdentityFile {
-						// Value changed, update it but preserve indentation and directive case
-						if _, err := file.WriteString(originalIndent + originalDirective + " " + entry.IdentityFile + "\n"); err != nil {
-							return err
-						}
-					} else {
-						// Value unchanged, write original line exactly as-is
-						if _, err := file.WriteString(line + "\n"); err != nil {
-							return err
-						}
-					}
-				} else {
-					// IdentityFile was removed, skip this line
-					continue
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, strings.Join(parts[1:], " "), entry.IdentityFile); err != nil {
+					return err
+				}
+			case "proxyjump":
+				writtenProxyJump = true
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, strings.Join(parts[1:], " "), entry.ProxyJump); err != nil {
+					return err
+				}
+			case "proxycommand":
+				writtenProxyCommand = true
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, rawDirectiveValue(directiveText), entry.ProxyCommand); err != nil {
+					return err
+				}
+			case "forwardagent":
+				writtenForwardAgent = true
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, strings.Join(parts[1:], " "), entry.ForwardAgent); err != nil {
+					return err
+				}
+			case "addkeystoagent":
+				writtenAddKeysToAgent = true
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, strings.Join(parts[1:], " "), entry.AddKeysToAgent); err != nil {
+					return err
+				}
+			case "usekeychain":
+				writtenUseKeychain = true
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, strings.Join(parts[1:], " "), entry.UseKeychain); err != nil {
+					return err
+				}
+			case "identitiesonly":
+				writtenIdentitiesOnly = true
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, strings.Join(parts[1:], " "), entry.IdentitiesOnly); err != nil {
+					return err
+				}
+			case "localforward":
+				// Repeatable: the n-th LocalForward line maps to the n-th forward,
+				// extra lines are dropped and extra forwards appended below
+				newValue := ""
+				if writtenLocalForwards < len(entry.LocalForward) {
+					newValue = entry.LocalForward[writtenLocalForwards]
+					writtenLocalForwards++
+				}
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, strings.Join(parts[1:], " "), newValue); err != nil {
+					return err
+				}
+			case "remoteforward":
+				// Repeatable, like LocalForward
+				newValue := ""
+				if writtenRemoteForwards < len(entry.RemoteForward) {
+					newValue = entry.RemoteForward[writtenRemoteForwards]
+					writtenRemoteForwards++
+				}
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, strings.Join(parts[1:], " "), newValue); err != nil {
+					return err
+				}
+			case "setenv":
+				// Repeatable, like LocalForward; values keep their quoting
+				newValue := ""
+				if writtenSetEnvs < len(entry.SetEnv) {
+					newValue = entry.SetEnv[writtenSetEnvs]
+					writtenSetEnvs++
+				}
+				if err := writeDirectiveLine(file, line, originalIndent+originalDirective, rawDirectiveValue(directiveText), newValue); err != nil {
+					return err
 				}
 			default:
-				// Preserve other directives as-is
+				// Preserve other directives as-is, unless they were removed
+				// from ExtraDirectives
+				if entry.ExtraDirectives != nil {
+					if pendingExtras[directiveText] == 0 {
+						continue
+					}
+					pendingExtras[directiveText]--
+				}
 				if _, err := file.WriteString(line + "\n"); err != nil {
 					return err
 				}
@@ -248,22 +502,74 @@ func writeEntry(file *os.File, entry *HostEntry) error {
 				return err
 			}
 		}
+		if !writtenProxyJump && entry.ProxyJump != "" {
+			if _, err := file.WriteString(indent + "ProxyJump " + entry.ProxyJump + "\n"); err != nil {
+				return err
+			}
+		}
+		if !writtenProxyCommand && entry.ProxyCommand != "" {
+			if _, err := file.WriteString(indent + "ProxyCommand " + entry.ProxyCommand + "\n"); err != nil {
+				return err
+			}
+		}
+		if !writtenForwardAgent && entry.ForwardAgent != "" {
+			if _, err := file.WriteString(indent + "ForwardAgent " + entry.ForwardAgent + "\n"); err != nil {
+				return err
+			}
+		}
+		if !writtenAddKeysToAgent && entry.AddKeysToAgent != "" {
+			if _, err := file.WriteString(indent + "AddKeysToAgent " + entry.AddKeysToAgent + "\n"); err != nil {
+				return err
+			}
+		}
+		if !writtenUseKeychain && entry.UseKeychain != "" {
+			if _, err := file.WriteString(indent + "UseKeychain " + entry.UseKeychain + "\n"); err != nil {
+				return err
+			}
+		}
+		if !writtenIdentitiesOnly && entry.IdentitiesOnly != "" {
+			if _, err := file.WriteString(indent + "IdentitiesOnly " + entry.IdentitiesOnly + "\n"); err != nil {
+				return err
+			}
+		}
+		for _, forward := range entry.LocalForward[writtenLocalForwards:] {
+			if _, err := file.WriteString(indent + "LocalForward " + forward + "\n"); err != nil {
+				return err
+			}
+		}
+		for _, forward := range entry.RemoteForward[writtenRemoteForwards:] {
+			if _, err := file.WriteString(indent + "RemoteForward " + forward + "\n"); err != nil {
+				return err
+			}
+		}
+		for _, env := range entry.SetEnv[writtenSetEnvs:] {
+			if _, err := file.WriteString(indent + "SetEnv " + env + "\n"); err != nil {
+				return err
+			}
+		}
+		for _, extra := range entry.ExtraDirectives {
+			if pendingExtras[extra] == 0 {
+				continue
+			}
+			pendingExtras[extra]--
+			if _, err := file.WriteString(indent + extra + "\n"); err != nil {
+				return err
+			}
+		}
+
+		// A Match block that followed the host goes after its directives, as is
+		for _, line := range matchLines {
+			if _, err := file.WriteString(line + "\n"); err != nil {
+				return err
+			}
+		}
 
 		return nil
 	}
 
 	// Write new entry from scratch
-	if entry.Description != "" {
-		if _, err := file.WriteString("# Description: " + entry.Description + "\n"); err != nil {
-			return err
-		}
-	}
-
-	if len(entry.Tags) > 0 {
-		tagsStr := strings.Join(entry.Tags, ", ")
-		if _, err := file.WriteString("# Tags: " + tagsStr + "\n"); err != nil {
-			return err
-		}
+	if err := writeMetadataComments(file, entry); err != nil {
+		return err
 	}
 
 	if _, err := file.WriteString("Host " + entry.Host + "\n"); err != nil {
@@ -294,50 +600,442 @@ func writeEntry(file *os.File, entry *HostEntry) error {
 		}
 	}
 
+	if entry.ProxyJump != "" {
+		if _, err := file.WriteString("    ProxyJump " + entry.ProxyJump + "\n"); err != nil {
+			return err
+		}
+	}
+
+	if entry.ProxyCommand != "" {
+		if _, err := file.WriteString("    ProxyCommand " + entry.ProxyCommand + "\n"); err != nil {
+			return err
+		}
+	}
+
+	if entry.ForwardAgent != "" {
+		if _, err := file.WriteString("    ForwardAgent " + entry.ForwardAgent + "\n"); err != nil {
+			return err
+		}
+	}
+
+	if entry.AddKeysToAgent != "" {
+		if _, err := file.WriteString("    AddKeysToAgent " + entry.AddKeysToAgent + "\n"); err != nil {
+			return err
+		}
+	}
+
+	if entry.UseKeychain != "" {
+		if _, err := file.WriteString("    UseKeychain " + entry.UseKeychain + "\n"); err != nil {
+			return err
+		}
+	}
+
+	if entry.IdentitiesOnly != "" {
+		if _, err := file.WriteString("    IdentitiesOnly " + entry.IdentitiesOnly + "\n"); err != nil {
+			return err
+		}
+	}
+
+	for _, forward := range entry.LocalForward {
+		if _, err := file.WriteString("    LocalForward " + forward + "\n"); err != nil {
+			return err
+		}
+	}
+
+	for _, forward := range entry.RemoteForward {
+		if _, err := file.WriteString("    RemoteForward " + forward + "\n"); err != nil {
+			return err
+		}
+	}
+
+	for _, env := range entry.SetEnv {
+		if _, err := file.WriteString("    SetEnv " + env + "\n"); err != nil {
+			return err
+		}
+	}
+
+	for _, extra := range entry.ExtraDirectives {
+		if _, err := file.WriteString("    " + extra + "\n"); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// sourceFileFor returns the file (the main config or one it Includes) that
+// defines host, so edits land in the right file
+func sourceFileFor(path string, host string) (string, error) {
+	entries, _, err := ParseConfig(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	if i := FindEntry(entries, host); i >= 0 {
+		return entries[i].SourceFile, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrHostNotFound, host)
+}
+
 // AddEntry adds a new entry to the config file
 func AddEntry(path string, entry *HostEntry) error {
-	entries, standaloneComments, err := ParseConfig(path)
+	change, err := PlanAddEntry(path, entry)
+	if err != nil {
+		return err
+	}
+	return change.Apply()
+}
+
+// UpdateEntry updates an existing entry in the file that defines it. oldHost
+// is matched with FindEntry, so the case or a single alias is enough.
+func UpdateEntry(path string, oldHost string, newEntry *HostEntry) error {
+	change, err := PlanUpdateEntry(path, oldHost, newEntry)
+	if err != nil {
+		return err
+	}
+	return change.Apply()
+}
+
+// removeAlias drops host from the Host line of a multi-alias entry, keeping
+// the block and its directives for the other aliases. It reports false when
+// host names the whole entry (its full Host line or its only alias).
+func removeAlias(entry *HostEntry, host string) bool {
+	aliases := entry.Aliases()
+	if len(aliases) < 2 || strings.EqualFold(strings.Join(aliases, " "), strings.Join(strings.Fields(host), " ")) {
+		return false
+	}
+	for i, alias := range aliases {
+		if strings.EqualFold(alias, host) {
+			entry.Host = strings.Join(append(aliases[:i:i], aliases[i+1:]...), " ")
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteEntry removes an entry (matched with FindEntry) from the file that
+// defines it. A single alias of a multi-alias block ("Host web1 web2") is
+// removed from the Host line only, leaving the block for the other aliases.
+func DeleteEntry(path string, host string) error {
+	file, err := sourceFileFor(path, host)
+	if err != nil {
+		return err
+	}
+
+	entries, standaloneComments, err := parseSingleFile(file)
 	if err != nil {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
 
-	entries = append(entries, entry)
-	return WriteConfig(path, entries, standaloneComments)
+	i := FindEntry(entries, host)
+	if i < 0 {
+		return fmt.Errorf("%w: %q", ErrHostNotFound, host)
+	}
+	if removeAlias(entries[i], host) {
+		return WriteConfig(file, entries, standaloneComments)
+	}
+	newEntries := append(entries[:i:i], entries[i+1:]...)
+
+	return WriteConfig(file, newEntries, standaloneComments)
 }
 
-// UpdateEntry updates an existing entry in the config file
-func UpdateEntry(path string, oldHost string, newEntry *HostEntry) error {
-	entries, standaloneComments, err := ParseConfig(path)
+// DeleteAlias removes alias from the Host line of the multi-alias entry host
+// (matched with FindEntry, e.g. by its whole Host line), keeping the block for
+// its other aliases. Unlike DeleteEntry with the alias, it never touches a
+// separate block that happens to be named alias.
+func DeleteAlias(path string, host string, alias string) error {
+	file, err := sourceFileFor(path, host)
+	if err != nil {
+		return err
+	}
+
+	entries, standaloneComments, err := parseSingleFile(file)
 	if err != nil {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
 
-	for i, entry := range entries {
-		if entry.Host == oldHost {
-			entries[i] = newEntry
-			break
+	i := FindEntry(entries, host)
+	if i < 0 {
+		return fmt.Errorf("%w: %q", ErrHostNotFound, host)
+	}
+	if len(entries[i].Aliases()) < 2 {
+		return fmt.Errorf("%w: %q", ErrSingleAlias, host)
+	}
+	if !removeAlias(entries[i], alias) {
+		return fmt.Errorf("%w: %q in %q", ErrHostNotFound, alias, host)
+	}
+
+	return WriteConfig(file, entries, standaloneComments)
+}
+
+// DeleteEntries removes several entries, writing each affected file once.
+// Single aliases are removed as in DeleteEntry. Hosts that can't be found are
+// skipped; the number removed is returned.
+func DeleteEntries(path string, hosts []string) (int, error) {
+	all, _, err := ParseConfig(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	var files []string
+	byFile := make(map[string][]string)
+	for _, host := range hosts {
+		i := FindEntry(all, host)
+		if i < 0 {
+			continue
+		}
+		file := all[i].SourceFile
+		if _, ok := byFile[file]; !ok {
+			files = append(files, file)
+		}
+		byFile[file] = append(byFile[file], host)
+	}
+
+	deleted := 0
+	for _, file := range files {
+		entries, standaloneComments, err := parseSingleFile(file)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to parse config: %w", err)
+		}
+
+		remove := make(map[int]bool)
+		aliasesRemoved := 0
+		for _, host := range byFile[file] {
+			i := FindEntry(entries, host)
+			if i < 0 || remove[i] {
+				continue
+			}
+			if removeAlias(entries[i], host) {
+				aliasesRemoved++
+			} else {
+				remove[i] = true
+			}
+		}
+		if len(remove) == 0 && aliasesRemoved == 0 {
+			continue
+		}
+
+		var newEntries []*HostEntry
+		for i, entry := range entries {
+			if !remove[i] {
+				newEntries = append(newEntries, entry)
+			}
 		}
+		if err := WriteConfig(file, newEntries, standaloneComments); err != nil {
+			return deleted, err
+		}
+		deleted += len(remove) + aliasesRemoved
 	}
 
-	return WriteConfig(path, entries, standaloneComments)
+	return deleted, nil
 }
 
-// DeleteEntry removes an entry from the config file
-func DeleteEntry(path string, host string) error {
-	entries, standaloneComments, err := ParseConfig(path)
+// SplitEntry replaces a multi-alias Host entry (e.g. "Host a b c") with one
+// single-alias entry per alias, each carrying a copy of the original directives
+func SplitEntry(path string, host string) error {
+	file, err := sourceFileFor(path, host)
+	if err != nil {
+		return err
+	}
+
+	entries, standaloneComments, err := parseSingleFile(file)
 	if err != nil {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
 
+	target := FindEntry(entries, host)
+	if target < 0 {
+		return fmt.Errorf("%w: %q", ErrHostNotFound, host)
+	}
+
 	var newEntries []*HostEntry
-	for _, entry := range entries {
-		if entry.Host != host {
+	for i, entry := range entries {
+		if i != target {
 			newEntries = append(newEntries, entry)
+			continue
+		}
+
+		aliases := entry.Aliases()
+		if len(aliases) < 2 {
+			return fmt.Errorf("%w: %q", ErrSingleAlias, host)
+		}
+
+		for _, alias := range aliases {
+			split := *entry
+			split.Host = alias
+			split.Tags = append([]string(nil), entry.Tags...)
+			split.RawLines = append([]string(nil), entry.RawLines...)
+			newEntries = append(newEntries, &split)
+		}
+	}
+
+	return WriteConfig(file, newEntries, standaloneComments)
+}
+
+// ImportResult counts what ImportEntries did with each imported entry
+type ImportResult struct {
+	Added   int
+	Updated int
+	Skipped int
+}
+
+// ImportEntries merges entries into the config at path. New hosts are added
+// to the main config; hosts that already exist (by any alias, ignoring case)
+// are skipped, or replaced in the file that defines them when overwrite is
+// set. Each affected file is written once.
+func ImportEntries(path string, entries []*HostEntry, overwrite bool) (ImportResult, error) {
+	var result ImportResult
+
+	existing, _, err := ParseConfig(path)
+	if err != nil {
+		return result, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	// Every change is applied to the parsed files first and each file is
+	// written once, so its .bak holds the config from before the import
+	type pendingFile struct {
+		entries            []*HostEntry
+		standaloneComments []string
+	}
+	var order []string
+	files := make(map[string]*pendingFile)
+	load := func(file string) (*pendingFile, error) {
+		resolved, err := resolveConfigPath(file)
+		if err != nil {
+			return nil, err
+		}
+		if pending, ok := files[resolved]; ok {
+			return pending, nil
+		}
+		fileEntries, standaloneComments, err := parseSingleFile(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse config: %w", err)
+		}
+		pending := &pendingFile{entries: fileEntries, standaloneComments: standaloneComments}
+		files[resolved] = pending
+		order = append(order, resolved)
+		return pending, nil
+	}
+
+	for _, entry := range entries {
+		if entry == nil || !entry.IsValid() {
+			result.Skipped++
+			continue
+		}
+
+		// The source file refers to the machine the entries were exported on
+		imported := *entry
+		imported.SourceFile = ""
+
+		if i := findImported(existing, entry); i >= 0 {
+			if !overwrite {
+				result.Skipped++
+				continue
+			}
+			pending, err := load(existing[i].SourceFile)
+			if err != nil {
+				return result, err
+			}
+			j := FindEntry(pending.entries, existing[i].Host)
+			if j < 0 {
+				return result, fmt.Errorf("%w: %q", ErrHostNotFound, existing[i].Host)
+			}
+			imported.SourceFile = existing[i].SourceFile
+			pending.entries[j] = &imported
+			existing[i] = &imported
+			result.Updated++
+			continue
+		}
+
+		pending, err := load(path)
+		if err != nil {
+			return result, err
+		}
+		pending.entries = append(pending.entries, &imported)
+		added := imported
+		added.SourceFile = path
+		existing = append(existing, &added)
+		result.Added++
+	}
+
+	for _, file := range order {
+		if err := WriteConfig(file, files[file].entries, files[file].standaloneComments); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// findImported returns the index of the existing entry an imported one
+// duplicates: matched with FindEntry by its whole Host line or any of its
+// aliases, so "Web1" or "web1 web2" finds an existing "web1". -1 if none.
+func findImported(existing []*HostEntry, entry *HostEntry) int {
+	if i := FindEntry(existing, entry.Host); i >= 0 {
+		return i
+	}
+	for _, alias := range entry.Aliases() {
+		if i := FindEntry(existing, alias); i >= 0 {
+			return i
 		}
 	}
+	return -1
+}
+
+// ExportEntry writes a single entry (with its raw lines and description) to a
+// new standalone file that can be Included from another config. It refuses to
+// overwrite an existing file.
+func ExportEntry(path string, entry *HostEntry) error {
+	expanded := path
+	if strings.HasPrefix(expanded, "~") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return &WriteError{Path: path, Op: "failed to get home directory", Err: err}
+		}
+		expanded = strings.Replace(expanded, "~", homeDir, 1)
+	}
+
+	if _, err := os.Stat(expanded); err == nil {
+		return &WriteError{Path: path, Op: "failed to export entry", Err: os.ErrExist}
+	}
+
+	return WriteConfig(expanded, []*HostEntry{entry}, nil)
+}
+
+// UpdateStandaloneComments replaces the comment block written at the top of the
+// config file. Non-empty lines that aren't comments are prefixed with "# ",
+// except Include lines and directives that were already in the block.
+func UpdateStandaloneComments(path string, comments []string) error {
+	entries, existing, err := parseSingleFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	existingDirectives := make(map[string]bool)
+	for _, line := range existing {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			existingDirectives[trimmed] = true
+		}
+	}
+
+	var normalized []string
+	for _, c := range comments {
+		trimmed := strings.TrimSpace(c)
+		isDirective := existingDirectives[trimmed] || strings.HasPrefix(strings.ToLower(trimmed), "include ")
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") && !isDirective {
+			c = "# " + trimmed
+		}
+		normalized = append(normalized, c)
+	}
+
+	// Drop leading/trailing blank lines so they don't pile up on each save
+	for len(normalized) > 0 && strings.TrimSpace(normalized[0]) == "" {
+		normalized = normalized[1:]
+	}
+	for len(normalized) > 0 && strings.TrimSpace(normalized[len(normalized)-1]) == "" {
+		normalized = normalized[:len(normalized)-1]
+	}
 
-	return WriteConfig(path, newEntries, standaloneComments)
+	return WriteConfig(path, entries, normalized)
 }
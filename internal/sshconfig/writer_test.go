@@ -1,6 +1,7 @@
 package sshconfig
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -230,6 +231,138 @@ func TestDeleteEntry(t *testing.T) {
 	}
 }
 
+func TestDeleteEntry_SingleAlias(t *testing.T) {
+	configContent := `# Description: Web cluster
+Host web1 web2
+    HostName 10.0.0.1
+    User deploy
+    ForwardAgent yes
+
+Host other
+    HostName other.com
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	if err := DeleteEntry(configPath, "web1"); err != nil {
+		t.Fatalf("DeleteEntry failed: %v", err)
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	want := strings.Replace(configContent, "Host web1 web2", "Host web2", 1)
+	if string(content) != want {
+		t.Errorf("Only the alias should be removed.\nGot:\n%s\nWant:\n%s", content, want)
+	}
+
+	// The last alias (or the whole Host line) removes the block
+	if err := DeleteEntry(configPath, "web2"); err != nil {
+		t.Fatalf("DeleteEntry failed: %v", err)
+	}
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Host != "other" {
+		t.Errorf("Expected only other to remain, got %d entries", len(entries))
+	}
+}
+
+func TestDeleteAlias(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	content := "Host web1\n    HostName standalone.example.com\n\nHost web1 web2\n    HostName cluster.example.com\n    User deploy\n"
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// The alias is removed from the named block, not from the standalone web1
+	if err := DeleteAlias(configPath, "web1 web2", "web1"); err != nil {
+		t.Fatalf("DeleteAlias failed: %v", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if want := strings.Replace(content, "Host web1 web2", "Host web2", 1); string(data) != want {
+		t.Errorf("Unexpected config:\n%s\nwant:\n%s", data, want)
+	}
+
+	if err := DeleteAlias(configPath, "web2", "web2"); !errors.Is(err, ErrSingleAlias) {
+		t.Errorf("Removing the only alias should fail with ErrSingleAlias, got %v", err)
+	}
+}
+
+func TestDeleteEntries(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	includedPath := filepath.Join(dir, "work.conf")
+	main := "Include work.conf\n\nHost a\n    HostName a.com\n\nHost b\n    HostName b.com\n\nHost c\n    HostName c.com\n"
+	if err := os.WriteFile(configPath, []byte(main), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if err := os.WriteFile(includedPath, []byte("Host w\n    HostName w.com\n"), 0600); err != nil {
+		t.Fatalf("Failed to write included config: %v", err)
+	}
+
+	deleted, err := DeleteEntries(configPath, []string{"a", "C", "w", "missing"})
+	if err != nil {
+		t.Fatalf("DeleteEntries failed: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("Expected 3 deleted, got %d", deleted)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Host != "b" {
+		t.Errorf("Expected only 'b' to remain, got %d entries", len(entries))
+	}
+}
+
+func TestUpdateDeleteEntry_TolerantMatching(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	content := "Host Prod\n    HostName prod.example.com\n\nHost web web-prod\n    HostName web.example.com\n\nHost db\n    HostName db.example.com\n"
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// Different case
+	if err := UpdateEntry(configPath, "prod", &HostEntry{Host: "Prod", HostName: "prod2.example.com"}); err != nil {
+		t.Fatalf("UpdateEntry with different case failed: %v", err)
+	}
+
+	// One alias of a multi-alias Host line
+	if err := UpdateEntry(configPath, "web-prod", &HostEntry{Host: "web web-prod", HostName: "web2.example.com"}); err != nil {
+		t.Fatalf("UpdateEntry by alias failed: %v", err)
+	}
+
+	if err := DeleteEntry(configPath, "DB"); err != nil {
+		t.Fatalf("DeleteEntry with different case failed: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].HostName != "prod2.example.com" || entries[1].HostName != "web2.example.com" {
+		t.Errorf("Entries not updated: %+v, %+v", entries[0], entries[1])
+	}
+
+	if err := DeleteEntry(configPath, "cache"); !errors.Is(err, ErrHostNotFound) {
+		t.Errorf("Expected ErrHostNotFound for an unknown host, got %v", err)
+	}
+}
+
 func TestPreserveFormatting(t *testing.T) {
 	configContent := `Host example
 	HostName example.com
@@ -337,3 +470,1147 @@ func TestUpdateDescription(t *testing.T) {
 		t.Error("Config should not contain old description")
 	}
 }
+
+func TestWriteConfig_LogsCommandRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	entry := &HostEntry{
+		Host:        "web",
+		HostName:    "web.example.com",
+		LogsCommand: "tail -f /var/log/syslog",
+	}
+	if err := WriteConfig(configPath, []*HostEntry{entry}, nil); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+
+	// Parse and write back to make sure the comment isn't duplicated
+	entries, comments, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if err := WriteConfig(configPath, entries, comments); err != nil {
+		t.Fatalf("WriteConfig (second) failed: %v", err)
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+
+	if count := strings.Count(string(content), "# Logs:"); count != 1 {
+		t.Errorf("Expected 1 logs comment, got %d:\n%s", count, content)
+	}
+	if !strings.Contains(string(content), "# Logs: tail -f /var/log/syslog") {
+		t.Errorf("Config should contain logs comment, got:\n%s", content)
+	}
+}
+
+func TestSplitEntry(t *testing.T) {
+	configContent := `# Description: Web cluster
+# Tags: prod
+Host web1 web2 web3
+    HostName 10.0.0.1
+    User deploy
+    ForwardAgent yes
+
+Host other
+    HostName other.com
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	if err := SplitEntry(configPath, "web1 web2 web3"); err != nil {
+		t.Fatalf("SplitEntry failed: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	wantHosts := []string{"web1", "web2", "web3", "other"}
+	if len(entries) != len(wantHosts) {
+		t.Fatalf("Expected %d entries, got %d", len(wantHosts), len(entries))
+	}
+	for i, host := range wantHosts {
+		if entries[i].Host != host {
+			t.Errorf("Entry %d: got host %q, want %q", i, entries[i].Host, host)
+		}
+	}
+	for _, e := range entries[:3] {
+		if e.HostName != "10.0.0.1" || e.User != "deploy" {
+			t.Errorf("%s: directives not copied (HostName %q, User %q)", e.Host, e.HostName, e.User)
+		}
+		if e.Description != "Web cluster" || len(e.Tags) != 1 || e.Tags[0] != "prod" {
+			t.Errorf("%s: metadata not copied (Description %q, Tags %v)", e.Host, e.Description, e.Tags)
+		}
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if count := strings.Count(string(content), "ForwardAgent yes"); count != 3 {
+		t.Errorf("Expected ForwardAgent copied to 3 entries, got %d", count)
+	}
+
+	// Single-alias hosts can't be split
+	if err := SplitEntry(configPath, "other"); err == nil {
+		t.Error("Expected error splitting a single-alias host")
+	}
+}
+
+func TestUpdateStandaloneComments(t *testing.T) {
+	configContent := `# ====================
+# My SSH config
+# ====================
+
+# Description: Production server
+Host prod
+    HostName prod.example.com
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	_, comments, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if len(comments) != 3 {
+		t.Fatalf("Expected 3 header comment lines, got %d: %q", len(comments), comments)
+	}
+
+	newComments := []string{"# Work laptop", "", "Managed by gosshit"}
+	if err := UpdateStandaloneComments(configPath, newComments); err != nil {
+		t.Fatalf("UpdateStandaloneComments failed: %v", err)
+	}
+
+	entries, comments, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig (second) failed: %v", err)
+	}
+
+	want := []string{"# Work laptop", "", "# Managed by gosshit"}
+	if len(comments) != len(want) {
+		t.Fatalf("Got comments %q, want %q", comments, want)
+	}
+	for i := range want {
+		if comments[i] != want[i] {
+			t.Errorf("Comment %d: got %q, want %q", i, comments[i], want[i])
+		}
+	}
+
+	if len(entries) != 1 || entries[0].Host != "prod" || entries[0].Description != "Production server" {
+		t.Errorf("Entries should be unchanged, got %+v", entries)
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "# Work laptop\n") {
+		t.Errorf("Comments should be written at the top of the file, got:\n%s", content)
+	}
+}
+
+func TestUpdateEntry_NonExistentHost(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	initialEntry := &HostEntry{
+		Host:     "example",
+		HostName: "example.com",
+	}
+	err := WriteConfig(configPath, []*HostEntry{initialEntry}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create initial config: %v", err)
+	}
+
+	before, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+
+	err = UpdateEntry(configPath, "stale-alias", &HostEntry{Host: "stale-alias", HostName: "new.example.com"})
+	if err == nil {
+		t.Fatal("Expected error when updating a non-existent host")
+	}
+
+	after, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if string(before) != string(after) {
+		t.Errorf("Config should be unchanged, got:\n%s", after)
+	}
+}
+
+func TestWriteConfig_ProxyJump(t *testing.T) {
+	configContent := "Host internal\n\tHostName 10.0.0.5\n\tproxyjump   bastion\n\nHost bastion\n    HostName bastion.example.com\n"
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, comments, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if entries[0].ProxyJump != "bastion" {
+		t.Fatalf("ProxyJump: got %q, want %q", entries[0].ProxyJump, "bastion")
+	}
+
+	// Untouched ProxyJump keeps its original indentation and case
+	if err := WriteConfig(configPath, entries, comments); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if !strings.Contains(string(content), "\tproxyjump   bastion\n") {
+		t.Errorf("Untouched ProxyJump line should be preserved, got:\n%s", content)
+	}
+
+	// Changed value keeps indentation and directive case
+	entries[0].ProxyJump = "jump.example.com"
+	// New ProxyJump is added to an entry that had none
+	entries[1].ProxyJump = "gateway"
+	if err := WriteConfig(configPath, entries, comments); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+	content, err = os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if !strings.Contains(string(content), "\tproxyjump jump.example.com\n") {
+		t.Errorf("Updated ProxyJump should keep indentation and case, got:\n%s", content)
+	}
+	if !strings.Contains(string(content), "    ProxyJump gateway\n") {
+		t.Errorf("New ProxyJump should be added, got:\n%s", content)
+	}
+
+	entries, _, err = ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig (second) failed: %v", err)
+	}
+	if entries[0].ProxyJump != "jump.example.com" || entries[1].ProxyJump != "gateway" {
+		t.Errorf("ProxyJump round-trip: got %q and %q", entries[0].ProxyJump, entries[1].ProxyJump)
+	}
+}
+
+func TestUpdateAndDeleteEntry_IncludedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+	includedPath := filepath.Join(tmpDir, "work.conf")
+
+	mainContent := "Include work.conf\n\nHost main\n    HostName main.example.com\n"
+	includedContent := "Host work\n    HostName work.example.com\n\nHost other\n    HostName other.example.com\n"
+	if err := os.WriteFile(configPath, []byte(mainContent), 0644); err != nil {
+		t.Fatalf("Failed to create main config: %v", err)
+	}
+	if err := os.WriteFile(includedPath, []byte(includedContent), 0644); err != nil {
+		t.Fatalf("Failed to create included config: %v", err)
+	}
+
+	err := UpdateEntry(configPath, "work", &HostEntry{Host: "work", HostName: "new.example.com"})
+	if err != nil {
+		t.Fatalf("UpdateEntry failed: %v", err)
+	}
+
+	mainAfter, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read main config: %v", err)
+	}
+	if string(mainAfter) != mainContent {
+		t.Errorf("Main config should be untouched, got:\n%s", mainAfter)
+	}
+
+	included, err := os.ReadFile(includedPath)
+	if err != nil {
+		t.Fatalf("Failed to read included config: %v", err)
+	}
+	if !strings.Contains(string(included), "HostName new.example.com") {
+		t.Errorf("Included config should be updated, got:\n%s", included)
+	}
+
+	if err := DeleteEntry(configPath, "other"); err != nil {
+		t.Fatalf("DeleteEntry failed: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	var hosts []string
+	for _, e := range entries {
+		hosts = append(hosts, e.Host)
+	}
+	if strings.Join(hosts, ",") != "work,main" {
+		t.Errorf("Hosts after delete: got %v, want [work main]", hosts)
+	}
+
+	// Adding a host writes to the main config without copying included hosts
+	if err := AddEntry(configPath, &HostEntry{Host: "added", HostName: "added.example.com"}); err != nil {
+		t.Fatalf("AddEntry failed: %v", err)
+	}
+	mainAfter, err = os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read main config: %v", err)
+	}
+	if strings.Contains(string(mainAfter), "Host work") {
+		t.Errorf("Included hosts must not be copied into the main config, got:\n%s", mainAfter)
+	}
+	if !strings.HasPrefix(string(mainAfter), "Include work.conf\n") {
+		t.Errorf("Include line should be preserved at the top, got:\n%s", mainAfter)
+	}
+}
+
+func TestExportEntry(t *testing.T) {
+	configContent := `# Description: Build server
+# Tags: ci
+Host build
+	HostName build.example.com
+	User ci
+	ServerAliveInterval 30
+
+Host other
+	HostName other.example.com
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+	exportPath := filepath.Join(tmpDir, "config.d", "build.conf")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	if err := ExportEntry(exportPath, entries[0]); err != nil {
+		t.Fatalf("ExportEntry failed: %v", err)
+	}
+
+	exported, _, err := ParseConfig(exportPath)
+	if err != nil {
+		t.Fatalf("ParseConfig (export) failed: %v", err)
+	}
+	if len(exported) != 1 {
+		t.Fatalf("Expected 1 exported entry, got %d", len(exported))
+	}
+	e := exported[0]
+	if e.Host != "build" || e.HostName != "build.example.com" || e.User != "ci" {
+		t.Errorf("Exported entry mismatch: %+v", e)
+	}
+	if e.Description != "Build server" || len(e.Tags) != 1 || e.Tags[0] != "ci" {
+		t.Errorf("Exported metadata mismatch: description %q, tags %v", e.Description, e.Tags)
+	}
+
+	content, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	if !strings.Contains(string(content), "\tServerAliveInterval 30\n") {
+		t.Errorf("Raw lines should be preserved, got:\n%s", content)
+	}
+
+	// Exporting again must not overwrite the file
+	if err := ExportEntry(exportPath, entries[1]); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Expected os.ErrExist when exporting over an existing file, got %v", err)
+	}
+}
+
+func TestWriteConfig_Forwarding(t *testing.T) {
+	configContent := `Host dev
+    HostName dev.example.com
+    ForwardAgent yes
+    LocalForward 8080 localhost:80
+    ServerAliveInterval 30
+    LocalForward 5432 db.internal:5432
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, comments, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	entry := entries[0]
+	if entry.ForwardAgent != "yes" {
+		t.Errorf("ForwardAgent: got %q, want %q", entry.ForwardAgent, "yes")
+	}
+	if len(entry.LocalForward) != 2 || entry.LocalForward[0] != "8080 localhost:80" || entry.LocalForward[1] != "5432 db.internal:5432" {
+		t.Fatalf("LocalForward: got %q", entry.LocalForward)
+	}
+
+	// Unchanged forwards round-trip exactly
+	if err := WriteConfig(configPath, entries, comments); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if string(content) != configContent {
+		t.Errorf("Unchanged entry should round-trip exactly, got:\n%s", content)
+	}
+
+	// Drop the second forward, change the first, add a third and turn off agent forwarding
+	entry.ForwardAgent = "no"
+	entry.LocalForward = []string{"9090 localhost:90", "6379 cache:6379"}
+	if err := WriteConfig(configPath, entries, comments); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+
+	entries, _, err = ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig (second) failed: %v", err)
+	}
+	entry = entries[0]
+	if entry.ForwardAgent != "no" {
+		t.Errorf("ForwardAgent after update: got %q, want %q", entry.ForwardAgent, "no")
+	}
+	if len(entry.LocalForward) != 2 || entry.LocalForward[0] != "9090 localhost:90" || entry.LocalForward[1] != "6379 cache:6379" {
+		t.Errorf("LocalForward after update: got %q", entry.LocalForward)
+	}
+	content, err = os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if !strings.Contains(string(content), "    ServerAliveInterval 30\n") {
+		t.Errorf("Other directives should be preserved, got:\n%s", content)
+	}
+}
+
+func TestWriteConfig_RemoteForward(t *testing.T) {
+	configContent := `Host dev
+    HostName dev.example.com
+    RemoteForward 9000 localhost:9000
+    LocalForward 8080 localhost:80
+    RemoteForward 1080
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, comments, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	entry := entries[0]
+	if len(entry.RemoteForward) != 2 || entry.RemoteForward[0] != "9000 localhost:9000" || entry.RemoteForward[1] != "1080" {
+		t.Fatalf("RemoteForward: got %q", entry.RemoteForward)
+	}
+	if len(entry.LocalForward) != 1 {
+		t.Fatalf("LocalForward: got %q", entry.LocalForward)
+	}
+	if len(entry.ExtraDirectives) != 0 {
+		t.Errorf("RemoteForward should not be an extra directive, got %q", entry.ExtraDirectives)
+	}
+
+	// Remove the dynamic forward and add another one on its own line
+	entry.RemoteForward = []string{"9000 localhost:9000", "2222 localhost:22", "3000 localhost:3000"}
+	if err := WriteConfig(configPath, entries, comments); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	expected := `Host dev
+    HostName dev.example.com
+    RemoteForward 9000 localhost:9000
+    LocalForward 8080 localhost:80
+    RemoteForward 2222 localhost:22
+    RemoteForward 3000 localhost:3000
+`
+	if string(content) != expected {
+		t.Errorf("Unexpected config, got:\n%s\nwant:\n%s", content, expected)
+	}
+}
+
+func TestUpdateEntry_Description(t *testing.T) {
+	configContent := `# Description: Old text
+# Tags: web
+Host web
+    HostName web.example.com
+    # keep me
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	for _, description := range []string{"New: text with # and colons", ""} {
+		entries, _, err := ParseConfig(configPath)
+		if err != nil {
+			t.Fatalf("ParseConfig failed: %v", err)
+		}
+
+		updated := *entries[0]
+		updated.Description = description
+		if err := UpdateEntry(configPath, "web", &updated); err != nil {
+			t.Fatalf("UpdateEntry failed: %v", err)
+		}
+
+		entries, _, err = ParseConfig(configPath)
+		if err != nil {
+			t.Fatalf("ParseConfig (after update) failed: %v", err)
+		}
+		if entries[0].Description != description {
+			t.Errorf("Description: got %q, want %q", entries[0].Description, description)
+		}
+		if len(entries[0].Tags) != 1 || entries[0].Tags[0] != "web" {
+			t.Errorf("Tags should be untouched, got %v", entries[0].Tags)
+		}
+
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("Failed to read config: %v", err)
+		}
+		if strings.Count(string(content), "# Description:") > 1 || strings.Contains(string(content), "Old text") {
+			t.Errorf("Old description should be replaced, got:\n%s", content)
+		}
+		if !strings.Contains(string(content), "    # keep me\n") {
+			t.Errorf("Comments inside the block should be preserved, got:\n%s", content)
+		}
+	}
+}
+
+func TestWriteConfig_AtomicWithBackup(t *testing.T) {
+	configContent := "Host example\n    HostName example.com\n"
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, comments, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	entries[0].HostName = "new.example.com"
+	if err := WriteConfig(configPath, entries, comments); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+
+	backup, err := os.ReadFile(configPath + ".bak")
+	if err != nil {
+		t.Fatalf("Backup should exist: %v", err)
+	}
+	if string(backup) != configContent {
+		t.Errorf("Backup should hold the previous config, got:\n%s", backup)
+	}
+
+	for _, p := range []string{configPath, configPath + ".bak"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("Stat %s: %v", p, err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("%s mode: got %v, want 0600", filepath.Base(p), info.Mode().Perm())
+		}
+	}
+
+	files, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(files) != 2 {
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Name())
+		}
+		t.Errorf("Expected only config and config.bak, got %v", names)
+	}
+}
+
+func TestWriteConfig_Symlink(t *testing.T) {
+	tmpDir := t.TempDir()
+	targetPath := filepath.Join(tmpDir, "dotfiles-config")
+	linkPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(targetPath, []byte("Host example\n    HostName example.com\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+	if err := os.Symlink(targetPath, linkPath); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+
+	entries := []*HostEntry{{Host: "other", HostName: "other.example.com"}}
+	if err := WriteConfig(linkPath, entries, nil); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+
+	info, err := os.Lstat(linkPath)
+	if err != nil {
+		t.Fatalf("Lstat failed: %v", err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Error("Symlink should not be replaced by a regular file")
+	}
+
+	content, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target: %v", err)
+	}
+	if !strings.Contains(string(content), "Host other") {
+		t.Errorf("Symlink target should be updated, got:\n%s", content)
+	}
+	if info, err := os.Stat(targetPath); err == nil && info.Mode().Perm() != 0644 {
+		t.Errorf("Target mode: got %v, want 0644", info.Mode().Perm())
+	}
+}
+
+func TestImportEntries(t *testing.T) {
+	sourceContent := `# Description: Web server
+# Tags: prod
+Host web
+    HostName web.example.com
+    User deploy
+    ServerAliveInterval 30
+
+Host db
+    HostName db.example.com
+    LocalForward 5432 localhost:5432
+`
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "source")
+	targetPath := filepath.Join(tmpDir, "target")
+
+	if err := os.WriteFile(sourcePath, []byte(sourceContent), 0644); err != nil {
+		t.Fatalf("Failed to create source config: %v", err)
+	}
+	if err := os.WriteFile(targetPath, []byte("Host db\n    HostName old-db.example.com\n"), 0644); err != nil {
+		t.Fatalf("Failed to create target config: %v", err)
+	}
+
+	exported, _, err := ParseConfig(sourcePath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	// Existing hosts are skipped by default
+	result, err := ImportEntries(targetPath, exported, false)
+	if err != nil {
+		t.Fatalf("ImportEntries failed: %v", err)
+	}
+	if result != (ImportResult{Added: 1, Skipped: 1}) {
+		t.Errorf("Result: got %+v", result)
+	}
+
+	entries, _, err := ParseConfig(targetPath)
+	if err != nil {
+		t.Fatalf("ParseConfig (target) failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].HostName != "old-db.example.com" {
+		t.Fatalf("Existing host should be kept, got %+v", entries)
+	}
+	web := entries[1]
+	if web.Host != "web" || web.User != "deploy" || web.Description != "Web server" || len(web.Tags) != 1 {
+		t.Errorf("Imported entry mismatch: %+v", web)
+	}
+	if web.SourceFile != targetPath {
+		t.Errorf("Imported entry should live in the target config, got %q", web.SourceFile)
+	}
+
+	// With overwrite, existing hosts are replaced
+	result, err = ImportEntries(targetPath, exported, true)
+	if err != nil {
+		t.Fatalf("ImportEntries (overwrite) failed: %v", err)
+	}
+	if result != (ImportResult{Updated: 2}) {
+		t.Errorf("Overwrite result: got %+v", result)
+	}
+
+	content, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target: %v", err)
+	}
+	for _, want := range []string{"    HostName db.example.com\n", "    LocalForward 5432 localhost:5432\n", "    ServerAliveInterval 30\n"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Target should contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestImportEntries_MatchesAliasesAndWritesOnce(t *testing.T) {
+	targetPath := filepath.Join(t.TempDir(), "config")
+	original := "Host web1\n    HostName web1.example.com\n"
+	if err := os.WriteFile(targetPath, []byte(original), 0600); err != nil {
+		t.Fatalf("Failed to create target config: %v", err)
+	}
+
+	imported := []*HostEntry{
+		{Host: "Web1", HostName: "other.example.com"},
+		{Host: "web1 web2", HostName: "other.example.com"},
+		{Host: "db", HostName: "db.example.com"},
+		{Host: "cache", HostName: "cache.example.com"},
+		{Host: "DB", HostName: "db2.example.com"},
+	}
+	result, err := ImportEntries(targetPath, imported, false)
+	if err != nil {
+		t.Fatalf("ImportEntries failed: %v", err)
+	}
+	if result != (ImportResult{Added: 2, Skipped: 3}) {
+		t.Errorf("Result: got %+v", result)
+	}
+
+	entries, _, err := ParseConfig(targetPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	var hosts []string
+	for _, e := range entries {
+		hosts = append(hosts, e.Host)
+	}
+	if strings.Join(hosts, " ") != "web1 db cache" {
+		t.Errorf("Hosts after import: got %v", hosts)
+	}
+
+	// The backup is the config from before the import, not before its last host
+	if backup, err := os.ReadFile(targetPath + ".bak"); err != nil || string(backup) != original {
+		t.Errorf("Backup should hold the pre-import config, got %q (%v)", backup, err)
+	}
+
+	// Overwriting by alias replaces the existing block in place
+	result, err = ImportEntries(targetPath, []*HostEntry{{Host: "WEB1", HostName: "new.example.com"}}, true)
+	if err != nil {
+		t.Fatalf("ImportEntries (overwrite) failed: %v", err)
+	}
+	if result != (ImportResult{Updated: 1}) {
+		t.Errorf("Overwrite result: got %+v", result)
+	}
+	entries, _, err = ParseConfig(targetPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if len(entries) != 3 || entries[0].HostName != "new.example.com" {
+		t.Errorf("Expected web1 to be replaced in place, got %d entries, first %+v", len(entries), entries[0])
+	}
+}
+
+func TestWriteConfig_ExtraDirectives(t *testing.T) {
+	configContent := `Host dev
+    HostName dev.example.com
+    Compression yes
+    ServerAliveInterval 30
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, comments, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	want := []string{"Compression yes", "ServerAliveInterval 30"}
+	if got := entries[0].ExtraDirectives; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("ExtraDirectives: got %q, want %q", got, want)
+	}
+
+	// Remove one, keep one in place and add another; add a brand-new entry with extras
+	entries[0].ExtraDirectives = []string{"ServerAliveInterval 30", "ForwardX11 no"}
+	entries = append(entries, &HostEntry{
+		Host:            "new",
+		HostName:        "new.example.com",
+		ExtraDirectives: []string{"Compression yes"},
+	})
+	if err := WriteConfig(configPath, entries, comments); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	wantContent := `Host dev
+    HostName dev.example.com
+    ServerAliveInterval 30
+    ForwardX11 no
+
+Host new
+    HostName new.example.com
+    Compression yes
+`
+	if string(content) != wantContent {
+		t.Errorf("Config mismatch:\ngot:\n%s\nwant:\n%s", content, wantContent)
+	}
+}
+
+func TestWriteConfig_KeepsCRLF(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	content := "# Header\r\n\r\nHost web\r\n    HostName web.example.com\r\n\r\nHost db\r\n    HostName db.example.com\r\n"
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if entries[0].HostName != "web.example.com" {
+		t.Errorf("HostName should not keep the \\r, got %q", entries[0].HostName)
+	}
+	for _, line := range entries[0].RawLines {
+		if strings.HasSuffix(line, "\r") {
+			t.Errorf("RawLines should not keep the \\r, got %q", line)
+		}
+	}
+
+	change, err := PlanUpdateEntry(configPath, "db", &HostEntry{Host: "db", HostName: "db2.example.com", RawLines: entries[1].RawLines})
+	if err != nil {
+		t.Fatalf("PlanUpdateEntry failed: %v", err)
+	}
+	if diff := change.Diff(); strings.Count(diff, "\n-") != 1 {
+		t.Errorf("Only the changed line should be in the diff, got:\n%s", diff)
+	}
+	if err := change.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	want := strings.Replace(content, "db.example.com", "db2.example.com", 1)
+	if string(data) != want {
+		t.Errorf("CRLF config did not round-trip\ngot:  %q\nwant: %q", data, want)
+	}
+}
+
+func TestUsesCRLF(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"", false},
+		{"Host a\n", false},
+		{"Host a\r\n", true},
+		{"Host a\r\n    HostName a\r\n    User b\n", true},
+		{"Host a\r\n    HostName a\n    User b\n", false},
+	}
+	for _, tt := range tests {
+		if got := usesCRLF(tt.content); got != tt.want {
+			t.Errorf("usesCRLF(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestWriteConfig_KeepsBlankLinesBetweenEntries(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	content := "Host web\n    HostName web.example.com\n\n\n# Description: Databases\nHost db\n    HostName db.example.com\nHost cache\n    HostName cache.example.com\n"
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if err := UpdateEntry(configPath, "web", &HostEntry{Host: "web", HostName: "web2.example.com", RawLines: entries[0].RawLines}); err != nil {
+		t.Fatalf("UpdateEntry failed: %v", err)
+	}
+	if err := AddEntry(configPath, &HostEntry{Host: "new", HostName: "new.example.com"}); err != nil {
+		t.Fatalf("AddEntry failed: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	// The double blank line survives; adjacent blocks and new entries get one
+	want := "Host web\n    HostName web2.example.com\n\n\n# Description: Databases\nHost db\n    HostName db.example.com\n\nHost cache\n    HostName cache.example.com\n\nHost new\n    HostName new.example.com\n"
+	if string(data) != want {
+		t.Errorf("Spacing not preserved\ngot:  %q\nwant: %q", data, want)
+	}
+}
+
+func TestWriteConfig_AgentDirectives(t *testing.T) {
+	configContent := `Host web
+    HostName web.example.com
+
+Host *
+    addkeystoagent yes
+    UseKeychain yes
+    ServerAliveInterval 30
+`
+	configPath := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	global := entries[1]
+	if global.AddKeysToAgent != "yes" || global.UseKeychain != "yes" || global.IdentitiesOnly != "" {
+		t.Errorf("Agent directives not parsed: %+v", global)
+	}
+	if len(global.ExtraDirectives) != 1 || global.ExtraDirectives[0] != "ServerAliveInterval 30" {
+		t.Errorf("Agent directives should not be extra directives, got %q", global.ExtraDirectives)
+	}
+
+	updated := *global
+	updated.AddKeysToAgent = "confirm"
+	updated.UseKeychain = ""
+	updated.IdentitiesOnly = "yes"
+	if err := UpdateEntry(configPath, "*", &updated); err != nil {
+		t.Fatalf("UpdateEntry failed: %v", err)
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	want := strings.Replace(configContent, "    addkeystoagent yes\n    UseKeychain yes\n", "    addkeystoagent confirm\n", 1) + "    IdentitiesOnly yes\n"
+	if string(content) != want {
+		t.Errorf("Unexpected config\ngot:\n%s\nwant:\n%s", content, want)
+	}
+
+	// Written from scratch too
+	if err := AddEntry(configPath, &HostEntry{Host: "git", HostName: "github.com", IdentitiesOnly: "yes", AddKeysToAgent: "1h"}); err != nil {
+		t.Fatalf("AddEntry failed: %v", err)
+	}
+	entries, _, err = ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if git := entries[2]; git.IdentitiesOnly != "yes" || git.AddKeysToAgent != "1h" {
+		t.Errorf("New entry lost its agent directives: %+v", git)
+	}
+}
+
+func TestWriteConfig_ProxyCommand(t *testing.T) {
+	configContent := `Host internal
+    HostName 10.0.0.5
+    ProxyCommand ssh -W %h:%p  bastion # via the bastion
+    User deploy
+`
+	configPath := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	entry := entries[0]
+	if entry.ProxyCommand != "ssh -W %h:%p  bastion" {
+		t.Errorf("ProxyCommand should keep its spacing, got %q", entry.ProxyCommand)
+	}
+	if len(entry.ExtraDirectives) != 0 {
+		t.Errorf("ProxyCommand should not be an extra directive, got %q", entry.ExtraDirectives)
+	}
+
+	// Unchanged: the line (and its comment) is kept as-is
+	updated := *entry
+	updated.User = "admin"
+	if err := UpdateEntry(configPath, "internal", &updated); err != nil {
+		t.Fatalf("UpdateEntry failed: %v", err)
+	}
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	want := strings.Replace(configContent, "User deploy", "User admin", 1)
+	if string(content) != want {
+		t.Errorf("Unexpected config\ngot:\n%s\nwant:\n%s", content, want)
+	}
+
+	// Changed: written exactly as given
+	updated.ProxyCommand = `sh -c "nc -X 5 -x proxy:1080 %h %p"`
+	if err := UpdateEntry(configPath, "internal", &updated); err != nil {
+		t.Fatalf("UpdateEntry failed: %v", err)
+	}
+	content, err = os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if !strings.Contains(string(content), "    ProxyCommand "+updated.ProxyCommand+"\n") {
+		t.Errorf("ProxyCommand not updated verbatim:\n%s", content)
+	}
+
+	// Written from scratch too
+	if err := AddEntry(configPath, &HostEntry{Host: "db", HostName: "10.0.0.6", ProxyCommand: "ssh -W %h:%p bastion"}); err != nil {
+		t.Fatalf("AddEntry failed: %v", err)
+	}
+	entries, _, err = ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if db := entries[1]; db.ProxyCommand != "ssh -W %h:%p bastion" {
+		t.Errorf("New entry lost its ProxyCommand: %+v", db)
+	}
+}
+
+func TestDetectIndent(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{"no directives", []string{"Host web"}, "    "},
+		{"tabs", []string{"Host web", "\tHostName web.example.com", "\tUser deploy"}, "\t"},
+		{"majority wins", []string{"Host web", "\tHostName web.example.com", "  User deploy", "  Port 2222"}, "  "},
+		{"tie goes to the first", []string{"Host web", "  HostName web.example.com", "\tUser deploy"}, "  "},
+		{"comments don't count", []string{"Host web", "\t# primary", "\t# web server", "  HostName web.example.com"}, "  "},
+		{"unindented directives are skipped", []string{"Host web", "HostName web.example.com", "\tUser deploy"}, "\t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectIndent(tt.lines); got != tt.want {
+				t.Errorf("detectIndent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteConfig_AddedDirectivesUseDominantIndent(t *testing.T) {
+	configContent := "Host web\n\t# web server\n\tHostName web.example.com\n  Port 2222\n  ForwardAgent yes\n"
+	configPath := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, _, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	updated := *entries[0]
+	updated.User = "deploy"
+	if err := UpdateEntry(configPath, "web", &updated); err != nil {
+		t.Fatalf("UpdateEntry failed: %v", err)
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if !strings.Contains(string(content), "\n  User deploy\n") {
+		t.Errorf("Added User should use the dominant two-space indent, got:\n%q", content)
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	// Missing file and missing directory are created on save
+	if err := CheckWritable(configPath); err != nil {
+		t.Errorf("Missing config should be writable, got %v", err)
+	}
+	if err := CheckWritable(filepath.Join(tmpDir, "missing", "config")); err != nil {
+		t.Errorf("Config in a missing directory should be writable, got %v", err)
+	}
+
+	if err := os.WriteFile(configPath, []byte("Host dev\n"), 0600); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+	if err := CheckWritable(configPath); err != nil {
+		t.Errorf("Config should be writable, got %v", err)
+	}
+	if entries, _ := os.ReadDir(tmpDir); len(entries) != 1 {
+		t.Errorf("Check should not leave files behind, got %d entries", len(entries))
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("Permission checks don't apply to root")
+	}
+	if err := os.Chmod(configPath, 0400); err != nil {
+		t.Fatalf("Chmod failed: %v", err)
+	}
+	if err := CheckWritable(configPath); err == nil {
+		t.Error("Expected an error for a read-only config")
+	}
+}
+
+func TestWriteConfig_SetEnv(t *testing.T) {
+	configContent := `Host dev
+    HostName dev.example.com
+    SetEnv FOO=bar
+    Compression yes
+    SetEnv GREETING="hello  world" LANG=C
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	entries, comments, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	entry := entries[0]
+	if len(entry.SetEnv) != 2 || entry.SetEnv[0] != "FOO=bar" || entry.SetEnv[1] != `GREETING="hello  world" LANG=C` {
+		t.Fatalf("SetEnv: got %q", entry.SetEnv)
+	}
+	if len(entry.ExtraDirectives) != 1 {
+		t.Errorf("SetEnv should not be an extra directive, got %q", entry.ExtraDirectives)
+	}
+
+	// Unchanged lines round-trip exactly
+	if err := WriteConfig(configPath, entries, comments); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if string(content) != configContent {
+		t.Errorf("Unchanged entry should round-trip exactly, got:\n%s", content)
+	}
+
+	// Change the first, keep the second and add a third
+	entry.SetEnv = []string{"FOO=baz", entry.SetEnv[1], "TERM=xterm"}
+	if err := WriteConfig(configPath, entries, comments); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+	content, err = os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	expected := `Host dev
+    HostName dev.example.com
+    SetEnv FOO=baz
+    Compression yes
+    SetEnv GREETING="hello  world" LANG=C
+    SetEnv TERM=xterm
+`
+	if string(content) != expected {
+		t.Errorf("Unexpected config, got:\n%s\nwant:\n%s", content, expected)
+	}
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// labelEscaper escapes a label value for the Prometheus text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteMetrics writes the visit data in the Prometheus text exposition format:
+// the visit count per host, plus the last visit time and time connected for
+// hosts that have them
+func WriteMetrics(w io.Writer, entries []HostVisits) error {
+	var b strings.Builder
+
+	b.WriteString("# HELP gosshit_visits_total Connections to the host started from gosshit.\n")
+	b.WriteString("# TYPE gosshit_visits_total counter\n")
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "gosshit_visits_total{host=\"%s\"} %d\n", labelEscaper.Replace(entry.Host), entry.Count)
+	}
+
+	b.WriteString("# HELP gosshit_last_visit_timestamp_seconds Unix time of the last connection to the host.\n")
+	b.WriteString("# TYPE gosshit_last_visit_timestamp_seconds gauge\n")
+	for _, entry := range entries {
+		if !entry.LastVisit.IsZero() {
+			fmt.Fprintf(&b, "gosshit_last_visit_timestamp_seconds{host=\"%s\"} %d\n", labelEscaper.Replace(entry.Host), entry.LastVisit.Unix())
+		}
+	}
+
+	b.WriteString("# HELP gosshit_connected_seconds_total Time spent in ssh sessions to the host.\n")
+	b.WriteString("# TYPE gosshit_connected_seconds_total counter\n")
+	for _, entry := range entries {
+		if entry.TotalDuration > 0 {
+			fmt.Fprintf(&b, "gosshit_connected_seconds_total{host=\"%s\"} %d\n", labelEscaper.Replace(entry.Host), int64(entry.TotalDuration.Seconds()))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
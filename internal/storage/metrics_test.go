@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	entries := []HostVisits{
+		{Host: "web", Count: 12, LastVisit: time.Unix(1700000000, 0), TotalDuration: 90 * time.Second},
+		{Host: `odd"host\name`, Count: 3},
+	}
+
+	var b strings.Builder
+	if err := WriteMetrics(&b, entries); err != nil {
+		t.Fatalf("WriteMetrics failed: %v", err)
+	}
+	got := b.String()
+
+	for _, want := range []string{
+		"# TYPE gosshit_visits_total counter\n",
+		"gosshit_visits_total{host=\"web\"} 12\n",
+		`gosshit_visits_total{host="odd\"host\\name"} 3` + "\n",
+		"gosshit_last_visit_timestamp_seconds{host=\"web\"} 1700000000\n",
+		"gosshit_connected_seconds_total{host=\"web\"} 90\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in metrics:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `gosshit_last_visit_timestamp_seconds{host="odd`) || strings.Contains(got, `gosshit_connected_seconds_total{host="odd`) {
+		t.Errorf("Hosts without a last visit or time connected should only have a visit count:\n%s", got)
+	}
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	notesFileName       = "notes.json"
+	legacyNotesFileName = ".gosshit_notes.json" // Next to a legacy ~/.gosshit tracker
+)
+
+// GetNotesPath returns the path to the notes file, which lives next to the
+// visit tracker (e.g. ~/.local/share/gosshit/notes.json)
+func GetNotesPath() (string, error) {
+	trackerPath, err := GetTrackerPath()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Dir(trackerPath)
+	if filepath.Base(trackerPath) == trackerFileName {
+		return filepath.Join(dir, legacyNotesFileName), nil
+	}
+	return filepath.Join(dir, notesFileName), nil
+}
+
+// Notes holds private per-host notes (keyed by Host alias) that are kept out
+// of the SSH config, e.g. credential hints or ticket links
+type Notes struct {
+	notes map[string]string
+	path  string
+}
+
+// NewNotes creates a new Notes store and loads existing data
+func NewNotes() (*Notes, error) {
+	path, err := GetNotesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create notes directory: %w", err)
+	}
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		if err := migrateLegacyFile(filepath.Join(homeDir, legacyNotesFileName), path, 0600); err != nil {
+			return nil, fmt.Errorf("failed to migrate %s: %w", legacyNotesFileName, err)
+		}
+	}
+
+	notes := &Notes{
+		notes: make(map[string]string),
+		path:  path,
+	}
+
+	if err := notes.Load(); err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// Load reads the notes file into memory. The file is a JSON object mapping
+// each Host to its note.
+func (n *Notes) Load() error {
+	data, err := os.ReadFile(n.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// File doesn't exist yet, that's okay
+			return nil
+		}
+		return fmt.Errorf("failed to open notes file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &n.notes); err != nil {
+		return fmt.Errorf("failed to parse notes file %s: %w", n.path, err)
+	}
+	return nil
+}
+
+// Save writes the notes to the notes file, readable only by the user
+func (n *Notes) Save() error {
+	data, err := json.MarshalIndent(n.notes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode notes: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(n.path), 0700); err != nil {
+		return fmt.Errorf("failed to create notes directory: %w", err)
+	}
+	err = writeFileAtomic(n.path, 0600, func(w io.Writer) error {
+		_, err := w.Write(append(data, '\n'))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write notes file: %w", err)
+	}
+	return nil
+}
+
+// Get returns the note for host ("" if there is none)
+func (n *Notes) Get(host string) string {
+	return n.notes[host]
+}
+
+// Set sets the note for host; a blank note removes it
+func (n *Notes) Set(host, note string) {
+	note = strings.TrimRight(note, " \t\n")
+	if strings.TrimSpace(note) == "" {
+		delete(n.notes, host)
+		return
+	}
+	n.notes[host] = note
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNotes_SaveAndLoad(t *testing.T) {
+	notesPath := filepath.Join(t.TempDir(), "gosshit", "notes.json")
+
+	notes1 := &Notes{notes: make(map[string]string), path: notesPath}
+	notes1.Set("web", "password in 1Password: web-prod\nticket: OPS-123\n\n")
+	notes1.Set("db", "   ")
+
+	if err := notes1.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if info, err := os.Stat(notesPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Notes file should be private (0600), got %v (%v)", info.Mode().Perm(), err)
+	}
+
+	notes2 := &Notes{notes: make(map[string]string), path: notesPath}
+	if err := notes2.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := notes2.Get("web"); got != "password in 1Password: web-prod\nticket: OPS-123" {
+		t.Errorf("web note: got %q", got)
+	}
+	if got := notes2.Get("db"); got != "" {
+		t.Errorf("Blank note should not be stored, got %q", got)
+	}
+
+	// A blank note removes it
+	notes2.Set("web", "")
+	if got := notes2.Get("web"); got != "" {
+		t.Errorf("After removal: got %q, want empty", got)
+	}
+}
+
+func TestNotes_NonExistentFile(t *testing.T) {
+	notes := &Notes{notes: make(map[string]string), path: filepath.Join(t.TempDir(), "missing.json")}
+	if err := notes.Load(); err != nil {
+		t.Errorf("Load of missing file should not fail: %v", err)
+	}
+}
+
+func TestNewNotes_MigratesLegacyFile(t *testing.T) {
+	home := isolateHome(t)
+	legacy := filepath.Join(home, ".gosshit_notes.json")
+	if err := os.WriteFile(legacy, []byte(`{"web": "ticket: OPS-123"}`), 0600); err != nil {
+		t.Fatalf("Failed to create legacy file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".local", "share"), 0755); err != nil {
+		t.Fatalf("Failed to create data home: %v", err)
+	}
+
+	notes, err := NewNotes()
+	if err != nil {
+		t.Fatalf("NewNotes failed: %v", err)
+	}
+	if want := filepath.Join(home, ".local", "share", "gosshit", "notes.json"); notes.path != want {
+		t.Errorf("Notes path: got %q, want %q", notes.path, want)
+	}
+	if got := notes.Get("web"); got != "ticket: OPS-123" {
+		t.Errorf("Migrated note: got %q, want %q", got, "ticket: OPS-123")
+	}
+	if info, err := os.Stat(notes.path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Migrated notes file should stay private (0600), got %v (%v)", info.Mode().Perm(), err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("Legacy file should be removed after migration, got %v", err)
+	}
+}
+
+func TestGetNotesPath(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+	path, err := GetNotesPath()
+	if err != nil {
+		t.Fatalf("GetNotesPath failed: %v", err)
+	}
+	if want := filepath.Join("/data", "gosshit", "notes.json"); path != want {
+		t.Errorf("GetNotesPath: got %q, want %q", path, want)
+	}
+}
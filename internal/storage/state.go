@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	stateFileName       = "state"
+	legacyStateFileName = ".gosshit_state" // Next to a legacy ~/.gosshit tracker
+)
+
+// GetStatePath returns the path to the UI state file, which lives next to the
+// visit tracker (e.g. ~/.local/share/gosshit/state)
+func GetStatePath() (string, error) {
+	trackerPath, err := GetTrackerPath()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Dir(trackerPath)
+	if filepath.Base(trackerPath) == trackerFileName {
+		return filepath.Join(dir, legacyStateFileName), nil
+	}
+	return filepath.Join(dir, stateFileName), nil
+}
+
+// State is a small persistent key/value store for UI state that isn't part
+// of the SSH config (e.g. remembered alternate ports)
+type State struct {
+	values map[string]string
+	path   string
+}
+
+// NewState creates a new State and loads existing data
+func NewState() (*State, error) {
+	path, err := GetStatePath()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		if err := migrateLegacyFile(filepath.Join(homeDir, legacyStateFileName), path, 0644); err != nil {
+			return nil, fmt.Errorf("failed to migrate %s: %w", legacyStateFileName, err)
+		}
+	}
+
+	state := &State{
+		values: make(map[string]string),
+		path:   path,
+	}
+
+	if err := state.Load(); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// Load reads the state file into memory. Each line is "key<TAB>value".
+func (s *State) Load() error {
+	file, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// File doesn't exist yet, that's okay
+			return nil
+		}
+		return fmt.Errorf("failed to open state file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		s.values[key] = value
+	}
+
+	return scanner.Err()
+}
+
+// Save writes the state to the state file, sorted by key
+func (s *State) Save() error {
+	if err := writeFileAtomic(s.path, 0644, s.write); err != nil {
+		return fmt.Errorf("failed to save state file: %w", err)
+	}
+	return nil
+}
+
+// write writes the state file's lines to w
+func (s *State) write(w io.Writer) error {
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "%s\t%s\n", key, s.values[key]); err != nil {
+			return fmt.Errorf("failed to write state entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Get returns the value for key ("" if not set)
+func (s *State) Get(key string) string {
+	return s.values[key]
+}
+
+// Set sets the value for key; an empty value removes it
+func (s *State) Set(key, value string) {
+	if value == "" {
+		delete(s.values, key)
+		return
+	}
+	s.values[key] = value
+}
+
+// AltPortKey returns the state key holding a host's remembered alternate port
+func AltPortKey(host string) string {
+	return "alt_port." + host
+}
+
+// ListLayoutKey is the state key holding the host list layout ("table", "compact" or empty for cards)
+const ListLayoutKey = "list_layout"
+
+// SortModeKey is the state key holding the list sort mode (e.g. "alphabetical";
+// empty for visits)
+const SortModeKey = "sort_mode"
+
+// SortReverseKey is the state key set to "yes" when the sort order is reversed
+const SortReverseKey = "sort_reverse"
+
+// PinnedKey is the state key holding the pinned Hosts, tab-separated in pin order
+const PinnedKey = "pinned"
+
+// LastSelectedKey is the state key holding the Host selected when gosshit last exited
+const LastSelectedKey = "last_selected"
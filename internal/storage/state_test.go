@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestState_SaveAndLoad(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "gosshit_state")
+
+	state1 := &State{values: make(map[string]string), path: statePath}
+	state1.Set(AltPortKey("web"), "2222")
+	state1.Set(AltPortKey("db"), "2200")
+	state1.Set("empty", "")
+
+	if err := state1.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	state2 := &State{values: make(map[string]string), path: statePath}
+	if err := state2.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := state2.Get(AltPortKey("web")); got != "2222" {
+		t.Errorf("web alt port: got %q, want %q", got, "2222")
+	}
+	if got := state2.Get(AltPortKey("db")); got != "2200" {
+		t.Errorf("db alt port: got %q, want %q", got, "2200")
+	}
+	if got := state2.Get("empty"); got != "" {
+		t.Errorf("empty value should not be stored, got %q", got)
+	}
+
+	// Setting an empty value removes the key
+	state2.Set(AltPortKey("web"), "")
+	if got := state2.Get(AltPortKey("web")); got != "" {
+		t.Errorf("After removal: got %q, want empty", got)
+	}
+}
+
+func TestState_NonExistentFile(t *testing.T) {
+	state := &State{values: make(map[string]string), path: filepath.Join(t.TempDir(), "missing")}
+	if err := state.Load(); err != nil {
+		t.Errorf("Load of missing file should not fail: %v", err)
+	}
+	if got := state.Get("anything"); got != "" {
+		t.Errorf("Expected empty value, got %q", got)
+	}
+}
+
+func TestNewState_MigratesLegacyFile(t *testing.T) {
+	home := isolateHome(t)
+	legacy := filepath.Join(home, ".gosshit_state")
+	if err := os.WriteFile(legacy, []byte("sort_mode\talphabetical\n"), 0644); err != nil {
+		t.Fatalf("Failed to create legacy file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".local", "share"), 0755); err != nil {
+		t.Fatalf("Failed to create data home: %v", err)
+	}
+
+	state, err := NewState()
+	if err != nil {
+		t.Fatalf("NewState failed: %v", err)
+	}
+	if want := filepath.Join(home, ".local", "share", "gosshit", "state"); state.path != want {
+		t.Errorf("State path: got %q, want %q", state.path, want)
+	}
+	if got := state.Get(SortModeKey); got != "alphabetical" {
+		t.Errorf("Migrated sort mode: got %q, want %q", got, "alphabetical")
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("Legacy file should be removed after migration, got %v", err)
+	}
+}
+
+func TestGetStatePath(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+	path, err := GetStatePath()
+	if err != nil {
+		t.Fatalf("GetStatePath failed: %v", err)
+	}
+	if want := filepath.Join("/data", "gosshit", "state"); path != want {
+		t.Errorf("GetStatePath: got %q, want %q", path, want)
+	}
+}
+
+func TestState_SaveReplacesFile(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "state")
+	if err := os.WriteFile(statePath, []byte("sort_mode\trecent\n"), 0600); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+
+	state := &State{values: map[string]string{SortModeKey: "alphabetical"}, path: statePath}
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("Failed to read state file: %v", err)
+	}
+	if string(data) != "sort_mode\talphabetical\n" {
+		t.Errorf("Unexpected state file: %q", data)
+	}
+	if info, err := os.Stat(statePath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Save should keep the file's mode, got %v (%v)", info.Mode().Perm(), err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Save should leave no temp files, got %d entries", len(entries))
+	}
+}
@@ -2,31 +2,80 @@ package storage
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
-	trackerFileName = ".gosshit"
+	trackerFileName = ".gosshit" // Legacy location in the home directory
+	dataDirName     = "gosshit"
+	visitsFileName  = "visits"
 )
 
-// GetTrackerPath returns the path to the visit tracker file
+// GetTrackerPath returns the path to the visit tracker file:
+// $XDG_DATA_HOME/gosshit/visits, or ~/.local/share/gosshit/visits when
+// ~/.local/share exists, falling back to the legacy ~/.gosshit
 func GetTrackerPath() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
-	return filepath.Join(homeDir, trackerFileName), nil
+	return trackerPath(homeDir, os.Getenv("XDG_DATA_HOME")), nil
+}
+
+// trackerPath resolves the tracker file location for GetTrackerPath
+func trackerPath(homeDir, xdgDataHome string) string {
+	// The XDG spec says relative paths must be ignored
+	if xdgDataHome != "" && filepath.IsAbs(xdgDataHome) {
+		return filepath.Join(xdgDataHome, dataDirName, visitsFileName)
+	}
+
+	dataHome := filepath.Join(homeDir, ".local", "share")
+	if info, err := os.Stat(dataHome); err == nil && info.IsDir() {
+		return filepath.Join(dataHome, dataDirName, visitsFileName)
+	}
+	return filepath.Join(homeDir, trackerFileName)
+}
+
+// migrateLegacyFile moves a file from its legacy place in the home directory
+// (e.g. ~/.gosshit) to path the first time the new location is used. The
+// copy gets the given mode, like the file's own saves.
+func migrateLegacyFile(legacy, path string, mode os.FileMode) error {
+	if legacy == path {
+		return nil
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil // Already migrated (or created fresh)
+	}
+	data, err := os.ReadFile(legacy)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	// Copy rather than rename so it also works across filesystems
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return err
+	}
+	return os.Remove(legacy)
 }
 
 // VisitTracker manages visit counts for SSH hosts
 type VisitTracker struct {
-	counts map[string]int
-	path   string
+	counts     map[string]int
+	lastVisits map[string]time.Time     // Time of the most recent visit per host
+	durations  map[string]time.Duration // Total time spent connected per host
+	path       string
 }
 
 // NewVisitTracker creates a new VisitTracker and loads existing data
@@ -36,9 +85,21 @@ func NewVisitTracker() (*VisitTracker, error) {
 		return nil, err
 	}
 
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create tracker directory: %w", err)
+	}
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		if err := migrateLegacyFile(filepath.Join(homeDir, trackerFileName), path, 0644); err != nil {
+			return nil, fmt.Errorf("failed to migrate %s: %w", trackerFileName, err)
+		}
+	}
+
 	tracker := &VisitTracker{
-		counts: make(map[string]int),
-		path:   path,
+		counts:     make(map[string]int),
+		lastVisits: make(map[string]time.Time),
+		durations:  make(map[string]time.Duration),
+		path:       path,
 	}
 
 	if err := tracker.Load(); err != nil {
@@ -48,7 +109,10 @@ func NewVisitTracker() (*VisitTracker, error) {
 	return tracker, nil
 }
 
-// Load reads the tracker file and loads visit counts into memory
+// Load reads the tracker file and loads visit counts into memory.
+// Lines are "host:count", optionally followed by a tab and the Unix time of
+// the last visit (0 if unknown) and another tab and the total seconds spent
+// connected. Files written by older versions lack one or both fields.
 func (vt *VisitTracker) Load() error {
 	file, err := os.Open(vt.path)
 	if err != nil {
@@ -67,61 +131,167 @@ func (vt *VisitTracker) Load() error {
 			continue
 		}
 
-		parts := strings.Split(line, ":")
-		if len(parts) != 2 {
+		var lastVisit time.Time
+		var duration time.Duration
+		fields := strings.Split(line, "\t")
+		line = strings.TrimSpace(fields[0])
+		if len(fields) > 1 {
+			if unix, err := strconv.ParseInt(strings.TrimSpace(fields[1]), 10, 64); err == nil && unix > 0 {
+				lastVisit = time.Unix(unix, 0)
+			}
+		}
+		if len(fields) > 2 {
+			if seconds, err := strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 64); err == nil && seconds > 0 {
+				duration = time.Duration(min(seconds, math.MaxInt64/int64(time.Second))) * time.Second
+			}
+		}
+
+		// The count never contains a colon, so split on the last one; host
+		// aliases may contain colons themselves (e.g. IPv6-like aliases)
+		sep := strings.LastIndex(line, ":")
+		if sep <= 0 {
 			continue
 		}
 
-		host := strings.TrimSpace(parts[0])
-		count, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		host := strings.TrimSpace(line[:sep])
+		count, err := strconv.Atoi(strings.TrimSpace(line[sep+1:]))
 		if err != nil {
-			continue
+			// Out-of-range values are clamped to the int limits by Atoi
+			if !errors.Is(err, strconv.ErrRange) {
+				continue
+			}
+		}
+		if count < 0 {
+			count = 0
 		}
 
 		vt.counts[host] = count
+		if !lastVisit.IsZero() {
+			vt.setLastVisit(host, lastVisit)
+		}
+		if duration > 0 {
+			vt.AddDuration(host, duration)
+		}
 	}
 
 	return scanner.Err()
 }
 
-// Save writes the visit counts to the tracker file
+// Save writes the visit counts to the tracker file, one "host:count" per line
+// followed by a tab and the last visit's Unix time when known, and by another
+// tab and the total seconds connected when any were recorded. The file is
+// replaced atomically, so a failed save keeps the previous visit data.
 func (vt *VisitTracker) Save() error {
-	file, err := os.Create(vt.path)
-	if err != nil {
-		return fmt.Errorf("failed to create tracker file: %w", err)
+	if err := writeFileAtomic(vt.path, 0644, vt.write); err != nil {
+		return fmt.Errorf("failed to save tracker file: %w", err)
 	}
-	defer file.Close()
+	return nil
+}
 
+// write writes the tracker file's lines to w
+func (vt *VisitTracker) write(w io.Writer) error {
 	// Sort by count (descending) for consistent output
-	type hostCount struct {
-		host  string
-		count int
+	for _, entry := range vt.Entries() {
+		line := fmt.Sprintf("%s:%d", entry.Host, entry.Count)
+		if !entry.LastVisit.IsZero() || entry.TotalDuration > 0 {
+			var unix int64
+			if !entry.LastVisit.IsZero() {
+				unix = entry.LastVisit.Unix()
+			}
+			line += fmt.Sprintf("\t%d", unix)
+		}
+		if entry.TotalDuration > 0 {
+			line += fmt.Sprintf("\t%d", int64(entry.TotalDuration/time.Second))
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("failed to write tracker entry: %w", err)
+		}
 	}
 
-	var entries []hostCount
+	return nil
+}
+
+// HostVisits is the stored visit data for a single host
+type HostVisits struct {
+	Host          string
+	Count         int
+	LastVisit     time.Time     // Zero if unknown
+	TotalDuration time.Duration // Time spent connected, zero if never recorded
+}
+
+// Entries returns all tracked hosts sorted by count (descending), then by name
+func (vt *VisitTracker) Entries() []HostVisits {
+	var entries []HostVisits
 	for host, count := range vt.counts {
-		entries = append(entries, hostCount{host, count})
+		entries = append(entries, HostVisits{Host: host, Count: count, LastVisit: vt.lastVisits[host], TotalDuration: vt.durations[host]})
 	}
 
-	sort.Slice(entries, func(i, j int) bool {
-		if entries[i].count == entries[j].count {
-			return entries[i].host < entries[j].host
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Count == entries[j].Count {
+			return entries[i].Host < entries[j].Host
 		}
-		return entries[i].count > entries[j].count
+		return entries[i].Count > entries[j].Count
 	})
 
-	for _, entry := range entries {
-		if _, err := fmt.Fprintf(file, "%s:%d\n", entry.host, entry.count); err != nil {
-			return fmt.Errorf("failed to write tracker entry: %w", err)
-		}
+	return entries
+}
+
+// Increment increments the visit count for a host, saturating at math.MaxInt,
+// and records the visit time
+func (vt *VisitTracker) Increment(host string) {
+	if vt.counts[host] < math.MaxInt {
+		vt.counts[host]++
 	}
+	vt.setLastVisit(host, time.Now())
+}
 
-	return nil
+func (vt *VisitTracker) setLastVisit(host string, t time.Time) {
+	if vt.lastVisits == nil {
+		vt.lastVisits = make(map[string]time.Time)
+	}
+	vt.lastVisits[host] = t
 }
 
-// Increment increments the visit count for a host
-func (vt *VisitTracker) Increment(host string) {
-	vt.counts[host]++
+// GetLastVisit returns the time of the host's most recent visit (zero if never visited
+// or only visited by a version that didn't record times)
+func (vt *VisitTracker) GetLastVisit(host string) time.Time {
+	return vt.lastVisits[host]
+}
+
+// AddDuration adds the length of a finished session to the host's total
+// connected time, saturating instead of overflowing
+func (vt *VisitTracker) AddDuration(host string, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	if vt.durations == nil {
+		vt.durations = make(map[string]time.Duration)
+	}
+	if _, ok := vt.counts[host]; !ok {
+		vt.counts[host] = 0
+	}
+	total := vt.durations[host]
+	if total > math.MaxInt64-d {
+		vt.durations[host] = math.MaxInt64
+		return
+	}
+	vt.durations[host] = total + d
+}
+
+// GetTotalDuration returns the total time spent connected to the host (zero
+// if no session was recorded)
+func (vt *VisitTracker) GetTotalDuration(host string) time.Duration {
+	return vt.durations[host]
+}
+
+// SortByRecent sorts a slice of host names by last visit (most recent first).
+// Hosts without a recorded visit follow, ordered by visit count.
+func (vt *VisitTracker) SortByRecent(hosts []string) []string {
+	result := vt.SortByVisits(hosts)
+	sort.SliceStable(result, func(i, j int) bool {
+		return vt.GetLastVisit(result[i]).After(vt.GetLastVisit(result[j]))
+	})
+	return result
 }
 
 // GetCount returns the visit count for a host (0 if not found)
@@ -129,23 +299,29 @@ func (vt *VisitTracker) GetCount(host string) int {
 	return vt.counts[host]
 }
 
-// SortByVisits sorts a slice of host names by visit count (descending)
+// SortByVisits sorts a slice of host names by visit count (descending).
+// Equal counts are ordered by last visit (most recent first), then by name.
 func (vt *VisitTracker) SortByVisits(hosts []string) []string {
 	type hostWithCount struct {
-		host  string
-		count int
+		host      string
+		count     int
+		lastVisit time.Time
 	}
 
 	var hostsWithCounts []hostWithCount
 	for _, host := range hosts {
 		hostsWithCounts = append(hostsWithCounts, hostWithCount{
-			host:  host,
-			count: vt.GetCount(host),
+			host:      host,
+			count:     vt.GetCount(host),
+			lastVisit: vt.GetLastVisit(host),
 		})
 	}
 
-	sort.Slice(hostsWithCounts, func(i, j int) bool {
+	sort.SliceStable(hostsWithCounts, func(i, j int) bool {
 		if hostsWithCounts[i].count == hostsWithCounts[j].count {
+			if !hostsWithCounts[i].lastVisit.Equal(hostsWithCounts[j].lastVisit) {
+				return hostsWithCounts[i].lastVisit.After(hostsWithCounts[j].lastVisit)
+			}
 			return hostsWithCounts[i].host < hostsWithCounts[j].host
 		}
 		return hostsWithCounts[i].count > hostsWithCounts[j].count
@@ -162,5 +338,120 @@ func (vt *VisitTracker) SortByVisits(hosts []string) []string {
 // ClearAll clears all visit counts and saves to file
 func (vt *VisitTracker) ClearAll() error {
 	vt.counts = make(map[string]int)
+	vt.lastVisits = make(map[string]time.Time)
+	vt.durations = make(map[string]time.Duration)
 	return vt.Save()
 }
+
+// Prune forgets every tracked host that isn't in hosts (e.g. hosts deleted
+// from the config) and returns how many were removed. Call Save to persist.
+func (vt *VisitTracker) Prune(hosts []string) int {
+	keep := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		keep[host] = true
+	}
+
+	tracked := make(map[string]bool)
+	for host := range vt.counts {
+		tracked[host] = true
+	}
+	for host := range vt.lastVisits {
+		tracked[host] = true
+	}
+	for host := range vt.durations {
+		tracked[host] = true
+	}
+
+	removed := 0
+	for host := range tracked {
+		if keep[host] {
+			continue
+		}
+		delete(vt.counts, host)
+		delete(vt.lastVisits, host)
+		delete(vt.durations, host)
+		removed++
+	}
+	return removed
+}
+
+// FormatDuration returns a short human-readable duration rounded to the two
+// largest units (e.g. 45s, 12m 5s, 3h 20m, 2d 4h)
+func FormatDuration(d time.Duration) string {
+	seconds := int64(d / time.Second)
+	if seconds < 60 {
+		return fmt.Sprintf("%ds", max(seconds, 0))
+	}
+
+	units := []struct {
+		suffix  string
+		seconds int64
+	}{{"d", 86400}, {"h", 3600}, {"m", 60}, {"s", 1}}
+	for i, unit := range units[:len(units)-1] {
+		if seconds < unit.seconds {
+			continue
+		}
+		next := units[i+1]
+		major := seconds / unit.seconds
+		minor := seconds % unit.seconds / next.seconds
+		if minor == 0 {
+			return fmt.Sprintf("%d%s", major, unit.suffix)
+		}
+		return fmt.Sprintf("%d%s %d%s", major, unit.suffix, minor, next.suffix)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+// FormatRelativeTime returns how long before now t was, in the largest whole
+// unit (e.g. just now, 1 minute ago, 3 hours ago, 2 years ago). Times in the
+// future, e.g. after a clock change, count as just now.
+func FormatRelativeTime(t, now time.Time) string {
+	elapsed := now.Sub(t)
+	if elapsed < time.Minute {
+		return "just now"
+	}
+
+	units := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"week", 7 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for _, unit := range units {
+		n := int64(elapsed / unit.duration)
+		if n == 0 {
+			continue
+		}
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit.name)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit.name)
+	}
+	return "just now"
+}
+
+// FormatCount returns a short human-readable visit count (e.g. 999, 1.2k, 3.4M)
+func FormatCount(count int) string {
+	if count < 1000 {
+		return strconv.Itoa(count)
+	}
+
+	units := []string{"k", "M", "B", "T", "P", "E"}
+	value := float64(count) / 1000
+	unit := 0
+	// Move to the next unit when rounding would print e.g. "1000k"
+	for value >= 999.5 && unit < len(units)-1 {
+		value /= 1000
+		unit++
+	}
+
+	if value >= 100 {
+		return fmt.Sprintf("%.0f%s", value, units[unit])
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0") + units[unit]
+}
@@ -1,12 +1,28 @@
 package storage
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
+// isolateHome points HOME at a temp dir so NewVisitTracker never creates,
+// migrates or reads the real tracker files
+func isolateHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+	return home
+}
+
 func TestVisitTracker_Increment(t *testing.T) {
+	isolateHome(t)
 	tmpDir := t.TempDir()
 	trackerPath := filepath.Join(tmpDir, "gosshit")
 
@@ -38,6 +54,7 @@ func TestVisitTracker_Increment(t *testing.T) {
 }
 
 func TestVisitTracker_GetCount(t *testing.T) {
+	isolateHome(t)
 	tmpDir := t.TempDir()
 	trackerPath := filepath.Join(tmpDir, "gosshit")
 
@@ -61,6 +78,7 @@ func TestVisitTracker_GetCount(t *testing.T) {
 }
 
 func TestVisitTracker_SaveAndLoad(t *testing.T) {
+	isolateHome(t)
 	tmpDir := t.TempDir()
 	trackerPath := filepath.Join(tmpDir, "gosshit")
 
@@ -113,6 +131,7 @@ func TestVisitTracker_SaveAndLoad(t *testing.T) {
 }
 
 func TestVisitTracker_SortByVisits(t *testing.T) {
+	isolateHome(t)
 	tmpDir := t.TempDir()
 	trackerPath := filepath.Join(tmpDir, "gosshit")
 
@@ -152,7 +171,30 @@ func TestVisitTracker_SortByVisits(t *testing.T) {
 	}
 }
 
+func TestVisitTracker_SortByVisitsTieBreak(t *testing.T) {
+	now := time.Now()
+	tracker := &VisitTracker{
+		counts: map[string]int{"alpha": 2, "beta": 2, "gamma": 2, "delta": 5, "zeta": 0, "eta": 0},
+		lastVisits: map[string]time.Time{
+			"alpha": now.Add(-time.Hour),
+			"gamma": now,
+			"eta":   now.Add(-time.Minute),
+		},
+		path: filepath.Join(t.TempDir(), "gosshit"),
+	}
+
+	sorted := tracker.SortByVisits([]string{"zeta", "beta", "alpha", "gamma", "eta", "delta"})
+	// Equal counts: most recent visit first, unvisited hosts alphabetical last
+	expected := []string{"delta", "gamma", "alpha", "beta", "eta", "zeta"}
+	for i, host := range expected {
+		if sorted[i] != host {
+			t.Errorf("Position %d: got %q, want %q", i, sorted[i], host)
+		}
+	}
+}
+
 func TestVisitTracker_EmptyFile(t *testing.T) {
+	isolateHome(t)
 	tmpDir := t.TempDir()
 	trackerPath := filepath.Join(tmpDir, "gosshit")
 
@@ -180,6 +222,7 @@ func TestVisitTracker_EmptyFile(t *testing.T) {
 }
 
 func TestVisitTracker_NonExistentDirectory(t *testing.T) {
+	isolateHome(t)
 	// Use a temporary directory path
 	tmpDir := t.TempDir()
 	trackerPath := filepath.Join(tmpDir, "nonexistent_subdir", "gosshit")
@@ -197,6 +240,7 @@ func TestVisitTracker_NonExistentDirectory(t *testing.T) {
 }
 
 func TestVisitTracker_ClearAllSavesToFile(t *testing.T) {
+	isolateHome(t)
 	tmpDir := t.TempDir()
 	trackerPath := filepath.Join(tmpDir, "gosshit")
 
@@ -241,3 +285,458 @@ func TestVisitTracker_ClearAllSavesToFile(t *testing.T) {
 		t.Errorf("After ClearAll and reload, host2 count: got %d, want 0", got)
 	}
 }
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		count int
+		want  string
+	}{
+		{0, "0"},
+		{42, "42"},
+		{999, "999"},
+		{1000, "1k"},
+		{1234, "1.2k"},
+		{99999, "100k"},
+		{999999, "1M"},
+		{3400000, "3.4M"},
+		{math.MaxInt32, "2.1B"},
+		{math.MaxInt, "9.2E"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatCount(tt.count); got != tt.want {
+			t.Errorf("FormatCount(%d) = %q, want %q", tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{0, "just now"},
+		{59 * time.Second, "just now"},
+		{-time.Hour, "just now"},
+		{time.Minute, "1 minute ago"},
+		{45 * time.Minute, "45 minutes ago"},
+		{3*time.Hour + 59*time.Minute, "3 hours ago"},
+		{25 * time.Hour, "1 day ago"},
+		{15 * 24 * time.Hour, "2 weeks ago"},
+		{90 * 24 * time.Hour, "3 months ago"},
+		{800 * 24 * time.Hour, "2 years ago"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatRelativeTime(now.Add(-tt.ago), now); got != tt.want {
+			t.Errorf("FormatRelativeTime(now - %v) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
+
+func TestVisitTracker_LargeCounts(t *testing.T) {
+	tmpDir := t.TempDir()
+	trackerPath := filepath.Join(tmpDir, "gosshit")
+
+	content := fmt.Sprintf("huge:%d\nbig:%d\ntie:%d\nover:99999999999999999999999\nnegative:-5\n",
+		math.MaxInt, math.MaxInt-1, math.MaxInt-1)
+	if err := os.WriteFile(trackerPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create tracker file: %v", err)
+	}
+
+	tracker := &VisitTracker{counts: make(map[string]int), path: trackerPath}
+	if err := tracker.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := tracker.GetCount("over"); got != math.MaxInt {
+		t.Errorf("Out-of-range count should clamp: got %d, want %d", got, math.MaxInt)
+	}
+	if got := tracker.GetCount("negative"); got != 0 {
+		t.Errorf("Negative count should clamp to 0: got %d", got)
+	}
+
+	// Incrementing at the limit must not wrap around
+	tracker.Increment("huge")
+	if got := tracker.GetCount("huge"); got != math.MaxInt {
+		t.Errorf("Increment at max: got %d, want %d", got, math.MaxInt)
+	}
+
+	sorted := tracker.SortByVisits([]string{"tie", "negative", "big", "huge", "over"})
+	expected := []string{"huge", "over", "big", "tie", "negative"}
+	for i, host := range expected {
+		if sorted[i] != host {
+			t.Errorf("Position %d: got %q, want %q", i, sorted[i], host)
+		}
+	}
+}
+
+func TestVisitTracker_Entries(t *testing.T) {
+	tracker := &VisitTracker{counts: make(map[string]int), path: filepath.Join(t.TempDir(), "gosshit")}
+
+	tracker.Increment("b")
+	tracker.Increment("a")
+	tracker.Increment("c")
+	tracker.Increment("c")
+
+	entries := tracker.Entries()
+	want := []HostVisits{{Host: "c", Count: 2}, {Host: "a", Count: 1}, {Host: "b", Count: 1}}
+	if len(entries) != len(want) {
+		t.Fatalf("Got %d entries, want %d", len(entries), len(want))
+	}
+	for i := range want {
+		if entries[i].Host != want[i].Host || entries[i].Count != want[i].Count {
+			t.Errorf("Entry %d: got %+v, want %+v", i, entries[i], want[i])
+		}
+		if entries[i].LastVisit.IsZero() {
+			t.Errorf("Entry %d: LastVisit should be set after Increment", i)
+		}
+	}
+}
+
+func TestVisitTracker_ColonInHost(t *testing.T) {
+	tmpDir := t.TempDir()
+	trackerPath := filepath.Join(tmpDir, "gosshit")
+
+	// Existing files written before colon support keep loading
+	content := "plain:3\nfe80::1:7\n:4\nbroken\n"
+	if err := os.WriteFile(trackerPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create tracker file: %v", err)
+	}
+
+	tracker := &VisitTracker{counts: make(map[string]int), path: trackerPath}
+	if err := tracker.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := tracker.GetCount("plain"); got != 3 {
+		t.Errorf("plain: got %d, want 3", got)
+	}
+	if got := tracker.GetCount("fe80::1"); got != 7 {
+		t.Errorf("fe80::1: got %d, want 7", got)
+	}
+	if len(tracker.counts) != 2 {
+		t.Errorf("Expected 2 tracked hosts, got %v", tracker.counts)
+	}
+
+	tracker.Increment("db:primary")
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reloaded := &VisitTracker{counts: make(map[string]int), path: trackerPath}
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if got := reloaded.GetCount("db:primary"); got != 1 {
+		t.Errorf("db:primary after round-trip: got %d, want 1", got)
+	}
+	if got := reloaded.GetCount("fe80::1"); got != 7 {
+		t.Errorf("fe80::1 after round-trip: got %d, want 7", got)
+	}
+}
+
+func TestVisitTracker_LastVisit(t *testing.T) {
+	tmpDir := t.TempDir()
+	trackerPath := filepath.Join(tmpDir, "gosshit")
+
+	// Old "host:count" lines load without a timestamp
+	content := "old:10\nrecent:2\t1700000200\nolder:5\t1700000100\nfe80::1:1\t1700000300\n"
+	if err := os.WriteFile(trackerPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create tracker file: %v", err)
+	}
+
+	tracker := &VisitTracker{counts: make(map[string]int), path: trackerPath}
+	if err := tracker.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := tracker.GetCount("recent"); got != 2 {
+		t.Errorf("recent count: got %d, want 2", got)
+	}
+	if got := tracker.GetLastVisit("recent"); !got.Equal(time.Unix(1700000200, 0)) {
+		t.Errorf("recent last visit: got %v", got)
+	}
+	if got := tracker.GetLastVisit("fe80::1"); !got.Equal(time.Unix(1700000300, 0)) {
+		t.Errorf("fe80::1 last visit: got %v", got)
+	}
+	if got := tracker.GetLastVisit("old"); !got.IsZero() {
+		t.Errorf("old should have no last visit, got %v", got)
+	}
+
+	sorted := tracker.SortByRecent([]string{"never", "old", "older", "recent", "fe80::1"})
+	expected := []string{"fe80::1", "recent", "older", "old", "never"}
+	for i, host := range expected {
+		if sorted[i] != host {
+			t.Errorf("Position %d: got %q, want %q", i, sorted[i], host)
+		}
+	}
+
+	before := time.Now().Add(-time.Second)
+	tracker.Increment("old")
+	if got := tracker.GetLastVisit("old"); got.Before(before) {
+		t.Errorf("Increment should record the visit time, got %v", got)
+	}
+
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	reloaded := &VisitTracker{counts: make(map[string]int), path: trackerPath}
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if got := reloaded.GetLastVisit("old").Unix(); got != tracker.GetLastVisit("old").Unix() {
+		t.Errorf("Last visit after round-trip: got %d, want %d", got, tracker.GetLastVisit("old").Unix())
+	}
+	if got := reloaded.GetCount("old"); got != 11 {
+		t.Errorf("Count after round-trip: got %d, want 11", got)
+	}
+}
+
+func TestTrackerPath(t *testing.T) {
+	homeDir := t.TempDir()
+	legacy := filepath.Join(homeDir, ".gosshit")
+
+	// No XDG_DATA_HOME and no ~/.local/share: legacy location
+	if got := trackerPath(homeDir, ""); got != legacy {
+		t.Errorf("Without data dir: got %q, want %q", got, legacy)
+	}
+
+	// Relative XDG_DATA_HOME is ignored
+	if got := trackerPath(homeDir, "relative/data"); got != legacy {
+		t.Errorf("Relative XDG_DATA_HOME: got %q, want %q", got, legacy)
+	}
+
+	xdg := filepath.Join(homeDir, "xdg")
+	if got, want := trackerPath(homeDir, xdg), filepath.Join(xdg, "gosshit", "visits"); got != want {
+		t.Errorf("XDG_DATA_HOME: got %q, want %q", got, want)
+	}
+
+	dataHome := filepath.Join(homeDir, ".local", "share")
+	if err := os.MkdirAll(dataHome, 0755); err != nil {
+		t.Fatalf("Failed to create data home: %v", err)
+	}
+	if got, want := trackerPath(homeDir, ""), filepath.Join(dataHome, "gosshit", "visits"); got != want {
+		t.Errorf("~/.local/share: got %q, want %q", got, want)
+	}
+}
+
+func TestMigrateLegacyFile(t *testing.T) {
+	homeDir := t.TempDir()
+	legacy := filepath.Join(homeDir, ".gosshit")
+	path := filepath.Join(homeDir, "data", "gosshit", "visits")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("Failed to create tracker dir: %v", err)
+	}
+
+	// Nothing to migrate
+	if err := migrateLegacyFile(legacy, path, 0644); err != nil {
+		t.Fatalf("migrate without legacy file: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("No tracker file should be created, got %v", err)
+	}
+
+	if err := os.WriteFile(legacy, []byte("prod:42\n"), 0644); err != nil {
+		t.Fatalf("Failed to create legacy file: %v", err)
+	}
+	if err := migrateLegacyFile(legacy, path, 0644); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+
+	tracker := &VisitTracker{counts: make(map[string]int), path: path}
+	if err := tracker.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := tracker.GetCount("prod"); got != 42 {
+		t.Errorf("Migrated count: got %d, want 42", got)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("Legacy file should be removed after migration, got %v", err)
+	}
+
+	// An existing new file is never overwritten
+	if err := os.WriteFile(legacy, []byte("prod:1\n"), 0644); err != nil {
+		t.Fatalf("Failed to recreate legacy file: %v", err)
+	}
+	if err := migrateLegacyFile(legacy, path, 0644); err != nil {
+		t.Fatalf("second migrate failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "prod:42\n" {
+		t.Errorf("Existing tracker was overwritten: %q", data)
+	}
+}
+
+func TestNewVisitTracker_MigratesLegacyFile(t *testing.T) {
+	home := isolateHome(t)
+	legacy := filepath.Join(home, ".gosshit")
+	if err := os.WriteFile(legacy, []byte("prod:42\n"), 0644); err != nil {
+		t.Fatalf("Failed to create legacy file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".local", "share"), 0755); err != nil {
+		t.Fatalf("Failed to create data home: %v", err)
+	}
+
+	tracker, err := NewVisitTracker()
+	if err != nil {
+		t.Fatalf("NewVisitTracker failed: %v", err)
+	}
+	if want := filepath.Join(home, ".local", "share", "gosshit", "visits"); tracker.path != want {
+		t.Errorf("Tracker path: got %q, want %q", tracker.path, want)
+	}
+	if got := tracker.GetCount("prod"); got != 42 {
+		t.Errorf("Migrated count: got %d, want 42", got)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("Legacy file should be removed after migration, got %v", err)
+	}
+}
+
+func TestVisitTracker_Durations(t *testing.T) {
+	trackerPath := filepath.Join(t.TempDir(), "gosshit")
+
+	// Older lines without a duration still load
+	content := "old:3\t1700000100\nnever:1\nlong:2\t1700000200\t7200\nnovisit:0\t0\t90\n"
+	if err := os.WriteFile(trackerPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create tracker file: %v", err)
+	}
+
+	tracker := &VisitTracker{counts: make(map[string]int), path: trackerPath}
+	if err := tracker.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := tracker.GetTotalDuration("long"); got != 2*time.Hour {
+		t.Errorf("long duration: got %v, want 2h", got)
+	}
+	if got := tracker.GetLastVisit("long"); !got.Equal(time.Unix(1700000200, 0)) {
+		t.Errorf("long last visit: got %v", got)
+	}
+	if got := tracker.GetTotalDuration("novisit"); got != 90*time.Second {
+		t.Errorf("novisit duration: got %v, want 90s", got)
+	}
+	if got := tracker.GetTotalDuration("old"); got != 0 {
+		t.Errorf("old should have no duration, got %v", got)
+	}
+
+	tracker.AddDuration("old", 90*time.Second)
+	tracker.AddDuration("old", 30*time.Second)
+	tracker.AddDuration("old", -time.Minute)
+	if got := tracker.GetTotalDuration("old"); got != 2*time.Minute {
+		t.Errorf("old duration after adding: got %v, want 2m", got)
+	}
+
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	reloaded := &VisitTracker{counts: make(map[string]int), path: trackerPath}
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	for _, host := range []string{"old", "never", "long", "novisit"} {
+		if got, want := reloaded.GetTotalDuration(host), tracker.GetTotalDuration(host); got != want {
+			t.Errorf("%s duration after round-trip: got %v, want %v", host, got, want)
+		}
+		if got, want := reloaded.GetLastVisit(host), tracker.GetLastVisit(host); !got.Equal(want) {
+			t.Errorf("%s last visit after round-trip: got %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{45 * time.Second, "45s"},
+		{time.Minute, "1m"},
+		{12*time.Minute + 5*time.Second, "12m 5s"},
+		{3*time.Hour + 20*time.Minute + 10*time.Second, "3h 20m"},
+		{2*24*time.Hour + 4*time.Hour + 59*time.Minute, "2d 4h"},
+		{48 * time.Hour, "2d"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestVisitTracker_Prune(t *testing.T) {
+	tracker := &VisitTracker{
+		counts:     map[string]int{"kept": 5, "deleted": 3, "web web2": 1},
+		lastVisits: map[string]time.Time{"deleted": time.Unix(1700000000, 0), "only-visited": time.Unix(1700000000, 0)},
+		durations:  map[string]time.Duration{"kept": time.Minute, "deleted": time.Hour},
+		path:       filepath.Join(t.TempDir(), "visits"),
+	}
+
+	if removed := tracker.Prune([]string{"kept", "web web2", "new"}); removed != 2 {
+		t.Errorf("Expected 2 hosts pruned, got %d", removed)
+	}
+	if tracker.GetCount("kept") != 5 || tracker.GetTotalDuration("kept") != time.Minute || tracker.GetCount("web web2") != 1 {
+		t.Errorf("Existing hosts should keep their data: %+v", tracker.Entries())
+	}
+	if tracker.GetCount("deleted") != 0 || !tracker.GetLastVisit("deleted").IsZero() || tracker.GetTotalDuration("deleted") != 0 {
+		t.Errorf("Deleted host should be forgotten")
+	}
+	if !tracker.GetLastVisit("only-visited").IsZero() {
+		t.Errorf("Host with only a last visit should be forgotten too")
+	}
+	if removed := tracker.Prune([]string{"kept", "web web2"}); removed != 0 {
+		t.Errorf("Second prune should remove nothing, got %d", removed)
+	}
+}
+
+func TestVisitTracker_SaveIsAtomic(t *testing.T) {
+	tmpDir := t.TempDir()
+	trackerPath := filepath.Join(tmpDir, "visits")
+	original := "web:5\t1700000000\n"
+	if err := os.WriteFile(trackerPath, []byte(original), 0640); err != nil {
+		t.Fatalf("Failed to write tracker file: %v", err)
+	}
+
+	vt := &VisitTracker{
+		counts:     map[string]int{"web": 6, "db": 1},
+		lastVisits: map[string]time.Time{},
+		durations:  map[string]time.Duration{},
+		path:       trackerPath,
+	}
+
+	// A write that fails halfway must leave the previous file untouched
+	err := writeFileAtomic(trackerPath, 0644, func(w io.Writer) error {
+		if err := vt.write(w); err != nil {
+			return err
+		}
+		return errors.New("disk full")
+	})
+	if err == nil {
+		t.Fatal("Expected the write error to be returned")
+	}
+	data, err := os.ReadFile(trackerPath)
+	if err != nil {
+		t.Fatalf("Failed to read tracker file: %v", err)
+	}
+	if string(data) != original {
+		t.Errorf("Original tracker file should be intact, got %q", data)
+	}
+
+	// A successful save replaces it, keeping its mode, and leaves no temp files
+	if err := vt.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, err = os.ReadFile(trackerPath)
+	if err != nil {
+		t.Fatalf("Failed to read tracker file: %v", err)
+	}
+	if string(data) != "web:6\ndb:1\n" {
+		t.Errorf("Unexpected tracker file: %q", data)
+	}
+	if info, err := os.Stat(trackerPath); err != nil || info.Mode().Perm() != 0640 {
+		t.Errorf("Tracker file mode should be kept, got %v (err %v)", info.Mode().Perm(), err)
+	}
+	if files, _ := os.ReadDir(tmpDir); len(files) != 1 {
+		t.Errorf("Temporary files left behind: %d entries", len(files))
+	}
+}
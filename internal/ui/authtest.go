@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// authTestTimeout bounds the whole auth test; ssh's own ConnectTimeout only
+// covers establishing the TCP connection
+const authTestTimeout = 15 * time.Second
+
+// authResult describes the outcome of a non-interactive ssh login
+type authResult int
+
+const (
+	authOK      authResult = iota // Logged in and ran `true`
+	authFailed                    // ssh or the remote command failed
+	authTimeout                   // Connecting (or the whole test) timed out
+)
+
+// String returns the short label shown in the status bar
+func (r authResult) String() string {
+	switch r {
+	case authOK:
+		return "auth ok"
+	case authTimeout:
+		return "timeout"
+	default:
+		return "auth failed"
+	}
+}
+
+// authTestMsg carries the result of an auth test
+type authTestMsg struct {
+	host   string
+	result authResult
+	detail string // Last line of ssh's error output, if any
+}
+
+// authTestArgs returns the ssh arguments for a BatchMode login to alias that
+// runs `true` and exits
+func authTestArgs(alias string) []string {
+	return []string{"-o", "BatchMode=yes", "-o", "ConnectTimeout=5", alias, "true"}
+}
+
+// testAuth runs `ssh -o BatchMode=yes -o ConnectTimeout=5 <alias> true` for
+// host in the background
+func testAuth(host string, sshArgs []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), authTestTimeout)
+		defer cancel()
+
+		out, err := exec.CommandContext(ctx, "ssh", sshArgs...).CombinedOutput()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return authTestMsg{host: host, result: authTimeout}
+		}
+		result, detail := classifyAuthTest(err, string(out))
+		return authTestMsg{host: host, result: result, detail: detail}
+	}
+}
+
+// classifyAuthTest maps the exit status and output of the ssh login to a result
+func classifyAuthTest(err error, output string) (authResult, string) {
+	if err == nil {
+		return authOK, ""
+	}
+
+	detail := lastLine(output)
+	lower := strings.ToLower(output)
+	if strings.Contains(lower, "timed out") || strings.Contains(lower, "timeout") {
+		return authTimeout, detail
+	}
+	return authFailed, detail
+}
+
+// lastLine returns the last non-empty line of s, trimmed
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
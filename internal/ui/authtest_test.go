@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTestAuth(t *testing.T) {
+	// Fake ssh that behaves according to the alias it was asked about
+	bin := t.TempDir()
+	script := `#!/bin/sh
+for arg; do alias=$prev; prev=$arg; done
+case "$alias" in
+ok) exit 0 ;;
+slow) echo "ssh: connect to host slow port 22: Connection timed out" >&2; exit 255 ;;
+*) echo "user@$alias: Permission denied (publickey)." >&2; exit 255 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "ssh"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create fake ssh: %v", err)
+	}
+	t.Setenv("PATH", bin)
+
+	tests := []struct {
+		alias  string
+		result authResult
+		detail string
+	}{
+		{"ok", authOK, ""},
+		{"slow", authTimeout, "ssh: connect to host slow port 22: Connection timed out"},
+		{"denied", authFailed, "user@denied: Permission denied (publickey)."},
+	}
+	for _, tt := range tests {
+		msg := testAuth(tt.alias, authTestArgs(tt.alias))().(authTestMsg)
+		if msg.host != tt.alias || msg.result != tt.result || msg.detail != tt.detail {
+			t.Errorf("%s: got %+v, want result %q detail %q", tt.alias, msg, tt.result, tt.detail)
+		}
+	}
+}
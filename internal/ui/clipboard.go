@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// writeClipboard copies text to the system clipboard (replaced in tests)
+var writeClipboard = clipboard.WriteAll
+
+// clipboardMsg carries the result of copying text to the clipboard
+type clipboardMsg struct {
+	what string // What was copied, e.g. "ssh command"
+	text string
+	err  error
+}
+
+// copyToClipboard copies text in the background; what names it in the
+// status message
+func copyToClipboard(what, text string) tea.Cmd {
+	return func() tea.Msg {
+		return clipboardMsg{what: what, text: text, err: writeClipboard(text)}
+	}
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestModel_CopyCommandAndConnectionString(t *testing.T) {
+	original := writeClipboard
+	t.Cleanup(func() { writeClipboard = original })
+	var copied string
+	writeClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+
+	m := newTestModel(t, "Host web\n    HostName web.example.com\n    User deploy\n    Port 2222\n")
+
+	_, _, cmd := m.handleListKeyPress(keyRunes("y"))
+	m.Update(cmd())
+	if copied != "ssh -p 2222 deploy@web.example.com" {
+		t.Errorf("y should copy the ssh command, got %q", copied)
+	}
+	if m.statusMsg != "Copied ssh command: ssh -p 2222 deploy@web.example.com" {
+		t.Errorf("Unexpected status: %q", m.statusMsg)
+	}
+
+	_, _, cmd = m.handleListKeyPress(keyRunes("Y"))
+	m.Update(cmd())
+	if copied != "deploy@web.example.com" {
+		t.Errorf("Y should copy the connection string, got %q", copied)
+	}
+	if m.statusMsg != "Copied connection string: deploy@web.example.com" {
+		t.Errorf("Unexpected status: %q", m.statusMsg)
+	}
+
+	writeClipboard = func(string) error { return errors.New("no clipboard utility") }
+	_, _, cmd = m.handleListKeyPress(keyRunes("Y"))
+	m.Update(cmd())
+	if m.statusMsg != "Couldn't copy the connection string: no clipboard utility" {
+		t.Errorf("Unexpected status: %q", m.statusMsg)
+	}
+}
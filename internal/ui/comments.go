@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CommentsEditorModel edits the standalone comment block at the top of the config file
+type CommentsEditorModel struct {
+	textarea textarea.Model
+	width    int
+	height   int
+	errorMsg string
+}
+
+// NewCommentsEditorModel creates a new comments editor
+func NewCommentsEditorModel() *CommentsEditorModel {
+	ta := textarea.New()
+	ta.Placeholder = "# Notes written at the top of your SSH config"
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+
+	return &CommentsEditorModel{
+		textarea: ta,
+	}
+}
+
+// SetComments loads the comment lines into the editor and focuses it
+func (m *CommentsEditorModel) SetComments(comments []string) tea.Cmd {
+	m.errorMsg = ""
+	m.textarea.SetValue(strings.Join(comments, "\n"))
+	return m.textarea.Focus()
+}
+
+// GetComments returns the edited comment lines
+func (m *CommentsEditorModel) GetComments() []string {
+	value := m.textarea.Value()
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return strings.Split(value, "\n")
+}
+
+// SetError sets an error message
+func (m *CommentsEditorModel) SetError(msg string) {
+	m.errorMsg = msg
+}
+
+// Blur removes focus from the editor
+func (m *CommentsEditorModel) Blur() {
+	m.textarea.Blur()
+}
+
+// SetSize sets the size of the comments editor
+func (m *CommentsEditorModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	// Leave room for panel borders/padding, title and help text
+	m.textarea.SetWidth(max(10, width-8))
+	m.textarea.SetHeight(max(3, height-10))
+}
+
+// Update handles updates to the comments editor
+func (m *CommentsEditorModel) Update(msg tea.Msg) (*CommentsEditorModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.textarea, cmd = m.textarea.Update(msg)
+	return m, cmd
+}
+
+// View renders the comments editor
+func (m *CommentsEditorModel) View() string {
+	var lines []string
+	lines = append(lines, titleStyle.Render("Config Header Comments"))
+	lines = append(lines, m.textarea.View())
+
+	if m.errorMsg != "" {
+		lines = append(lines, "")
+		lines = append(lines, errorStyle.Render("Error: "+m.errorMsg))
+	}
+
+	lines = append(lines, helpStyle.Render("Ctrl+S: save | Esc: cancel | lines without # are turned into comments"))
+
+	return detailPanelStyle.Width(m.width).Height(m.height).Render(strings.Join(lines, "\n"))
+}
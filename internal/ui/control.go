@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// controlCheckTimeout bounds how long `ssh -O check` may take
+const controlCheckTimeout = 3 * time.Second
+
+// controlStatus describes the state of a host's ControlMaster connection
+type controlStatus int
+
+const (
+	controlUnknown       controlStatus = iota // Not checked yet
+	controlNotConfigured                      // No ControlPath for the host
+	controlInactive                           // ControlPath set, no master running
+	controlActive                             // A master connection is running
+)
+
+// controlStatusMsg carries the result of checking a host's master connection
+type controlStatusMsg struct {
+	host   string
+	status controlStatus
+}
+
+// checkControlMaster runs `ssh -O check` for host in the background
+func checkControlMaster(host string, sshArgs []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), controlCheckTimeout)
+		defer cancel()
+
+		out, err := exec.CommandContext(ctx, "ssh", sshArgs...).CombinedOutput()
+		if err == nil {
+			return controlStatusMsg{host: host, status: controlActive}
+		}
+		if strings.Contains(string(out), "No ControlPath specified") {
+			return controlStatusMsg{host: host, status: controlNotConfigured}
+		}
+		return controlStatusMsg{host: host, status: controlInactive}
+	}
+}
+
+// exitControlMaster runs `ssh -O exit` for host and re-checks its status
+func exitControlMaster(host string, exitArgs, checkArgs []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), controlCheckTimeout)
+		defer cancel()
+
+		_ = exec.CommandContext(ctx, "ssh", exitArgs...).Run()
+		return checkControlMaster(host, checkArgs)()
+	}
+}
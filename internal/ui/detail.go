@@ -1,16 +1,24 @@
 package ui
 
 import (
-	"fmt"
 	"strings"
+	"time"
 
 	"github.com/nicklasos/gosshit/internal/sshconfig"
+	"github.com/nicklasos/gosshit/internal/storage"
 )
 
 // DetailModel represents the right panel detail view
 type DetailModel struct {
 	entry      *sshconfig.HostEntry
 	visitCount int
+	lastVisit  time.Time
+	connected  time.Duration
+	control    controlStatus
+	reach      reachability
+	keyArt     fingerprint
+	note       string
+	sourceFile string // Included file defining the entry, "" for the main config
 	width      int
 	height     int
 }
@@ -30,12 +38,55 @@ func (m *DetailModel) SetVisitCount(count int) {
 	m.visitCount = count
 }
 
+// SetControlStatus sets the ControlMaster status for the current entry
+func (m *DetailModel) SetControlStatus(status controlStatus) {
+	m.control = status
+}
+
+// SetLastVisit sets the time of the current entry's most recent visit
+func (m *DetailModel) SetLastVisit(t time.Time) {
+	m.lastVisit = t
+}
+
+// SetTotalDuration sets the total time spent connected to the current entry
+func (m *DetailModel) SetTotalDuration(d time.Duration) {
+	m.connected = d
+}
+
+// SetFingerprint sets the fingerprint of the current entry's IdentityFile
+func (m *DetailModel) SetFingerprint(fp fingerprint) {
+	m.keyArt = fp
+}
+
+// SetNote sets the private note of the current entry
+func (m *DetailModel) SetNote(note string) {
+	m.note = note
+}
+
+// SetSourceFile sets the included file the current entry is defined in, ""
+// when it's in the main config
+func (m *DetailModel) SetSourceFile(file string) {
+	m.sourceFile = file
+}
+
+// SetReachability sets the reachability of the current entry
+func (m *DetailModel) SetReachability(status reachability) {
+	m.reach = status
+}
+
 // SetSize sets the size of the detail view
 func (m *DetailModel) SetSize(width, height int) {
 	m.width = width
 	m.height = height
 }
 
+// wrapValue renders a value wrapped to the panel's content width, breaking
+// long words such as IPv6 addresses, so continuation lines stay under the label
+func (m *DetailModel) wrapValue(value string) string {
+	// Panel padding is 2 per side
+	return valueStyle.Width(max(10, m.width-4)).Render(value)
+}
+
 // View renders the detail view
 func (m *DetailModel) View() string {
 	if m.entry == nil {
@@ -52,17 +103,36 @@ func (m *DetailModel) View() string {
 	if m.entry.Description != "" {
 		lines = append(lines, "")
 		lines = append(lines, labelStyle.Render("Description:"))
-		lines = append(lines, valueStyle.Render(m.entry.Description))
+		lines = append(lines, m.wrapValue(m.entry.Description))
+	}
+
+	// Private note kept by gosshit
+	if m.note != "" {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Notes:"))
+		lines = append(lines, m.wrapValue(m.note))
 	}
 
 	lines = append(lines, "")
 	lines = append(lines, labelStyle.Render("Host:"))
-	lines = append(lines, valueStyle.Render(m.entry.Host))
+	lines = append(lines, valueStyle.Render(m.entry.PrimaryAlias()))
+
+	if aliases := m.entry.Aliases(); len(aliases) > 1 {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Also matches:"))
+		lines = append(lines, valueStyle.Render(strings.Join(aliases[1:], ", ")))
+	}
+
+	if m.sourceFile != "" {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Defined in:"))
+		lines = append(lines, m.wrapValue(m.sourceFile))
+	}
 
 	lines = append(lines, "")
 	lines = append(lines, labelStyle.Render("HostName:"))
 	if m.entry.HostName != "" {
-		lines = append(lines, valueStyle.Render(m.entry.HostName))
+		lines = append(lines, m.wrapValue(m.entry.HostName))
 	} else {
 		lines = append(lines, valueStyle.Foreground(subtleColor).Render("(not set)"))
 	}
@@ -77,26 +147,84 @@ func (m *DetailModel) View() string {
 
 	lines = append(lines, "")
 	lines = append(lines, labelStyle.Render("Port:"))
-	if m.entry.Port != "" {
+	if !m.entry.IsDefaultPort() {
 		lines = append(lines, valueStyle.Render(m.entry.Port))
 	} else {
-		lines = append(lines, valueStyle.Foreground(subtleColor).Render("(default: 22)"))
+		lines = append(lines, valueStyle.Foreground(subtleColor).Render("(default: "+sshconfig.DefaultPort+")"))
 	}
 
 	lines = append(lines, "")
 	lines = append(lines, labelStyle.Render("IdentityFile:"))
 	if m.entry.IdentityFile != "" {
 		lines = append(lines, valueStyle.Render(m.entry.IdentityFile))
+		if warning := m.entry.IdentityFileWarning(); warning != "" {
+			lines = append(lines, warningStyle.Render("⚠ "+warning))
+		}
 	} else {
 		lines = append(lines, valueStyle.Foreground(subtleColor).Render("(not set)"))
 	}
 
+	if m.entry.ProxyJump != "" {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("ProxyJump:"))
+		lines = append(lines, valueStyle.Render(m.entry.ProxyJump))
+	}
+
+	if m.entry.ProxyCommand != "" {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("ProxyCommand:"))
+		lines = append(lines, m.wrapValue(m.entry.ProxyCommand))
+	}
+
+	if m.entry.ForwardAgent != "" {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("ForwardAgent:"))
+		lines = append(lines, valueStyle.Render(m.entry.ForwardAgent))
+	}
+
+	agentDirectives := []struct{ name, value string }{
+		{"AddKeysToAgent:", m.entry.AddKeysToAgent},
+		{"UseKeychain:", m.entry.UseKeychain},
+		{"IdentitiesOnly:", m.entry.IdentitiesOnly},
+	}
+	for _, directive := range agentDirectives {
+		if directive.value != "" {
+			lines = append(lines, "")
+			lines = append(lines, labelStyle.Render(directive.name))
+			lines = append(lines, valueStyle.Render(directive.value))
+		}
+	}
+
+	if len(m.entry.LocalForward) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("LocalForward:"))
+		for _, forward := range m.entry.LocalForward {
+			lines = append(lines, valueStyle.Render(forward))
+		}
+	}
+
+	if len(m.entry.RemoteForward) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("RemoteForward:"))
+		for _, forward := range m.entry.RemoteForward {
+			lines = append(lines, valueStyle.Render(forward))
+		}
+	}
+
+	if len(m.entry.SetEnv) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("SetEnv:"))
+		for _, env := range m.entry.SetEnv {
+			lines = append(lines, valueStyle.Render(env))
+		}
+	}
+
 	// Tags
 	if len(m.entry.Tags) > 0 {
 		lines = append(lines, "")
 		lines = append(lines, labelStyle.Render("Tags:"))
 		var tagBadges []string
-		for _, tag := range m.entry.Tags {
+		for _, tag := range sshconfig.UniqueTags(m.entry.Tags) {
 			tagBadges = append(tagBadges, formatTagBadge(tag))
 		}
 		lines = append(lines, strings.TrimSpace(strings.Join(tagBadges, " ")))
@@ -106,7 +234,66 @@ func (m *DetailModel) View() string {
 	if m.visitCount > 0 {
 		lines = append(lines, "")
 		lines = append(lines, labelStyle.Render("Visits:"))
-		lines = append(lines, valueStyle.Render(fmt.Sprintf("%d", m.visitCount)))
+		lines = append(lines, valueStyle.Render(storage.FormatCount(m.visitCount)))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, labelStyle.Render("Last connected:"))
+	if m.lastVisit.IsZero() {
+		lines = append(lines, valueStyle.Foreground(subtleColor).Render("never"))
+	} else {
+		lines = append(lines, valueStyle.Render(storage.FormatRelativeTime(m.lastVisit, time.Now())))
+		lines = append(lines, valueStyle.Foreground(subtleColor).Render(m.lastVisit.Format("2006-01-02 15:04")))
+	}
+
+	if m.connected > 0 {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Time connected:"))
+		lines = append(lines, valueStyle.Render(storage.FormatDuration(m.connected)))
+	}
+
+	// Reachability of HostName:Port
+	var reachLine string
+	switch m.reach {
+	case reachabilityChecking:
+		reachLine = valueStyle.Foreground(subtleColor).Render("checking...")
+	case reachabilityReachable:
+		reachLine = successStyle.Render("● reachable")
+	case reachabilityUnreachable:
+		reachLine = errorStyle.Render("● unreachable")
+	case reachabilitySkipped:
+		reachLine = valueStyle.Foreground(subtleColor).Render("behind a proxy (not checked)")
+	}
+	if reachLine != "" {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Status:"))
+		lines = append(lines, reachLine)
+	}
+
+	// Multiplexed (ControlMaster) connection, only for hosts with a ControlPath
+	switch m.control {
+	case controlActive:
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Master connection:"))
+		lines = append(lines, valueStyle.Render("active (O: close)"))
+	case controlInactive:
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Master connection:"))
+		lines = append(lines, valueStyle.Foreground(subtleColor).Render("not running"))
+	}
+
+	// Fingerprint and randomart of the IdentityFile's public key
+	if m.entry.IdentityFile != "" {
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Key fingerprint:"))
+		switch {
+		case !m.keyArt.done:
+			lines = append(lines, valueStyle.Foreground(subtleColor).Render("computing..."))
+		case m.keyArt.art == "":
+			lines = append(lines, valueStyle.Foreground(subtleColor).Render("(fingerprint unavailable)"))
+		default:
+			lines = append(lines, valueStyle.Render(m.keyArt.art))
+		}
 	}
 
 	content := strings.Join(lines, "\n")
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+func TestDetailModel_WrapsLongValues(t *testing.T) {
+	description := strings.TrimSpace(strings.Repeat("database primary in frankfurt ", 8))[:200]
+	hostname := "2001:0db8:85a3:0000:0000:8a2e:0370:7334"
+
+	m := NewDetailModel()
+	m.SetSize(30, 60)
+	m.SetEntry(&sshconfig.HostEntry{Host: "db", HostName: hostname, Description: description})
+
+	// Values wrap inside the panel padding instead of relying on the panel
+	for _, value := range []string{description, hostname} {
+		for _, line := range strings.Split(m.wrapValue(value), "\n") {
+			if w := lipgloss.Width(line); w > 30-4 {
+				t.Errorf("Wrapped value line wider than the content area (%d): %q", w, line)
+			}
+		}
+	}
+
+	view := m.View()
+	for _, line := range strings.Split(view, "\n") {
+		// Width plus the left and right border
+		if w := lipgloss.Width(line); w > 30+2 {
+			t.Errorf("Line wider than the panel (%d): %q", w, line)
+		}
+	}
+
+	// Every word is still there, in order
+	content := strings.Join(strings.Fields(stripBorders(view)), " ")
+	if !strings.Contains(content, strings.Join(strings.Fields(description), " ")) {
+		t.Errorf("Description mangled by wrapping:\n%s", view)
+	}
+	if !strings.Contains(strings.ReplaceAll(content, " ", ""), hostname) {
+		t.Errorf("HostName mangled by wrapping:\n%s", view)
+	}
+}
+
+// stripBorders removes the panel's border characters from a rendered view
+func stripBorders(view string) string {
+	return strings.NewReplacer("│", "", "╭", "", "╮", "", "╰", "", "╯", "", "─", "").Replace(view)
+}
+
+func TestDetailModel_LastConnected(t *testing.T) {
+	m := NewDetailModel()
+	m.SetSize(40, 60)
+	m.SetEntry(&sshconfig.HostEntry{Host: "web", HostName: "web.example.com"})
+
+	if view := m.View(); !strings.Contains(view, "Last connected:") || !strings.Contains(view, "never") {
+		t.Errorf("A host without visits should show never:\n%s", view)
+	}
+
+	lastVisit := time.Now().Add(-3*time.Hour - time.Minute)
+	m.SetLastVisit(lastVisit)
+	view := m.View()
+	if !strings.Contains(view, "3 hours ago") {
+		t.Errorf("Expected the relative time:\n%s", view)
+	}
+	if !strings.Contains(view, lastVisit.Format("2006-01-02 15:04")) {
+		t.Errorf("Expected the timestamp below the relative time:\n%s", view)
+	}
+}
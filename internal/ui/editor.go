@@ -2,8 +2,11 @@ package ui
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,12 +17,17 @@ import (
 // EditorModel represents the form-based editor for host entries
 type EditorModel struct {
 	fields       []textinput.Model
-	focused      int
+	setEnv       *RowsModel     // SetEnv values, one row each
+	forwards     *ForwardsModel // LocalForward/RemoteForward rules, one row each
+	extra        textarea.Model // Extra directives, one per line
+	focused      int            // Index into fields, or fieldSetEnv/fieldForwards/fieldExtra
 	entry        *sshconfig.HostEntry
+	template     *sshconfig.HostEntry // Entry being duplicated, if any
 	isNew        bool
 	width        int
 	height       int
 	errorMsg     string
+	sourceNote   string // Shown under the title, e.g. when the host lives in an included file
 	keySelector  *KeySelectorModel
 	selectingKey bool
 	viewport     viewport.Model
@@ -32,16 +40,51 @@ const (
 	fieldUser
 	fieldPort
 	fieldIdentityFile
+	fieldProxyJump
+	fieldProxyCommand
+	fieldForwardAgent
+	fieldAddKeysToAgent
+	fieldUseKeychain
+	fieldIdentitiesOnly
 	fieldDescription
 	fieldTags
+	fieldLogs
 	fieldCount
 )
 
+// Focus indices of the SetEnv and forwards lists and the extra directives
+// textarea, after the inputs
+const (
+	fieldSetEnv   = fieldCount
+	fieldForwards = fieldCount + 1
+	fieldExtra    = fieldCount + 2
+	focusCount    = fieldCount + 3
+)
+
+// Environment variables overriding the initial values of new hosts
+const (
+	defaultUserEnv         = "GOSSHIT_DEFAULT_USER"
+	defaultPortEnv         = "GOSSHIT_DEFAULT_PORT"
+	defaultIdentityFileEnv = "GOSSHIT_DEFAULT_IDENTITY_FILE"
+)
+
+// newEntryDefault returns the initial value of a new host's field: the
+// environment variable if it's set (even to empty, to leave the field blank),
+// otherwise fallback
+func newEntryDefault(env, fallback string) string {
+	if value, ok := os.LookupEnv(env); ok {
+		return strings.TrimSpace(value)
+	}
+	return fallback
+}
+
 // NewEditorModel creates a new editor model
 func NewEditorModel() *EditorModel {
 	m := &EditorModel{
 		fields:      make([]textinput.Model, fieldCount),
 		keySelector: NewKeySelectorModel(),
+		setEnv:      NewRowsModel("FOO=bar (optional)"),
+		forwards:    NewForwardsModel(),
 		viewport:    viewport.New(0, 0),
 	}
 
@@ -62,12 +105,40 @@ func NewEditorModel() *EditorModel {
 	m.fields[fieldIdentityFile] = textinput.New()
 	m.fields[fieldIdentityFile].Placeholder = "~/.ssh/id_rsa (optional - enter path manually)"
 
+	m.fields[fieldProxyJump] = textinput.New()
+	m.fields[fieldProxyJump].Placeholder = "bastion or user@jump.example.com:22 (optional)"
+
+	m.fields[fieldProxyCommand] = textinput.New()
+	m.fields[fieldProxyCommand].Placeholder = "ssh -W %h:%p bastion (optional, instead of ProxyJump)"
+	m.fields[fieldProxyCommand].CharLimit = 4096
+
+	m.fields[fieldForwardAgent] = textinput.New()
+	m.fields[fieldForwardAgent].Placeholder = "yes or no (optional)"
+
+	m.fields[fieldAddKeysToAgent] = textinput.New()
+	m.fields[fieldAddKeysToAgent].Placeholder = "yes, no, ask or confirm (optional, usually in Host *)"
+
+	m.fields[fieldUseKeychain] = textinput.New()
+	m.fields[fieldUseKeychain].Placeholder = "yes or no (optional, macOS only)"
+
+	m.fields[fieldIdentitiesOnly] = textinput.New()
+	m.fields[fieldIdentitiesOnly].Placeholder = "yes or no (optional)"
+
 	m.fields[fieldDescription] = textinput.New()
 	m.fields[fieldDescription].Placeholder = "Description (optional)"
 
 	m.fields[fieldTags] = textinput.New()
 	m.fields[fieldTags].Placeholder = "prod,dev,stage (comma-separated, optional)"
 
+	m.fields[fieldLogs] = textinput.New()
+	m.fields[fieldLogs].Placeholder = sshconfig.DefaultLogsCommand + " (remote logs command, optional)"
+
+	m.extra = textarea.New()
+	m.extra.Placeholder = "Compression yes\nServerAliveInterval 30"
+	m.extra.ShowLineNumbers = false
+	m.extra.CharLimit = 0
+	m.extra.SetHeight(4)
+
 	return m
 }
 
@@ -79,8 +150,10 @@ func (m *EditorModel) Init() tea.Cmd {
 // SetEntry sets the entry to edit (nil for new entry)
 func (m *EditorModel) SetEntry(entry *sshconfig.HostEntry) {
 	m.entry = entry
+	m.template = nil
 	m.isNew = entry == nil
 	m.errorMsg = ""
+	m.sourceNote = ""
 
 	if entry != nil {
 		m.fields[fieldHost].SetValue(entry.Host)
@@ -88,6 +161,14 @@ func (m *EditorModel) SetEntry(entry *sshconfig.HostEntry) {
 		m.fields[fieldUser].SetValue(entry.User)
 		m.fields[fieldPort].SetValue(entry.Port)
 		m.fields[fieldIdentityFile].SetValue(entry.IdentityFile)
+		m.fields[fieldProxyJump].SetValue(entry.ProxyJump)
+		m.fields[fieldProxyCommand].SetValue(entry.ProxyCommand)
+		m.fields[fieldForwardAgent].SetValue(entry.ForwardAgent)
+		m.fields[fieldAddKeysToAgent].SetValue(entry.AddKeysToAgent)
+		m.fields[fieldUseKeychain].SetValue(entry.UseKeychain)
+		m.fields[fieldIdentitiesOnly].SetValue(entry.IdentitiesOnly)
+		m.setEnv.SetRows(entry.SetEnv)
+		m.forwards.SetForwards(entry.LocalForward, entry.RemoteForward)
 		m.fields[fieldDescription].SetValue(entry.Description)
 		// Convert tags slice to comma-separated string
 		if len(entry.Tags) > 0 {
@@ -95,15 +176,27 @@ func (m *EditorModel) SetEntry(entry *sshconfig.HostEntry) {
 		} else {
 			m.fields[fieldTags].SetValue("")
 		}
+		m.fields[fieldLogs].SetValue(entry.LogsCommand)
+		m.extra.SetValue(strings.Join(entry.ExtraDirectives, "\n"))
 	} else {
 		// Default values for new entries
 		m.fields[fieldHost].SetValue("")
 		m.fields[fieldHostName].SetValue("")
-		m.fields[fieldUser].SetValue("root")
-		m.fields[fieldPort].SetValue("22")
-		m.fields[fieldIdentityFile].SetValue("")
+		m.fields[fieldUser].SetValue(newEntryDefault(defaultUserEnv, "root"))
+		m.fields[fieldPort].SetValue(newEntryDefault(defaultPortEnv, "22"))
+		m.fields[fieldIdentityFile].SetValue(newEntryDefault(defaultIdentityFileEnv, ""))
+		m.fields[fieldProxyJump].SetValue("")
+		m.fields[fieldProxyCommand].SetValue("")
+		m.fields[fieldForwardAgent].SetValue("")
+		m.fields[fieldAddKeysToAgent].SetValue("")
+		m.fields[fieldUseKeychain].SetValue("")
+		m.fields[fieldIdentitiesOnly].SetValue("")
+		m.setEnv.SetRows()
+		m.forwards.SetForwards(nil, nil)
 		m.fields[fieldDescription].SetValue("")
 		m.fields[fieldTags].SetValue("")
+		m.fields[fieldLogs].SetValue("")
+		m.extra.SetValue("")
 	}
 
 	// Focus first field
@@ -111,6 +204,33 @@ func (m *EditorModel) SetEntry(entry *sshconfig.HostEntry) {
 	m.updateFocus()
 }
 
+// SetSourceNote sets a note shown under the title, such as where the host
+// being edited is defined
+func (m *EditorModel) SetSourceNote(note string) {
+	m.sourceNote = note
+}
+
+// SetClone fills the form from entry for adding a new host based on it.
+// The alias gets a "-copy" suffix; all other fields (and unknown directives)
+// carry over.
+func (m *EditorModel) SetClone(entry *sshconfig.HostEntry) {
+	m.SetEntry(entry)
+	m.entry = nil
+	m.template = entry
+	m.isNew = true
+
+	m.fields[fieldHost].SetValue(entry.Host + "-copy")
+	m.fields[fieldHost].CursorEnd()
+}
+
+// SetGlobal fills the form for adding a Host * block, without the User and
+// Port defaults of new hosts
+func (m *EditorModel) SetGlobal() {
+	m.SetEntry(&sshconfig.HostEntry{Host: "*"})
+	m.entry = nil
+	m.isNew = true
+}
+
 // SetSize sets the size of the editor
 func (m *EditorModel) SetSize(width, height int) {
 	m.width = width
@@ -120,6 +240,9 @@ func (m *EditorModel) SetSize(width, height int) {
 	for i := range m.fields {
 		m.fields[i].Width = fieldWidth
 	}
+	m.setEnv.SetWidth(fieldWidth)
+	m.forwards.SetWidth(fieldWidth - 8) // Room for the Local/Remote label
+	m.extra.SetWidth(fieldWidth)
 	m.keySelector.SetSize(width, height)
 	// Set viewport size (accounting for borders - 2 lines top/bottom)
 	m.viewport.Width = width - 4
@@ -155,6 +278,17 @@ func (m *EditorModel) Update(msg tea.Msg) (*EditorModel, tea.Cmd) {
 		m.selectingKey = false
 		return m, nil
 
+	case keyGeneratedMsg:
+		// ssh-keygen finished, select the new private key
+		m.selectingKey = false
+		if msg.err != nil {
+			m.SetError(msg.err.Error())
+			return m, nil
+		}
+		m.errorMsg = ""
+		m.fields[fieldIdentityFile].SetValue(msg.key)
+		return m, nil
+
 	case tea.KeyMsg:
 		// If key selector is open, handle it first
 		if m.selectingKey {
@@ -170,17 +304,24 @@ func (m *EditorModel) Update(msg tea.Msg) (*EditorModel, tea.Cmd) {
 		}
 
 		switch msg.String() {
+		case "ctrl+o":
+			if m.focused == fieldIdentityFile {
+				m.selectingKey = true
+				return m, m.keySelector.Open()
+			}
 		case "tab":
-			m.focused = (m.focused + 1) % fieldCount
+			m.focused = (m.focused + 1) % focusCount
 			m.updateFocus()
 			return m, nil
 		case "shift+tab":
-			m.focused = (m.focused - 1 + fieldCount) % fieldCount
+			m.focused = (m.focused - 1 + focusCount) % focusCount
 			m.updateFocus()
 			return m, nil
 		case "enter":
-			// Will be handled by parent model
-			return m, nil
+			// Inserts a line in the extra directives, otherwise handled by parent model
+			if m.focused != fieldExtra {
+				return m, nil
+			}
 		case "esc":
 			// Will be handled by parent model
 			return m, nil
@@ -189,6 +330,21 @@ func (m *EditorModel) Update(msg tea.Msg) (*EditorModel, tea.Cmd) {
 
 	// Update focused field first (before viewport, so content is up to date)
 	var fieldCmd tea.Cmd
+	if m.focused == fieldSetEnv {
+		// Arrow keys move between the rows rather than scrolling
+		return m, m.setEnv.Update(msg)
+	}
+	if m.focused == fieldForwards {
+		return m, m.forwards.Update(msg)
+	}
+	if m.focused == fieldExtra {
+		m.extra, fieldCmd = m.extra.Update(msg)
+		if fieldCmd != nil {
+			cmds = append(cmds, fieldCmd)
+		}
+		// Arrow keys move within the textarea rather than scrolling
+		return m, tea.Batch(cmds...)
+	}
 	m.fields[m.focused], fieldCmd = m.fields[m.focused].Update(msg)
 	if fieldCmd != nil {
 		cmds = append(cmds, fieldCmd)
@@ -215,6 +371,33 @@ func (m *EditorModel) updateFocus() {
 			m.fields[i].Blur()
 		}
 	}
+	if m.focused == fieldSetEnv {
+		m.setEnv.Focus()
+	} else {
+		m.setEnv.Blur()
+	}
+	if m.focused == fieldForwards {
+		m.forwards.Focus()
+	} else {
+		m.forwards.Blur()
+	}
+	if m.focused == fieldExtra {
+		m.extra.Focus()
+	} else {
+		m.extra.Blur()
+	}
+}
+
+// SelectingKey reports whether the key selector is open (it handles Enter
+// and Esc itself)
+func (m *EditorModel) SelectingKey() bool {
+	return m.selectingKey
+}
+
+// ExtraFocused reports whether the extra directives field has focus (Enter
+// inserts a line there instead of saving)
+func (m *EditorModel) ExtraFocused() bool {
+	return m.focused == fieldExtra
 }
 
 // Validate validates the form fields
@@ -224,41 +407,171 @@ func (m *EditorModel) Validate() error {
 	if host == "" {
 		return fmt.Errorf("Host alias is required")
 	}
-	// Host * entries don't need HostName
-	if host != "*" {
+	// Pattern entries (Host *, Host *.internal, ...) don't need HostName
+	if !(&sshconfig.HostEntry{Host: host}).IsPattern() {
 		hostname := m.fields[fieldHostName].Value()
 		if hostname == "" {
 			return fmt.Errorf("HostName is required")
 		}
 	}
 
-	return nil
-}
+	if port := strings.TrimSpace(m.fields[fieldPort].Value()); port != "" {
+		if err := validatePort(port); err != nil {
+			return err
+		}
+	}
 
-// GetEntry returns the entry from the form fields
-func (m *EditorModel) GetEntry() *sshconfig.HostEntry {
-	// Parse tags from comma-separated string
-	var tags []string
-	tagsStr := strings.TrimSpace(m.fields[fieldTags].Value())
-	if tagsStr != "" {
-		parts := strings.Split(tagsStr, ",")
-		for _, tag := range parts {
-			tag = strings.TrimSpace(tag)
-			if tag != "" {
-				tags = append(tags, tag)
+	// ssh uses whichever of the two comes first and ignores the other
+	if strings.TrimSpace(m.fields[fieldProxyJump].Value()) != "" && strings.TrimSpace(m.fields[fieldProxyCommand].Value()) != "" {
+		return fmt.Errorf("Set either ProxyJump or ProxyCommand, not both")
+	}
+
+	yesNoFields := []struct {
+		field int
+		name  string
+	}{
+		{fieldForwardAgent, "ForwardAgent"},
+		{fieldUseKeychain, "UseKeychain"},
+		{fieldIdentitiesOnly, "IdentitiesOnly"},
+	}
+	for _, f := range yesNoFields {
+		switch strings.ToLower(strings.TrimSpace(m.fields[f.field].Value())) {
+		case "", "yes", "no":
+		default:
+			return fmt.Errorf("%s must be yes or no", f.name)
+		}
+	}
+
+	// AddKeysToAgent also takes a key lifetime, alone or after "confirm"
+	if value := strings.ToLower(strings.TrimSpace(m.fields[fieldAddKeysToAgent].Value())); value != "" {
+		switch strings.Fields(value)[0] {
+		case "yes", "no", "ask", "confirm":
+		default:
+			if !isTimeInterval(value) {
+				return fmt.Errorf("AddKeysToAgent must be yes, no, ask, confirm or a key lifetime (e.g. 1h)")
 			}
 		}
 	}
 
-	return &sshconfig.HostEntry{
+	for _, env := range m.setEnv.Values()[0] {
+		if name, _, ok := strings.Cut(strings.Fields(env)[0], "="); !ok || name == "" {
+			return fmt.Errorf("SetEnv %q must be \"NAME=value\"", env)
+		}
+	}
+
+	if err := m.forwards.Validate(); err != nil {
+		return err
+	}
+
+	for _, line := range m.extraDirectives() {
+		parts := strings.Fields(line)
+		directive := strings.ToLower(parts[0])
+		switch {
+		case strings.HasPrefix(line, "#"):
+			return fmt.Errorf("Extra directives can't contain comments (%q)", line)
+		case directive == "host" || directive == "match":
+			return fmt.Errorf("Extra directives can't start a new block (%q)", line)
+		case sshconfig.IsKnownDirective(directive):
+			return fmt.Errorf("Use the %s field instead of an extra directive", parts[0])
+		case len(parts) < 2:
+			return fmt.Errorf("Extra directive %q needs a value", line)
+		}
+	}
+
+	return nil
+}
+
+// Warning returns a non-blocking warning about the current form values
+func (m *EditorModel) Warning() string {
+	entry := &sshconfig.HostEntry{IdentityFile: m.fields[fieldIdentityFile].Value()}
+	return entry.IdentityFileWarning()
+}
+
+// GetEntry returns the entry from the form fields
+func (m *EditorModel) GetEntry() *sshconfig.HostEntry {
+	entry := &sshconfig.HostEntry{
 		Host:         m.fields[fieldHost].Value(),
 		HostName:     m.fields[fieldHostName].Value(),
 		User:         m.fields[fieldUser].Value(),
-		Port:         m.fields[fieldPort].Value(),
+		Port:         strings.TrimSpace(m.fields[fieldPort].Value()),
 		IdentityFile: m.fields[fieldIdentityFile].Value(),
-		Description:  m.fields[fieldDescription].Value(),
-		Tags:         tags,
+		ProxyJump:    strings.TrimSpace(m.fields[fieldProxyJump].Value()),
+		ProxyCommand: strings.TrimSpace(m.fields[fieldProxyCommand].Value()),
+		ForwardAgent: strings.ToLower(strings.TrimSpace(m.fields[fieldForwardAgent].Value())),
+
+		AddKeysToAgent: strings.ToLower(strings.TrimSpace(m.fields[fieldAddKeysToAgent].Value())),
+		UseKeychain:    strings.ToLower(strings.TrimSpace(m.fields[fieldUseKeychain].Value())),
+		IdentitiesOnly: strings.ToLower(strings.TrimSpace(m.fields[fieldIdentitiesOnly].Value())),
+		SetEnv:         m.setEnv.Values()[0],
+
+		Description: m.fields[fieldDescription].Value(),
+		Tags:        sshconfig.UniqueTags(splitList(m.fields[fieldTags].Value())),
+		LogsCommand: strings.TrimSpace(m.fields[fieldLogs].Value()),
+
+		ExtraDirectives: m.extraDirectives(),
+	}
+	entry.LocalForward, entry.RemoteForward = m.forwards.Forwards()
+
+	// Keep the original lines when editing so directives the form doesn't
+	// know about (and comments inside the block) survive the rewrite
+	if m.entry != nil {
+		entry.RawLines = m.entry.RawLines
+		entry.SourceFile = m.entry.SourceFile
+	} else if m.template != nil {
+		entry.RawLines = m.template.RawLines
+	}
+
+	return entry
+}
+
+// extraDirectives returns the non-empty, trimmed lines of the extra directives
+// field (never nil, so removed lines are dropped from the config)
+func (m *EditorModel) extraDirectives() []string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(m.extra.Value(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// validatePort checks that port is a number between 1 and 65535
+func validatePort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("Port must be a number between 1 and 65535 (got %q)", port)
+	}
+	return nil
+}
+
+// isTimeInterval reports whether value is an sshd_config(5) time format
+// such as 30, 10m or 1h30m
+func isTimeInterval(value string) bool {
+	digits := false
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = true
+		case digits && strings.ContainsRune("smhdwSMHDW", r):
+			digits = false
+		default:
+			return false
+		}
 	}
+	return value != ""
+}
+
+// splitList splits a comma-separated field value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 // SetError sets an error message
@@ -272,16 +585,25 @@ func (m *EditorModel) updateViewportContent() {
 
 	// Title/Header
 	title := "Edit Host"
-	if m.isNew {
+	if m.template != nil {
+		title = "Duplicate " + m.template.Host
+	} else if m.isNew {
 		title = "Add New Host"
 	}
 	lines = append(lines, titleStyle.Render(title))
+	if m.sourceNote != "" {
+		lines = append(lines, warningStyle.Render(m.sourceNote))
+	}
 	lines = append(lines, "")
 
 	// Field labels
-	labels := []string{"Host:", "HostName:", "User:", "Port:", "IdentityFile:", "Description:", "Tags:"}
+	labels := []string{"Host:", "HostName:", "User:", "Port:", "IdentityFile:", "ProxyJump:", "ProxyCommand:", "ForwardAgent:", "AddKeysToAgent:", "UseKeychain:", "IdentitiesOnly:", "Description:", "Tags:", "Logs:"}
+	focusedTop, focusedBottom := -1, -1
 	for i, label := range labels {
 		lines = append(lines, "")
+		if i == m.focused {
+			focusedTop = renderedHeight(lines)
+		}
 		lines = append(lines, labelStyle.Render(label))
 
 		var fieldView string
@@ -291,6 +613,51 @@ func (m *EditorModel) updateViewportContent() {
 			fieldView = inputStyle.Render(m.fields[i].View())
 		}
 		lines = append(lines, fieldView)
+		if i == m.focused {
+			focusedBottom = renderedHeight(lines) - 1
+		}
+	}
+
+	lines = append(lines, "")
+	if m.focused == fieldSetEnv {
+		focusedTop = renderedHeight(lines)
+	}
+	lines = append(lines, labelStyle.Render("SetEnv (one NAME=value per row):"))
+	if m.focused == fieldSetEnv {
+		lines = append(lines, inputFocusedStyle.Render(m.setEnv.View()))
+		focusedBottom = renderedHeight(lines) - 1
+	} else {
+		lines = append(lines, inputStyle.Render(m.setEnv.View()))
+	}
+
+	lines = append(lines, "")
+	if m.focused == fieldForwards {
+		focusedTop = renderedHeight(lines)
+	}
+	lines = append(lines, labelStyle.Render("Forwards (LocalForward/RemoteForward):"))
+	if m.focused == fieldForwards {
+		lines = append(lines, inputFocusedStyle.Render(m.forwards.View()))
+		focusedBottom = renderedHeight(lines) - 1
+	} else {
+		lines = append(lines, inputStyle.Render(m.forwards.View()))
+	}
+
+	lines = append(lines, "")
+	if m.focused == fieldExtra {
+		focusedTop = renderedHeight(lines)
+	}
+	lines = append(lines, labelStyle.Render("Extra directives:"))
+	if m.focused == fieldExtra {
+		lines = append(lines, inputFocusedStyle.Render(m.extra.View()))
+		focusedBottom = renderedHeight(lines) - 1
+	} else {
+		lines = append(lines, inputStyle.Render(m.extra.View()))
+	}
+
+	// Non-blocking warnings
+	if warning := m.Warning(); warning != "" {
+		lines = append(lines, "")
+		lines = append(lines, warningStyle.Render("Warning: "+warning))
 	}
 
 	// Error message
@@ -301,30 +668,37 @@ func (m *EditorModel) updateViewportContent() {
 
 	// Help text
 	lines = append(lines, "")
-	helpText := "Tab: next field | Shift+Tab: previous field | Enter: save | Esc: cancel | ↑↓: scroll"
+	helpText := "Tab: next field | Shift+Tab: previous field | Enter/Ctrl+S: save | Esc: cancel | ↑↓: scroll"
+	if m.focused == fieldExtra {
+		helpText = "Tab: next field | Shift+Tab: previous field | Enter: new line | Ctrl+S: save | Esc: cancel"
+	} else if m.focused == fieldSetEnv {
+		helpText = "↑↓: row | Ctrl+N: add row | Ctrl+X: remove row | Tab: next field | Enter/Ctrl+S: save | Esc: cancel"
+	} else if m.focused == fieldForwards {
+		helpText = "↑↓: row | Ctrl+N: add row | Ctrl+X: remove row | Ctrl+T: Local/Remote | Tab: next field | Enter/Ctrl+S: save | Esc: cancel"
+	} else if m.focused == fieldIdentityFile {
+		helpText = "Tab: next field | Shift+Tab: previous field | Ctrl+O: pick or generate a key | Enter/Ctrl+S: save | Esc: cancel"
+	}
 	lines = append(lines, helpStyle.Render(helpText))
 
 	content := strings.Join(lines, "\n")
 	m.viewport.SetContent(content)
 
-	// Scroll to show focused field (each field takes ~3 lines: empty line + label + input)
-	// Account for title and empty line at top (2 lines)
-	if m.focused >= 0 && m.focused < len(labels) {
-		// Calculate approximate line number of focused field (1-indexed for content)
-		// Title takes 1 line, empty line takes 1 line, then fields start
-		estimatedLine := 2 + m.focused*3 + 1
-
-		// Check if focused field is above visible area
-		if estimatedLine < m.viewport.YOffset {
-			// Scroll to show focused field at top
-			m.viewport.SetYOffset(estimatedLine - 1)
-		} else if estimatedLine+2 > m.viewport.YOffset+m.viewport.Height {
-			// Scroll to show focused field at bottom (field takes ~3 lines)
-			m.viewport.SetYOffset(estimatedLine + 2 - m.viewport.Height)
+	// Scroll so the focused field (label + input) is fully visible. Positions
+	// come from the rendered content, so multi-line inputs are accounted for.
+	if focusedTop >= 0 {
+		if focusedTop < m.viewport.YOffset {
+			m.viewport.SetYOffset(focusedTop)
+		} else if focusedBottom >= m.viewport.YOffset+m.viewport.Height {
+			m.viewport.SetYOffset(focusedBottom + 1 - m.viewport.Height)
 		}
 	}
 }
 
+// renderedHeight returns the number of terminal lines the joined blocks occupy
+func renderedHeight(blocks []string) int {
+	return lipgloss.Height(strings.Join(blocks, "\n"))
+}
+
 // View renders the editor view
 func (m *EditorModel) View() string {
 	// Update viewport content
@@ -333,11 +707,9 @@ func (m *EditorModel) View() string {
 	// Render viewport inside panel
 	view := detailPanelStyle.Width(m.width).Height(m.height).Render(m.viewport.View())
 
-	// Show key selector on top if open
+	// Show key selector in place of the form if open
 	if m.selectingKey {
-		selectorView := m.keySelector.View()
-		// Overlay the selector
-		return lipgloss.JoinVertical(lipgloss.Center, selectorView, view)
+		return m.keySelector.View()
 	}
 
 	// Ensure the view fills the available space and shows borders properly
@@ -0,0 +1,430 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+func TestEditorModel_ScrollsToLastField(t *testing.T) {
+	m := NewEditorModel()
+	m.SetEntry(nil)
+	m.SetSize(80, 16)
+	m.SetError("something went wrong")
+
+	for i := 0; i < focusCount-1; i++ {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		m.View()
+	}
+
+	if m.focused != fieldExtra {
+		t.Fatalf("Expected focus on the last field, got %d", m.focused)
+	}
+
+	visible := m.viewport.View()
+	if !strings.Contains(visible, "Extra directives:") {
+		t.Errorf("Last field label should be visible after scrolling, got:\n%s", visible)
+	}
+
+	// Going back to the first field scrolls up again
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.View()
+	if !strings.Contains(m.viewport.View(), "Host:") {
+		t.Errorf("First field label should be visible after wrapping around, got:\n%s", m.viewport.View())
+	}
+}
+
+func TestEditorModel_GetEntryKeepsRawLines(t *testing.T) {
+	original := &sshconfig.HostEntry{
+		Host:         "dev",
+		HostName:     "dev.example.com",
+		ForwardAgent: "yes",
+		LocalForward: []string{"8080 localhost:80", "5432 db:5432"},
+		RawLines:     []string{"Host dev", "    HostName dev.example.com", "    ServerAliveInterval 30"},
+	}
+
+	m := NewEditorModel()
+	m.SetEntry(original)
+	m.forwards.SetForwards([]string{"8080 localhost:80", " ", "6379 cache:6379"}, nil)
+
+	if err := m.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	entry := m.GetEntry()
+	if entry.ForwardAgent != "yes" {
+		t.Errorf("ForwardAgent: got %q, want %q", entry.ForwardAgent, "yes")
+	}
+	if len(entry.LocalForward) != 2 || entry.LocalForward[1] != "6379 cache:6379" {
+		t.Errorf("LocalForward: got %q", entry.LocalForward)
+	}
+	if len(entry.RawLines) != len(original.RawLines) {
+		t.Errorf("RawLines should be kept when editing, got %q", entry.RawLines)
+	}
+
+	m.fields[fieldForwardAgent].SetValue("maybe")
+	if err := m.Validate(); err == nil {
+		t.Error("Expected an error for an invalid ForwardAgent value")
+	}
+}
+
+func TestEditorModel_ForwardRows(t *testing.T) {
+	m := NewEditorModel()
+	m.SetEntry(&sshconfig.HostEntry{
+		Host:         "dev",
+		HostName:     "dev.example.com",
+		LocalForward: []string{"8080 localhost:80"},
+	})
+	for m.focused != fieldForwards {
+		m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+
+	// Add a row after the first, type into it and make it a RemoteForward
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m.Update(keyRunes("2222 localhost:22"))
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+
+	entry := m.GetEntry()
+	if len(entry.LocalForward) != 1 || entry.LocalForward[0] != "8080 localhost:80" {
+		t.Errorf("LocalForward: got %q", entry.LocalForward)
+	}
+	if len(entry.RemoteForward) != 1 || entry.RemoteForward[0] != "2222 localhost:22" {
+		t.Errorf("RemoteForward: got %q", entry.RemoteForward)
+	}
+
+	// Remove the first row
+	m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	entry = m.GetEntry()
+	if len(entry.LocalForward) != 0 || len(entry.RemoteForward) != 1 {
+		t.Errorf("After removing a row: local %q, remote %q", entry.LocalForward, entry.RemoteForward)
+	}
+
+	// A LocalForward needs a destination, a RemoteForward doesn't
+	m.forwards.SetForwards([]string{"8080"}, nil)
+	if err := m.Validate(); err == nil {
+		t.Error("Expected an error for a LocalForward without a destination")
+	}
+	m.forwards.SetForwards(nil, []string{"1080"})
+	if err := m.Validate(); err != nil {
+		t.Errorf("Dynamic RemoteForward should be valid, got %v", err)
+	}
+}
+
+func TestEditorModel_SetEnv(t *testing.T) {
+	m := NewEditorModel()
+	m.SetEntry(&sshconfig.HostEntry{
+		Host:     "dev",
+		HostName: "dev.example.com",
+		SetEnv:   []string{"FOO=bar", "A=1 B=2"},
+	})
+	if got := m.setEnv.Values()[0]; len(got) != 2 || got[1] != "A=1 B=2" {
+		t.Errorf("SetEnv rows: got %q", got)
+	}
+
+	// Add a row after the last one, skipping an empty row in between
+	for m.focused != fieldSetEnv {
+		m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m.Update(keyRunes("TERM=xterm"))
+	if err := m.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if entry := m.GetEntry(); len(entry.SetEnv) != 3 || entry.SetEnv[2] != "TERM=xterm" {
+		t.Errorf("SetEnv: got %q", entry.SetEnv)
+	}
+
+	m.setEnv.SetRows([]string{"FOO"})
+	if err := m.Validate(); err == nil {
+		t.Error("Expected an error for SetEnv without a value")
+	}
+}
+
+func TestEditorModel_SetEnvWithComma(t *testing.T) {
+	original := &sshconfig.HostEntry{
+		Host:     "dev",
+		HostName: "dev.example.com",
+		SetEnv:   []string{`LIST="a,b"`, "PATHS=/usr/bin,/bin"},
+	}
+	m := NewEditorModel()
+	m.SetEntry(original)
+
+	if err := m.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	entry := m.GetEntry()
+	if strings.Join(entry.SetEnv, "\n") != strings.Join(original.SetEnv, "\n") {
+		t.Errorf("SetEnv should round-trip unchanged, got %q", entry.SetEnv)
+	}
+}
+
+func TestEditorModel_NewEntryDefaultsFromEnv(t *testing.T) {
+	t.Setenv(defaultUserEnv, "deploy")
+	t.Setenv(defaultPortEnv, " 2222 ")
+	t.Setenv(defaultIdentityFileEnv, "~/.ssh/id_work")
+
+	m := NewEditorModel()
+	m.SetEntry(nil)
+	if got := m.fields[fieldUser].Value(); got != "deploy" {
+		t.Errorf("User: got %q, want %q", got, "deploy")
+	}
+	if got := m.fields[fieldPort].Value(); got != "2222" {
+		t.Errorf("Port: got %q, want %q", got, "2222")
+	}
+	if got := m.fields[fieldIdentityFile].Value(); got != "~/.ssh/id_work" {
+		t.Errorf("IdentityFile: got %q, want %q", got, "~/.ssh/id_work")
+	}
+
+	// Set but empty leaves the field blank
+	t.Setenv(defaultPortEnv, "")
+	m.SetEntry(nil)
+	if got := m.fields[fieldPort].Value(); got != "" {
+		t.Errorf("Port: got %q, want it empty", got)
+	}
+
+	// Existing entries keep their own values
+	m.SetEntry(&sshconfig.HostEntry{Host: "web", HostName: "web.example.com", User: "admin"})
+	if got := m.fields[fieldUser].Value(); got != "admin" {
+		t.Errorf("User: got %q, want %q", got, "admin")
+	}
+}
+
+func TestEditorModel_SetClone(t *testing.T) {
+	original := &sshconfig.HostEntry{
+		Host:         "web",
+		HostName:     "web.example.com",
+		User:         "deploy",
+		IdentityFile: "~/.ssh/id_ed25519",
+		Tags:         []string{"prod", "web"},
+		RawLines:     []string{"Host web", "    HostName web.example.com", "    ServerAliveInterval 30"},
+	}
+
+	m := NewEditorModel()
+	m.SetClone(original)
+
+	if !m.isNew {
+		t.Error("Cloned entry should be saved as a new host")
+	}
+
+	entry := m.GetEntry()
+	if entry.Host != "web-copy" {
+		t.Errorf("Host: got %q, want %q", entry.Host, "web-copy")
+	}
+	if entry.IdentityFile != original.IdentityFile {
+		t.Errorf("IdentityFile: got %q, want %q", entry.IdentityFile, original.IdentityFile)
+	}
+	if len(entry.Tags) != 2 || entry.Tags[0] != "prod" || entry.Tags[1] != "web" {
+		t.Errorf("Tags: got %v, want [prod web]", entry.Tags)
+	}
+	if len(entry.RawLines) != len(original.RawLines) {
+		t.Errorf("Unknown directives should carry over, got %q", entry.RawLines)
+	}
+
+	// Opening the editor for a new host afterwards starts from scratch
+	m.SetEntry(nil)
+	if entry := m.GetEntry(); entry.Host != "" || entry.RawLines != nil {
+		t.Errorf("SetEntry(nil) should clear the clone, got %+v", entry)
+	}
+}
+
+func TestEditorModel_ExtraDirectives(t *testing.T) {
+	m := NewEditorModel()
+	m.SetEntry(&sshconfig.HostEntry{
+		Host:            "dev",
+		HostName:        "dev.example.com",
+		ExtraDirectives: []string{"Compression yes"},
+	})
+
+	if got := m.GetEntry().ExtraDirectives; len(got) != 1 || got[0] != "Compression yes" {
+		t.Errorf("ExtraDirectives: got %q", got)
+	}
+
+	m.extra.SetValue("  Compression yes \n\nServerAliveInterval 30")
+	if err := m.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if got := m.GetEntry().ExtraDirectives; len(got) != 2 || got[1] != "ServerAliveInterval 30" {
+		t.Errorf("ExtraDirectives: got %q", got)
+	}
+
+	// Clearing the field removes the directives instead of keeping the raw lines
+	m.extra.SetValue("")
+	if got := m.GetEntry().ExtraDirectives; got == nil || len(got) != 0 {
+		t.Errorf("Cleared ExtraDirectives should be empty but non-nil, got %#v", got)
+	}
+
+	for _, bad := range []string{"Host other", "User root", "Compression", "# note"} {
+		m.extra.SetValue(bad)
+		if err := m.Validate(); err == nil {
+			t.Errorf("Expected a validation error for %q", bad)
+		}
+	}
+}
+
+func TestEditorModel_HostFollowedByMatchBlock(t *testing.T) {
+	config := "Host web\n    HostName web.example.com\n\nMatch host *.internal\n    User admin\n    Compression yes\n"
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	entries, _, err := sshconfig.ParseConfig(path)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	m := NewEditorModel()
+	m.SetEntry(entries[0])
+	if err := m.Validate(); err != nil {
+		t.Fatalf("A host followed by a Match block should be savable, got %v", err)
+	}
+	entry := m.GetEntry()
+	if entry.User != "" || len(entry.ExtraDirectives) != 0 {
+		t.Errorf("Match block directives leaked into the host: User %q, extras %q", entry.User, entry.ExtraDirectives)
+	}
+
+	// Editing the host adds its new directive before the Match block, which is kept as is
+	m.fields[fieldUser].SetValue("deploy")
+	if err := sshconfig.UpdateEntry(path, "web", m.GetEntry()); err != nil {
+		t.Fatalf("UpdateEntry failed: %v", err)
+	}
+	want := "Host web\n    HostName web.example.com\n    User deploy\n\nMatch host *.internal\n    User admin\n    Compression yes\n"
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Errorf("Unexpected config:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestEditorModel_ValidatePort(t *testing.T) {
+	m := NewEditorModel()
+	m.SetEntry(&sshconfig.HostEntry{Host: "dev", HostName: "dev.example.com"})
+
+	for _, port := range []string{"", "22", " 2222 ", "65535"} {
+		m.fields[fieldPort].SetValue(port)
+		if err := m.Validate(); err != nil {
+			t.Errorf("Port %q: unexpected error %v", port, err)
+		}
+	}
+
+	for _, port := range []string{"2222x", "0", "65536", "-1", "ssh"} {
+		m.fields[fieldPort].SetValue(port)
+		if err := m.Validate(); err == nil {
+			t.Errorf("Port %q: expected a validation error", port)
+		}
+	}
+
+	m.fields[fieldPort].SetValue(" 22 ")
+	if got := m.GetEntry().Port; got != "22" {
+		t.Errorf("Port should be trimmed, got %q", got)
+	}
+}
+
+func TestEditorModel_KeyGenerated(t *testing.T) {
+	m := NewEditorModel()
+	m.SetEntry(nil)
+
+	m.Update(keyGeneratedMsg{err: errors.New("ssh-keygen not found in PATH")})
+	if m.errorMsg != "ssh-keygen not found in PATH" {
+		t.Errorf("Expected the error to be shown, got %q", m.errorMsg)
+	}
+
+	m.Update(keyGeneratedMsg{key: "~/.ssh/id_ed25519_work"})
+	if m.errorMsg != "" || m.fields[fieldIdentityFile].Value() != "~/.ssh/id_ed25519_work" {
+		t.Errorf("Expected the new key in IdentityFile, got %q (error %q)", m.fields[fieldIdentityFile].Value(), m.errorMsg)
+	}
+}
+
+func TestEditorModel_CtrlOOpensKeySelector(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := NewEditorModel()
+	m.SetEntry(nil)
+	m.SetSize(80, 30)
+
+	// Only the IdentityFile field opens the selector
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.SelectingKey() {
+		t.Fatal("Ctrl+O outside IdentityFile should not open the key selector")
+	}
+
+	for m.focused != fieldIdentityFile {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if !m.SelectingKey() || cmd == nil {
+		t.Fatal("Ctrl+O in IdentityFile should open the key selector and load keys")
+	}
+
+	// Without ~/.ssh the selector still offers to generate a key or type a path
+	m, _ = m.Update(cmd())
+	if view := m.View(); !strings.Contains(view, generateKeyOption) || !strings.Contains(view, customPathOption) {
+		t.Errorf("Key selector not shown:\n%s", view)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.SelectingKey() {
+		t.Error("Esc should close the key selector")
+	}
+}
+
+func TestEditorModel_AgentDirectives(t *testing.T) {
+	m := NewEditorModel()
+	m.SetGlobal()
+	if m.fields[fieldHost].Value() != "*" || m.fields[fieldUser].Value() != "" || m.fields[fieldPort].Value() != "" {
+		t.Errorf("Host * form should have no User/Port defaults, got user %q port %q", m.fields[fieldUser].Value(), m.fields[fieldPort].Value())
+	}
+
+	m.fields[fieldAddKeysToAgent].SetValue("Yes")
+	m.fields[fieldUseKeychain].SetValue("yes")
+	m.fields[fieldIdentitiesOnly].SetValue("no")
+	if err := m.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	entry := m.GetEntry()
+	if entry.AddKeysToAgent != "yes" || entry.UseKeychain != "yes" || entry.IdentitiesOnly != "no" {
+		t.Errorf("Agent directives not read from the form: %+v", entry)
+	}
+
+	for _, value := range []string{"confirm 30m", "1h30m", "600"} {
+		m.fields[fieldAddKeysToAgent].SetValue(value)
+		if err := m.Validate(); err != nil {
+			t.Errorf("AddKeysToAgent %q should be valid, got %v", value, err)
+		}
+	}
+	for _, value := range []string{"always", "h1"} {
+		m.fields[fieldAddKeysToAgent].SetValue(value)
+		if err := m.Validate(); err == nil {
+			t.Errorf("AddKeysToAgent %q should be rejected", value)
+		}
+	}
+
+	m.fields[fieldAddKeysToAgent].SetValue("")
+	m.fields[fieldUseKeychain].SetValue("sometimes")
+	if err := m.Validate(); err == nil || !strings.Contains(err.Error(), "UseKeychain") {
+		t.Errorf("Expected a UseKeychain error, got %v", err)
+	}
+}
+
+func TestEditorModel_ProxyCommand(t *testing.T) {
+	m := NewEditorModel()
+	m.SetEntry(&sshconfig.HostEntry{Host: "internal", HostName: "10.0.0.5", ProxyCommand: "ssh -W %h:%p  bastion"})
+	if got := m.fields[fieldProxyCommand].Value(); got != "ssh -W %h:%p  bastion" {
+		t.Errorf("ProxyCommand field = %q", got)
+	}
+	if err := m.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if entry := m.GetEntry(); entry.ProxyCommand != "ssh -W %h:%p  bastion" {
+		t.Errorf("ProxyCommand should be kept verbatim, got %q", entry.ProxyCommand)
+	}
+
+	m.fields[fieldProxyJump].SetValue("bastion")
+	if err := m.Validate(); err == nil || !strings.Contains(err.Error(), "ProxyJump or ProxyCommand") {
+		t.Errorf("Expected an error for both ProxyJump and ProxyCommand, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fingerprintTimeout bounds how long `ssh-keygen -lv` may take
+const fingerprintTimeout = 3 * time.Second
+
+// fingerprint is the fingerprint and randomart of an IdentityFile
+type fingerprint struct {
+	art  string // ssh-keygen -lv output, "" when unavailable
+	done bool   // false while ssh-keygen is still running
+}
+
+// fingerprintMsg carries the result of fingerprinting an IdentityFile
+type fingerprintMsg struct {
+	identityFile string
+	art          string
+}
+
+// publicKeyPath returns the public key belonging to an IdentityFile value
+func publicKeyPath(identityFile string) (string, error) {
+	path, err := expandKeyPath(strings.TrimSpace(identityFile))
+	if err != nil {
+		return "", err
+	}
+	if strings.HasSuffix(path, ".pub") {
+		return path, nil
+	}
+	return path + ".pub", nil
+}
+
+// loadFingerprint runs `ssh-keygen -lv` on the public key of identityFile in
+// the background. A missing .pub file or ssh-keygen yields an empty result.
+func loadFingerprint(identityFile string) tea.Cmd {
+	return func() tea.Msg {
+		unavailable := fingerprintMsg{identityFile: identityFile}
+
+		pubKey, err := publicKeyPath(identityFile)
+		if err != nil {
+			return unavailable
+		}
+		if _, err := os.Stat(pubKey); err != nil {
+			return unavailable
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), fingerprintTimeout)
+		defer cancel()
+
+		out, err := exec.CommandContext(ctx, "ssh-keygen", "-lv", "-f", pubKey).Output()
+		if err != nil {
+			return unavailable
+		}
+		return fingerprintMsg{identityFile: identityFile, art: strings.TrimRight(string(out), "\n")}
+	}
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	key := filepath.Join(dir, "id_ed25519")
+
+	// Fake ssh-keygen printing the file it was asked about
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho \"256 SHA256:abc $3 (ED25519)\"\necho '+--[ED25519 256]--+'\n"
+	if err := os.WriteFile(filepath.Join(bin, "ssh-keygen"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create fake ssh-keygen: %v", err)
+	}
+	t.Setenv("PATH", bin)
+
+	// Missing .pub file
+	msg := loadFingerprint(key)().(fingerprintMsg)
+	if msg.identityFile != key || msg.art != "" {
+		t.Errorf("Missing public key should be unavailable, got %+v", msg)
+	}
+
+	if err := os.WriteFile(key+".pub", []byte("ssh-ed25519 AAAA"), 0644); err != nil {
+		t.Fatalf("Failed to create public key: %v", err)
+	}
+	msg = loadFingerprint(key)().(fingerprintMsg)
+	want := "256 SHA256:abc " + key + ".pub (ED25519)\n+--[ED25519 256]--+"
+	if msg.art != want {
+		t.Errorf("art: got %q, want %q", msg.art, want)
+	}
+
+	// An IdentityFile pointing at the .pub file is used as-is
+	msg = loadFingerprint(key + ".pub")().(fingerprintMsg)
+	if msg.art != want {
+		t.Errorf("art for .pub IdentityFile: got %q, want %q", msg.art, want)
+	}
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of forward rows
+const (
+	forwardLocal = iota
+	forwardRemote
+)
+
+// ForwardsModel edits a host's port forwards as a list of rows, one
+// LocalForward/RemoteForward directive each
+type ForwardsModel struct {
+	*RowsModel
+}
+
+// NewForwardsModel creates an empty forwards list
+func NewForwardsModel() *ForwardsModel {
+	return &ForwardsModel{NewRowsModel("8080 localhost:80 (optional)", "Local  ", "Remote ")}
+}
+
+// SetForwards replaces the rows with the given forwards, local ones first
+func (m *ForwardsModel) SetForwards(local, remote []string) {
+	m.SetRows(local, remote)
+}
+
+// Forwards returns the trimmed, non-empty LocalForward and RemoteForward rules
+func (m *ForwardsModel) Forwards() (local, remote []string) {
+	values := m.Values()
+	return values[forwardLocal], values[forwardRemote]
+}
+
+// Validate checks every non-empty row: a LocalForward needs a listen port and
+// a destination, a RemoteForward may leave out the destination (dynamic SOCKS)
+func (m *ForwardsModel) Validate() error {
+	for _, row := range m.rows {
+		value := strings.TrimSpace(row.input.Value())
+		if value == "" {
+			continue
+		}
+		fields := len(strings.Fields(value))
+		if row.kind == forwardLocal && fields != 2 {
+			return fmt.Errorf("LocalForward %q must be \"[bind_address:]port host:hostport\"", value)
+		}
+		if row.kind == forwardRemote && fields != 1 && fields != 2 {
+			return fmt.Errorf("RemoteForward %q must be \"[bind_address:]port [host:hostport]\"", value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyMatch reports whether all runes of pattern appear in text in order
+// (case-insensitive) and returns a score where higher is a better match.
+// Consecutive runes and matches at word starts score higher.
+func fuzzyMatch(pattern, text string) (bool, int) {
+	if pattern == "" {
+		return true, 0
+	}
+
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+
+	score := 0
+	pi := 0
+	prevMatch := -2
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if t[ti] != p[pi] {
+			continue
+		}
+		score++
+		if ti == prevMatch+1 {
+			score += 2 // consecutive
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 3 // word start
+		}
+		prevMatch = ti
+		pi++
+	}
+
+	if pi < len(p) {
+		return false, 0
+	}
+	return true, score
+}
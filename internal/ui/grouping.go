@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+// ListGrouping controls how the host list is split into groups with headers
+type ListGrouping int
+
+const (
+	GroupNone       ListGrouping = iota
+	GroupByTag                   // A host is listed under every tag it carries
+	GroupByFirstTag              // A host is listed once, under its first tag
+	groupingCount
+)
+
+// untaggedGroup is the header of hosts without tags, listed last
+const untaggedGroup = "(untagged)"
+
+// listGroup is a group header in the list
+type listGroup struct {
+	name      string
+	count     int  // Hosts listed in the group
+	collapsed bool // Only the header is shown, the hosts are left out
+}
+
+// String returns a short label for the grouping
+func (g ListGrouping) String() string {
+	switch g {
+	case GroupByTag:
+		return "tag"
+	case GroupByFirstTag:
+		return "first tag"
+	default:
+		return "none"
+	}
+}
+
+// Next returns the grouping that follows g, wrapping around
+func (g ListGrouping) Next() ListGrouping {
+	return (g + 1) % groupingCount
+}
+
+// groupEntries orders entries into groups (alphabetically, untagged last),
+// keeping their order within each group and leaving out the hosts of collapsed
+// groups. It returns the grouped entries and the headers shown before each
+// index: a group's header is keyed by the index of its first entry, and
+// collapsed groups by the index of the next entry shown (len(grouped) after
+// the last one).
+func groupEntries(entries []*sshconfig.HostEntry, grouping ListGrouping, collapsed map[string]bool) ([]*sshconfig.HostEntry, map[int][]listGroup) {
+	if grouping == GroupNone {
+		return entries, nil
+	}
+
+	members := make(map[string][]*sshconfig.HostEntry)
+	var names []string
+	add := func(name string, entry *sshconfig.HostEntry) {
+		if _, ok := members[name]; !ok && name != untaggedGroup {
+			names = append(names, name)
+		}
+		members[name] = append(members[name], entry)
+	}
+
+	for _, entry := range entries {
+		tags := sshconfig.UniqueTags(entry.Tags)
+		switch {
+		case len(tags) == 0:
+			add(untaggedGroup, entry)
+		case grouping == GroupByFirstTag:
+			add(tags[0], entry)
+		default:
+			for _, tag := range tags {
+				add(tag, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	if len(members[untaggedGroup]) > 0 {
+		names = append(names, untaggedGroup)
+	}
+
+	grouped := make([]*sshconfig.HostEntry, 0, len(entries))
+	headers := make(map[int][]listGroup, len(names))
+	for _, name := range names {
+		group := listGroup{name: name, count: len(members[name]), collapsed: collapsed[name]}
+		headers[len(grouped)] = append(headers[len(grouped)], group)
+		if !group.collapsed {
+			grouped = append(grouped, members[name]...)
+		}
+	}
+	return grouped, headers
+}
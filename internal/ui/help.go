@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpBinding is one key (or key combination) and what it does
+type helpBinding struct {
+	keys string
+	desc string
+}
+
+// helpSection groups the bindings of one context
+type helpSection struct {
+	title    string
+	bindings []helpBinding
+}
+
+// helpSections lists every keybinding, grouped by context
+var helpSections = []helpSection{
+	{title: "List", bindings: []helpBinding{
+		{"j / ↓", "Move down"},
+		{"k / ↑", "Move up"},
+		{"g g / G", "Jump to the first / last host"},
+		{"1-9", "Jump to the Nth host; more digits for larger numbers"},
+		{"' + text", "Jump to the first host whose alias starts with (or contains) the text"},
+		{"enter", "Connect to the selected host"},
+		{"t", "Connect inside a per-host tmux session"},
+		{"w", "Connect once as another user and/or port (user:port)"},
+		{"l", "Tail the host's logs"},
+		{"/", "Search"},
+		{"T", "Filter by tags"},
+		{"a", "Add a host"},
+		{"c", "Duplicate the selected host"},
+		{"e", "Edit the selected host"},
+		{"*", "Edit the global Host * block (agent settings and other defaults)"},
+		{"n", "Edit the host's private notes (kept out of the SSH config)"},
+		{"D", "Edit the Description inline"},
+		{"Space", "Select / unselect the host for a bulk delete"},
+		{"Esc", "Clear the selection"},
+		{"d", "Delete the selected host, or every selected host"},
+		{"u", "Undo the last change"},
+		{"i", "Jump to the next host using the same IdentityFile"},
+		{"y", "Copy the full ssh command"},
+		{"Y", "Copy the connection string (user@host)"},
+		{"P", "Toggle Port between 22 and the remembered alternate"},
+		{"O", "Close the multiplexed master connection"},
+		{"S", "Split a multi-alias host into separate entries"},
+		{"E", "Export the host to a standalone file"},
+		{"C", "Open the config file in $EDITOR"},
+		{"ctrl+r", "Reload the config from disk"},
+		{"H", "Edit the header comments"},
+		{"s", "Cycle the sort order"},
+		{"o", "Reverse the sort order"},
+		{"p", "Pin the host to the top of the list (again to unpin)"},
+		{"g", "Cycle grouping: none, by tag, by first tag (after a short pause, so gg still works)"},
+		{"z", "Collapse the host's group (right below a collapsed header: expand it)"},
+		{"Z", "Expand all groups"},
+		{"v", "Toggle cards / table layout"},
+		{"m", "Toggle compact one-line entries"},
+		{"R", "Show the host's block exactly as written in the config"},
+		{"r", "Re-check reachability"},
+		{"A", "Test a non-interactive SSH login (auth ok / auth failed / timeout)"},
+		{"x", "Clear all visit counts"},
+		{"ctrl+p", "Command palette"},
+		{"?", "This help"},
+		{"q / ctrl+c", "Quit"},
+	}},
+	{title: "Search", bindings: []helpBinding{
+		{"type", "Filter by alias, hostname, user, description or tag"},
+		{"enter", "Keep the filter and return to the list"},
+		{"esc", "Clear the filter and return to the list"},
+	}},
+	{title: "Editor", bindings: []helpBinding{
+		{"tab / shift+tab", "Next / previous field"},
+		{"ctrl+o", "In IdentityFile: pick or generate a key"},
+		{"enter / ctrl+s", "Review and save (enter adds a line in Extra directives)"},
+		{"esc", "Cancel"},
+	}},
+	{title: "Review changes", bindings: []helpBinding{
+		{"y / enter", "Write the changes"},
+		{"n / esc", "Back to the editor"},
+		{"j / k", "Scroll the diff"},
+	}},
+	{title: "Delete / clear visits", bindings: []helpBinding{
+		{"y", "Confirm"},
+		{"n / esc", "Cancel"},
+		{"tab", "For a multi-alias host: delete the whole block or just one alias"},
+	}},
+	{title: "Command palette", bindings: []helpBinding{
+		{"type", "Filter actions"},
+		{"↑ / ↓", "Move through the actions"},
+		{"enter", "Run the highlighted action"},
+		{"esc", "Close"},
+	}},
+	{title: "Tag filter", bindings: []helpBinding{
+		{"j / k", "Move"},
+		{"space / x", "Toggle a tag"},
+		{"c", "Clear all tags"},
+		{"enter", "Apply"},
+		{"esc", "Cancel"},
+	}},
+}
+
+// helpKeyWidth is the width of the key column
+const helpKeyWidth = 18
+
+// HelpModel is the full-screen keybinding overlay
+type HelpModel struct {
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// NewHelpModel creates a new help overlay
+func NewHelpModel() *HelpModel {
+	return &HelpModel{
+		viewport: viewport.New(0, 0),
+	}
+}
+
+// Open scrolls the help back to the top
+func (m *HelpModel) Open() {
+	m.viewport.GotoTop()
+}
+
+// SetSize sets the size of the overlay and re-wraps the bindings
+func (m *HelpModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	// Leave room for panel borders/padding, title and help text
+	m.viewport.Width = max(10, width-4)
+	m.viewport.Height = max(3, height-6)
+	m.viewport.SetContent(renderHelp(m.viewport.Width))
+}
+
+// Update scrolls the help
+func (m *HelpModel) Update(msg tea.Msg) (*HelpModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the overlay
+func (m *HelpModel) View() string {
+	var lines []string
+	lines = append(lines, titleStyle.Render("Keybindings"))
+	lines = append(lines, m.viewport.View())
+	lines = append(lines, helpStyle.Render("j/k: scroll | ?/Esc/q: close"))
+
+	return detailPanelStyle.Width(m.width).Height(m.height).Render(strings.Join(lines, "\n"))
+}
+
+// renderHelp lays out every section for the given width, wrapping long
+// descriptions under their own column (and the key column itself when the
+// terminal is very narrow)
+func renderHelp(width int) string {
+	keyWidth := min(helpKeyWidth, max(1, width/3))
+	descWidth := max(1, width-keyWidth-1)
+	keyStyle := helpKeyStyle.Copy().Width(keyWidth)
+	descStyle := valueStyle.Copy().Width(descWidth)
+
+	var blocks []string
+	for i, section := range helpSections {
+		if i > 0 {
+			blocks = append(blocks, "")
+		}
+		blocks = append(blocks, helpSectionStyle.Copy().Width(width).Render(section.title))
+		for _, binding := range section.bindings {
+			blocks = append(blocks, lipgloss.JoinHorizontal(lipgloss.Top,
+				keyStyle.Render(binding.keys),
+				descStyle.Render(binding.desc),
+			))
+		}
+	}
+	return strings.Join(blocks, "\n")
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRenderHelp_Wraps(t *testing.T) {
+	for _, width := range []int{20, 40, 100} {
+		content := renderHelp(width)
+		for _, line := range strings.Split(content, "\n") {
+			if w := lipgloss.Width(line); w > width {
+				t.Errorf("width %d: line is %d wide: %q", width, w, line)
+			}
+		}
+		// Every description is still there, just wrapped
+		if !strings.Contains(strings.Join(strings.Fields(content), " "), "Jump to the next host using the same IdentityFile") {
+			t.Errorf("width %d: description missing or mangled", width)
+		}
+	}
+}
+
+func TestModel_HelpOverlay(t *testing.T) {
+	m := newTestModel(t, "Host web\n    HostName web.example.com\n")
+	m.Update(tea.WindowSizeMsg{Width: 50, Height: 20})
+
+	for _, key := range []tea.KeyMsg{keyRunes("?"), keyRunes("q"), keyMsgFor("esc")} {
+		m.Update(keyRunes("?"))
+		if m.mode != ModeHelp {
+			t.Fatalf("Expected help mode, got %d", m.mode)
+		}
+		if view := m.View(); !strings.Contains(view, "Keybindings") {
+			t.Errorf("Help overlay not rendered:\n%s", view)
+		}
+		m.Update(key)
+		if m.mode != ModeList {
+			t.Errorf("%q should close the help, got mode %d", key.String(), m.mode)
+		}
+	}
+}
+
+func TestListStatusKeys_FitNormalTerminal(t *testing.T) {
+	// The bar is padded by one column on each side
+	if w := lipgloss.Width(listStatusKeys) + 2; w > 80 {
+		t.Errorf("Status bar is %d wide, the ? and q hints would be cut off at 80 columns", w)
+	}
+	for _, hint := range []string{"?: help", "q: quit"} {
+		if !strings.Contains(listStatusKeys, hint) {
+			t.Errorf("Status bar should show %q", hint)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// matchRanges returns the rune ranges [start, end) of every non-overlapping,
+// case-insensitive occurrence of term in text
+func matchRanges(text, term string) [][2]int {
+	t := []rune(text)
+	p := []rune(term)
+	if len(p) == 0 {
+		return nil
+	}
+
+	var ranges [][2]int
+	for i := 0; i+len(p) <= len(t); {
+		matched := true
+		for j, r := range p {
+			if unicode.ToLower(t[i+j]) != unicode.ToLower(r) {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			i++
+			continue
+		}
+		ranges = append(ranges, [2]int{i, i + len(p)})
+		i += len(p)
+	}
+	return ranges
+}
+
+// highlightMatch renders the parts of text matching the search term with
+// searchMatchStyle and the rest with base. Text without a match is returned
+// unstyled, so it keeps the style of the line it is placed in.
+func highlightMatch(text, term string, base lipgloss.Style) string {
+	ranges := matchRanges(text, term)
+	if len(ranges) == 0 {
+		return text
+	}
+
+	runes := []rune(text)
+	var out string
+	prev := 0
+	for _, r := range ranges {
+		if r[0] > prev {
+			out += base.Render(string(runes[prev:r[0]]))
+		}
+		out += searchMatchStyle.Render(string(runes[r[0]:r[1]]))
+		prev = r[1]
+	}
+	if prev < len(runes) {
+		out += base.Render(string(runes[prev:]))
+	}
+	return out
+}
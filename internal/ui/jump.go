@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// jumpTimeout is how long the numeric jump buffer waits for another digit
+const jumpTimeout = time.Second
+
+// jumpTimeoutMsg clears the jump buffer unless another digit arrived since
+type jumpTimeoutMsg struct {
+	seq int
+}
+
+// handleJumpKey selects the Nth visible entry as digits are typed (like vim
+// counts). Any non-digit key clears the buffer and is left unhandled.
+func (m *Model) handleJumpKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	key := msg.String()
+	isDigit := len(key) == 1 && key[0] >= '0' && key[0] <= '9'
+	if !isDigit || (key == "0" && m.jumpBuffer == "") {
+		m.jumpBuffer = ""
+		return false, nil
+	}
+
+	m.jumpBuffer += key
+	n, _ := strconv.Atoi(m.jumpBuffer)
+	m.listModel.SetSelected(n - 1)
+	m.updateDetailView()
+	m.statusMsg = fmt.Sprintf("Jump to %s", m.jumpBuffer)
+
+	m.jumpSeq++
+	seq := m.jumpSeq
+	return true, tea.Tick(jumpTimeout, func(time.Time) tea.Msg {
+		return jumpTimeoutMsg{seq: seq}
+	})
+}
+
+// clearJump ends the current jump when its timeout fires
+func (m *Model) clearJump(msg jumpTimeoutMsg) {
+	if msg.seq != m.jumpSeq || m.jumpBuffer == "" {
+		return
+	}
+	if m.statusMsg == fmt.Sprintf("Jump to %s", m.jumpBuffer) {
+		m.statusMsg = ""
+	}
+	m.jumpBuffer = ""
+}
+
+// gPrefixTimeout is how long a lone g waits for a second g before it cycles
+// the grouping
+const gPrefixTimeout = 500 * time.Millisecond
+
+// gPrefixTimeoutMsg resolves a pending g as a grouping change unless another
+// key arrived since
+type gPrefixTimeoutMsg struct {
+	seq int
+}
+
+// handleGotoKey handles the vim-style G (last entry) and gg (first entry)
+// jumps. A g not followed by another g cycles the grouping, either when the
+// next key arrives or when gPrefixTimeout expires.
+func (m *Model) handleGotoKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	key := msg.String()
+	if m.gPending {
+		m.gPending = false
+		if key == "g" {
+			m.listModel.SetSelected(0)
+			m.updateDetailView()
+			return true, nil
+		}
+		m.cycleGrouping()
+	}
+
+	switch key {
+	case "g":
+		m.gPending = true
+		m.gSeq++
+		seq := m.gSeq
+		return true, tea.Tick(gPrefixTimeout, func(time.Time) tea.Msg {
+			return gPrefixTimeoutMsg{seq: seq}
+		})
+	case "G":
+		m.listModel.SetSelected(len(m.listModel.filtered) - 1)
+		m.updateDetailView()
+		return true, nil
+	}
+	return false, nil
+}
+
+// resolveGPrefix cycles the grouping for a lone g once its timeout fires
+func (m *Model) resolveGPrefix(msg gPrefixTimeoutMsg) {
+	if msg.seq != m.gSeq || !m.gPending {
+		return
+	}
+	m.gPending = false
+	m.cycleGrouping()
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestModel_NumericJump(t *testing.T) {
+	config := ""
+	for i := 1; i <= 12; i++ {
+		config += "Host h" + string(rune('a'+i-1)) + "\n    HostName 10.0.0.1\n"
+	}
+	m := newTestModel(t, config)
+
+	m.Update(keyRunes("3"))
+	if got := m.listModel.GetSelectedIndex(); got != 2 {
+		t.Errorf("After 3: selected %d, want 2", got)
+	}
+
+	m.Update(keyRunes("1"))
+	if got := m.listModel.GetSelectedIndex(); got != 11 {
+		t.Errorf("After 31: selected %d, want the last entry (11)", got)
+	}
+
+	// A stale timeout doesn't clear the buffer
+	m.Update(jumpTimeoutMsg{seq: m.jumpSeq - 1})
+	if m.jumpBuffer != "31" {
+		t.Errorf("Stale timeout cleared the buffer: %q", m.jumpBuffer)
+	}
+
+	m.Update(jumpTimeoutMsg{seq: m.jumpSeq})
+	if m.jumpBuffer != "" || m.statusMsg != "" {
+		t.Errorf("Timeout should clear the jump, got buffer %q status %q", m.jumpBuffer, m.statusMsg)
+	}
+
+	m.Update(keyRunes("1"))
+	m.Update(keyRunes("0"))
+	if got := m.listModel.GetSelectedIndex(); got != 9 {
+		t.Errorf("After 10: selected %d, want 9", got)
+	}
+
+	// Any other key ends the jump
+	m.Update(keyRunes("k"))
+	m.Update(keyRunes("2"))
+	if got := m.listModel.GetSelectedIndex(); got != 1 {
+		t.Errorf("After k then 2: selected %d, want 1", got)
+	}
+
+	// 0 can't start a jump
+	m.jumpBuffer = ""
+	m.Update(keyRunes("0"))
+	if m.jumpBuffer != "" {
+		t.Errorf("0 should not start a jump, got %q", m.jumpBuffer)
+	}
+}
+
+func TestModel_GotoTopAndBottom(t *testing.T) {
+	m := newTestModel(t, "Host a\n    HostName 10.0.0.1\n# Tags: web\nHost b\n    HostName 10.0.0.2\nHost c\n    HostName 10.0.0.3\n")
+
+	m.Update(keyRunes("G"))
+	if got := m.listModel.GetSelectedIndex(); got != 2 {
+		t.Errorf("After G: selected %d, want 2", got)
+	}
+	if m.detailModel.entry != m.listModel.GetSelected() {
+		t.Errorf("Detail view not updated after G")
+	}
+
+	m.Update(keyRunes("g"))
+	m.Update(keyRunes("g"))
+	if got := m.listModel.GetSelectedIndex(); got != 0 {
+		t.Errorf("After gg: selected %d, want 0", got)
+	}
+	if m.listModel.Grouping() != GroupNone {
+		t.Errorf("gg should not change the grouping")
+	}
+
+	// A lone g cycles the grouping once its timeout fires
+	m.Update(keyRunes("g"))
+	m.Update(gPrefixTimeoutMsg{seq: m.gSeq - 1})
+	if m.listModel.Grouping() != GroupNone {
+		t.Errorf("Stale timeout changed the grouping")
+	}
+	m.Update(gPrefixTimeoutMsg{seq: m.gSeq})
+	if m.listModel.Grouping() == GroupNone || m.gPending {
+		t.Errorf("Lone g should cycle the grouping, got %v (pending %v)", m.listModel.Grouping(), m.gPending)
+	}
+
+	// ... or as soon as another key arrives
+	m.Update(keyRunes("g"))
+	m.Update(keyRunes("j"))
+	if m.listModel.Grouping() != GroupByFirstTag {
+		t.Errorf("g followed by j should cycle the grouping, got %v", m.listModel.Grouping())
+	}
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyTypes are the key types offered when generating a key
+var keyTypes = []string{"ed25519", "rsa"}
+
+// keyGeneratedMsg is sent when ssh-keygen finishes (or couldn't be started)
+type keyGeneratedMsg struct {
+	key string // Path as entered (e.g. ~/.ssh/id_ed25519_work)
+	err error
+}
+
+// defaultKeyPath suggests a file name for a new key of keyType
+func defaultKeyPath(keyType string) string {
+	return "~/.ssh/id_" + keyType
+}
+
+// keygenCommand returns the ssh-keygen command line that creates a keyType
+// key at path, refusing to overwrite an existing key
+func keygenCommand(path, keyType string) ([]string, error) {
+	if path == "" {
+		return nil, errors.New("key file name is required")
+	}
+
+	expanded, err := expandKeyPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, existing := range []string{expanded, expanded + ".pub"} {
+		if _, err := os.Stat(existing); err == nil {
+			return nil, fmt.Errorf("%s already exists - pick another file name", existing)
+		}
+	}
+
+	keygen, err := exec.LookPath("ssh-keygen")
+	if err != nil {
+		return nil, errors.New("ssh-keygen not found in PATH")
+	}
+
+	argv := []string{keygen, "-t", keyType, "-f", expanded}
+	switch keyType {
+	case "ed25519":
+	case "rsa":
+		argv = append(argv, "-b", "4096")
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", keyType)
+	}
+	return argv, nil
+}
+
+// expandKeyPath expands a leading tilde in a key path
+func expandKeyPath(path string) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return strings.Replace(path, "~", homeDir, 1), nil
+}
+
+// generateKey runs ssh-keygen in the foreground (so it can ask for a
+// passphrase) and reports the new key's path
+func generateKey(path, keyType string) tea.Cmd {
+	argv, err := keygenCommand(path, keyType)
+	if err == nil {
+		// ssh-keygen doesn't create missing directories (e.g. a fresh ~/.ssh)
+		var expanded string
+		if expanded, err = expandKeyPath(path); err == nil {
+			err = os.MkdirAll(filepath.Dir(expanded), 0700)
+		}
+	}
+	if err != nil {
+		return func() tea.Msg {
+			return keyGeneratedMsg{err: err}
+		}
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return keyGeneratedMsg{err: fmt.Errorf("ssh-keygen failed: %w", err)}
+		}
+		return keyGeneratedMsg{key: path}
+	})
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKeygenCommand(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "id_ed25519_work")
+
+	if _, err := keygenCommand("", "ed25519"); err == nil {
+		t.Error("Expected an error for an empty path")
+	}
+
+	// Use a fake ssh-keygen so the test doesn't depend on OpenSSH
+	bin := t.TempDir()
+	fake := filepath.Join(bin, "ssh-keygen")
+	if err := os.WriteFile(fake, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to create fake ssh-keygen: %v", err)
+	}
+	t.Setenv("PATH", bin)
+
+	argv, err := keygenCommand(path, "ed25519")
+	if err != nil {
+		t.Fatalf("keygenCommand failed: %v", err)
+	}
+	if got, want := strings.Join(argv, " "), fake+" -t ed25519 -f "+path; got != want {
+		t.Errorf("argv: got %q, want %q", got, want)
+	}
+
+	argv, err = keygenCommand(path, "rsa")
+	if err != nil {
+		t.Fatalf("keygenCommand failed: %v", err)
+	}
+	if got := strings.Join(argv, " "); !strings.HasSuffix(got, "-t rsa -f "+path+" -b 4096") {
+		t.Errorf("rsa argv: got %q", got)
+	}
+
+	if _, err := keygenCommand(path, "dsa"); err == nil {
+		t.Error("Expected an error for an unsupported key type")
+	}
+
+	// Existing keys are never overwritten
+	if err := os.WriteFile(path+".pub", []byte("ssh-ed25519 AAAA"), 0644); err != nil {
+		t.Fatalf("Failed to create public key: %v", err)
+	}
+	if _, err := keygenCommand(path, "ed25519"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected an already exists error, got %v", err)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, err := keygenCommand(filepath.Join(dir, "id_other"), "ed25519"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected ssh-keygen not found, got %v", err)
+	}
+}
+
+func TestKeySelector_GenerateForm(t *testing.T) {
+	m := NewKeySelectorModel()
+	m.Open()
+	m.Update(keyLoadError{})
+
+	if len(m.keys) != 2 || m.keys[0] != generateKeyOption {
+		t.Fatalf("Without ~/.ssh/ the generate option should be offered, got %q", m.keys)
+	}
+
+	m.Update(keyMsgFor("enter"))
+	if !m.generating || m.nameInput.Value() != "~/.ssh/id_ed25519" {
+		t.Fatalf("Expected the generate form with a default name, got generating=%v name=%q", m.generating, m.nameInput.Value())
+	}
+
+	// Switching the type updates the untouched default name
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if keyTypes[m.keyType] != "rsa" || m.nameInput.Value() != "~/.ssh/id_rsa" {
+		t.Errorf("Expected rsa with a matching name, got %s %q", keyTypes[m.keyType], m.nameInput.Value())
+	}
+
+	m.Update(keyMsgFor("esc"))
+	if m.generating || !m.IsOpen() {
+		t.Errorf("Esc should go back to the key list")
+	}
+}
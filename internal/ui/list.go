@@ -1,14 +1,21 @@
 package ui
 
 import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/nicklasos/gosshit/internal/sshconfig"
+	"github.com/nicklasos/gosshit/internal/storage"
 )
 
-// formatTagBadge returns a styled badge for a tag
+// formatTagBadge returns a styled badge for a tag. prod, dev and stage have
+// fixed colors; any other tag gets a color derived from its name, so it looks
+// the same everywhere and across runs.
 func formatTagBadge(tag string) string {
 	tagLower := strings.ToLower(tag)
 	switch tagLower {
@@ -19,23 +26,49 @@ func formatTagBadge(tag string) string {
 	case "stage":
 		return tagStageStyle.Render("[" + tag + "]")
 	default:
-		return tagDefaultStyle.Render("[" + tag + "]")
+		return tagStyle(tagLower).Render("[" + tag + "]")
 	}
 }
 
+// tagStyle picks the style of a custom tag (lowercased) by hashing its name
+func tagStyle(tag string) lipgloss.Style {
+	h := fnv.New32a()
+	h.Write([]byte(tag))
+	return tagStyles[h.Sum32()%uint32(len(tagStyles))]
+}
+
+// ListLayout controls how hosts are rendered in the list panel
+type ListLayout int
+
+const (
+	LayoutCards   ListLayout = iota // Alias and hostname on separate lines
+	LayoutTable                     // One aligned row per host
+	LayoutCompact                   // One line per host: alias, hostname and tags
+)
+
 // ListModel represents the left panel list view
 type ListModel struct {
 	entries     []*sshconfig.HostEntry
 	filtered    []*sshconfig.HostEntry
 	selected    int
 	searchTerm  string
+	untagged    bool // The search contains #untagged: only entries without tags are shown
 	width       int
 	height      int
 	visitCounts map[string]int // host -> visit count
+	layout      ListLayout
+	tagFilter   []string            // Only entries carrying all of these tags are shown
+	marked      map[string]bool     // Hosts selected for a bulk action, by Host
+	pinned      []string            // Hosts pinned to the top, shown with a marker
+	resolved    map[string][]string // Lowercased HostName -> resolved addresses, matched by search
+	grouping    ListGrouping
+	collapsed   map[string]bool     // Groups whose hosts are hidden, by name
+	headers     map[int][]listGroup // Group headers shown before filtered[i]
 }
 
 // NewListModel creates a new list model
 func NewListModel(entries []*sshconfig.HostEntry, visitCounts map[string]int) *ListModel {
+	entries = groupPatterns(entries)
 	return &ListModel{
 		entries:     entries,
 		filtered:    entries,
@@ -69,19 +102,58 @@ func (m *ListModel) Update(msg tea.Msg) (*ListModel, tea.Cmd) {
 
 // SetEntries updates the entries list
 func (m *ListModel) SetEntries(entries []*sshconfig.HostEntry) {
-	m.entries = entries
+	m.entries = groupPatterns(entries)
 	m.ApplyFilter()
 }
 
+// groupPatterns moves pattern entries (Host *.internal, Host !prod-*, ...)
+// after the regular hosts, keeping the order within each group
+func groupPatterns(entries []*sshconfig.HostEntry) []*sshconfig.HostEntry {
+	grouped := make([]*sshconfig.HostEntry, 0, len(entries))
+	var patterns []*sshconfig.HostEntry
+	for _, entry := range entries {
+		if entry.IsPattern() {
+			patterns = append(patterns, entry)
+		} else {
+			grouped = append(grouped, entry)
+		}
+	}
+	return append(grouped, patterns...)
+}
+
+// SetMarked sets the hosts shown with a checkmark
+func (m *ListModel) SetMarked(marked map[string]bool) {
+	m.marked = marked
+}
+
+// SetPinned sets the hosts shown with a pin marker
+func (m *ListModel) SetPinned(pinned []string) {
+	m.pinned = pinned
+}
+
+// markPrefix returns the checkmark shown before a marked host's alias and
+// the marker of a pinned one
+func (m *ListModel) markPrefix(entry *sshconfig.HostEntry) string {
+	prefix := ""
+	if m.marked[entry.Host] {
+		prefix += "✓ "
+	}
+	if slices.Contains(m.pinned, entry.Host) {
+		prefix += "★ "
+	}
+	return prefix
+}
+
 // SetVisitCounts updates the visit counts
 func (m *ListModel) SetVisitCounts(counts map[string]int) {
 	m.visitCounts = counts
 }
 
-// ApplyFilter applies the current search filter
+// ApplyFilter applies the current search filter and tag filter, then the
+// grouping
 func (m *ListModel) ApplyFilter() {
-	if m.searchTerm == "" {
-		m.filtered = m.entries
+	if m.searchTerm == "" && len(m.tagFilter) == 0 && !m.untagged {
+		m.filtered, m.headers = groupEntries(m.entries, m.grouping, m.collapsed)
 		m.selected = 0
 		return
 	}
@@ -89,35 +161,201 @@ func (m *ListModel) ApplyFilter() {
 	var filtered []*sshconfig.HostEntry
 	term := strings.ToLower(m.searchTerm)
 	for _, entry := range m.entries {
-		// Check host, hostname, user, description
-		if strings.Contains(strings.ToLower(entry.Host), term) ||
-			strings.Contains(strings.ToLower(entry.HostName), term) ||
-			strings.Contains(strings.ToLower(entry.User), term) ||
-			strings.Contains(strings.ToLower(entry.Description), term) {
+		if (matchesSearch(entry, term) || m.matchesResolved(entry, term)) && hasAllTags(entry, m.tagFilter) && (!m.untagged || len(entry.Tags) == 0) {
 			filtered = append(filtered, entry)
-			continue
 		}
-		// Check tags
+	}
+
+	m.filtered, m.headers = groupEntries(filtered, m.grouping, m.collapsed)
+	if m.selected >= len(m.filtered) {
+		m.selected = max(0, len(m.filtered)-1)
+	}
+}
+
+// SetGrouping sets how the list is grouped, keeping the selected entry.
+// Every group starts out expanded.
+func (m *ListModel) SetGrouping(grouping ListGrouping) {
+	selected := m.GetSelected()
+	m.grouping = grouping
+	m.collapsed = nil
+	m.ApplyFilter()
+	m.reselect(selected)
+}
+
+// reselect selects entry again after the list was rebuilt, if it's still shown
+func (m *ListModel) reselect(entry *sshconfig.HostEntry) {
+	for i, e := range m.filtered {
+		if e == entry {
+			m.selected = i
+			break
+		}
+	}
+}
+
+// ToggleGroup collapses the group the selected host is listed under, so only
+// its header is shown and j/k skip it. Right below a collapsed header (where
+// collapsing leaves the selection) it expands that group again instead. It
+// returns the group's name ("" when the list isn't grouped) and whether it is
+// now collapsed.
+func (m *ListModel) ToggleGroup() (string, bool) {
+	if m.grouping == GroupNone {
+		return "", false
+	}
+	index := m.selected
+
+	groups := m.headers[index]
+	for i := len(groups) - 1; i >= 0; i-- {
+		if groups[i].collapsed {
+			// Its hosts come back at the selected index, so the selection
+			// moves to the first of them
+			delete(m.collapsed, groups[i].name)
+			m.ApplyFilter()
+			m.SetSelected(index)
+			return groups[i].name, false
+		}
+	}
+
+	for i := index; i >= 0; i-- {
+		if groups := m.headers[i]; len(groups) > 0 {
+			// The last header before an entry is the expanded group it's in
+			name := groups[len(groups)-1].name
+			if m.collapsed == nil {
+				m.collapsed = make(map[string]bool)
+			}
+			m.collapsed[name] = true
+			m.ApplyFilter()
+			m.SetSelected(i)
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// ExpandGroups expands every collapsed group, keeping the selected entry. It
+// reports whether any group was collapsed.
+func (m *ListModel) ExpandGroups() bool {
+	if len(m.collapsed) == 0 {
+		return false
+	}
+	selected := m.GetSelected()
+	m.collapsed = nil
+	m.ApplyFilter()
+	m.reselect(selected)
+	return true
+}
+
+// Grouping returns how the list is grouped
+func (m *ListModel) Grouping() ListGrouping {
+	return m.grouping
+}
+
+// groupHeader renders the headers shown before filtered[i], one per line, or
+// "" if no group starts there. Collapsed groups show how many hosts they hide.
+func (m *ListModel) groupHeader(i int) string {
+	var lines []string
+	for _, group := range m.headers[i] {
+		if group.collapsed {
+			lines = append(lines, groupHeaderStyle.Render(fmt.Sprintf("▸─ %s (%d hidden)", group.name, group.count)))
+		} else {
+			lines = append(lines, groupHeaderStyle.Render("── "+group.name))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// matchesSearch reports whether entry matches the lowercased search term
+func matchesSearch(entry *sshconfig.HostEntry, term string) bool {
+	if term == "" {
+		return true
+	}
+	// Check host, hostname, connection string (user@hostname), user, description
+	if strings.Contains(strings.ToLower(entry.Host), term) ||
+		strings.Contains(strings.ToLower(entry.HostName), term) ||
+		strings.Contains(strings.ToLower(entry.GetConnectionString()), term) ||
+		strings.Contains(strings.ToLower(entry.User), term) ||
+		strings.Contains(strings.ToLower(entry.Description), term) {
+		return true
+	}
+	// Check tags
+	for _, tag := range entry.Tags {
+		if strings.Contains(strings.ToLower(tag), term) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAllTags reports whether entry carries every tag (case-insensitive)
+func hasAllTags(entry *sshconfig.HostEntry, tags []string) bool {
+	for _, want := range tags {
+		found := false
 		for _, tag := range entry.Tags {
-			if strings.Contains(strings.ToLower(tag), term) {
-				filtered = append(filtered, entry)
+			if strings.EqualFold(tag, want) {
+				found = true
 				break
 			}
 		}
+		if !found {
+			return false
+		}
 	}
+	return true
+}
 
-	m.filtered = filtered
-	if m.selected >= len(m.filtered) {
-		m.selected = max(0, len(m.filtered)-1)
+// SetTagFilter limits the list to entries carrying all of tags (nil clears it)
+func (m *ListModel) SetTagFilter(tags []string) {
+	m.tagFilter = tags
+	m.ApplyFilter()
+}
+
+// TagFilter returns the active tag filter
+func (m *ListModel) TagFilter() []string {
+	return m.tagFilter
+}
+
+// listTitle returns the panel title, including the active tag filter
+func (m *ListModel) listTitle() string {
+	title := "SSH Hosts"
+	if len(m.tagFilter) > 0 {
+		title += " [" + strings.Join(m.tagFilter, " + ") + "]"
 	}
+	if m.untagged {
+		title += " [untagged]"
+	}
+	if m.grouping != GroupNone {
+		title += " by " + m.grouping.String()
+	}
+	return title
 }
 
-// SetSearchTerm sets the search term and applies the filter
+// untaggedToken is the search token that limits the list to entries without tags
+const untaggedToken = "#untagged"
+
+// SetSearchTerm sets the search term and applies the filter. A #untagged
+// token in term shows only entries without tags; the rest is searched as usual.
 func (m *ListModel) SetSearchTerm(term string) {
-	m.searchTerm = term
+	m.searchTerm, m.untagged = parseSearchTerm(term)
 	m.ApplyFilter()
 }
 
+// parseSearchTerm splits the #untagged token off term
+func parseSearchTerm(term string) (string, bool) {
+	fields := strings.Fields(term)
+	rest := fields[:0]
+	untagged := false
+	for _, field := range fields {
+		if strings.EqualFold(field, untaggedToken) {
+			untagged = true
+			continue
+		}
+		rest = append(rest, field)
+	}
+	if !untagged {
+		return term, false
+	}
+	return strings.Join(rest, " "), true
+}
+
 // GetSelected returns the currently selected entry
 func (m *ListModel) GetSelected() *sshconfig.HostEntry {
 	if len(m.filtered) == 0 || m.selected < 0 || m.selected >= len(m.filtered) {
@@ -142,6 +380,16 @@ func (m *ListModel) GetSelectedIndex() int {
 	return m.selected
 }
 
+// SetLayout sets how hosts are rendered
+func (m *ListModel) SetLayout(layout ListLayout) {
+	m.layout = layout
+}
+
+// Layout returns how hosts are rendered
+func (m *ListModel) Layout() ListLayout {
+	return m.layout
+}
+
 // SetSize sets the size of the list view
 func (m *ListModel) SetSize(width, height int) {
 	m.width = width
@@ -151,14 +399,21 @@ func (m *ListModel) SetSize(width, height int) {
 // View renders the list view
 func (m *ListModel) View() string {
 	if len(m.filtered) == 0 {
+		body := "No hosts found"
+		if header := m.groupHeader(0); header != "" {
+			body = header // Every group is collapsed
+		}
 		return listPanelStyle.Width(m.width).Height(m.height).Render(
-			titleStyle.Render("SSH Hosts") + "\n\n" +
-				"No hosts found",
+			titleStyle.Render(m.listTitle()) + "\n\n" + body,
 		)
 	}
 
+	if m.layout == LayoutTable {
+		return m.viewTable()
+	}
+
 	var lines []string
-	lines = append(lines, titleStyle.Render("SSH Hosts"))
+	lines = append(lines, titleStyle.Render(m.listTitle()))
 
 	// Account for panel padding (1 top + 1 bottom) and title (1 line + margin)
 	// Each entry can be 2-3 lines (2 lines normally, 3 when tags wrap)
@@ -166,7 +421,11 @@ func (m *ListModel) View() string {
 	titleHeight := 2                    // title + margin
 	availableForEntries := availableHeight - titleHeight
 	// Use a conservative estimate: assume 2.5 lines per entry on average
-	visibleEntries := max(1, availableForEntries/3)
+	linesPerEntry := 3
+	if m.layout == LayoutCompact {
+		linesPerEntry = 1
+	}
+	visibleEntries := max(1, availableForEntries/linesPerEntry)
 
 	start := max(0, m.selected-visibleEntries/2)
 	end := min(len(m.filtered), start+visibleEntries*2) // Allow more entries to account for variable heights
@@ -176,7 +435,15 @@ func (m *ListModel) View() string {
 
 	for i := start; i < end && entryLinesCount < availableForEntries; i++ {
 		entry := m.filtered[i]
-		entryLines := m.formatEntry(entry, i == m.selected)
+		var entryLines string
+		if m.layout == LayoutCompact {
+			entryLines = m.formatCompactEntry(entry, i == m.selected)
+		} else {
+			entryLines = m.formatEntry(entry, i == m.selected)
+		}
+		if header := m.groupHeader(i); header != "" {
+			entryLines = header + "\n" + entryLines
+		}
 		splitLines := strings.Split(entryLines, "\n")
 		if entryLinesCount+len(splitLines) > availableForEntries {
 			break
@@ -187,6 +454,15 @@ func (m *ListModel) View() string {
 		}
 		actualEnd = i + 1
 	}
+	// Collapsed groups after the last entry
+	if header := m.groupHeader(len(m.filtered)); header != "" && actualEnd == len(m.filtered) {
+		for _, line := range strings.Split(header, "\n") {
+			if entryLinesCount < availableForEntries {
+				lines = append(lines, line)
+				entryLinesCount++
+			}
+		}
+	}
 
 	// Show scroll indicators
 	hasMoreAbove := start > 0
@@ -213,25 +489,42 @@ func (m *ListModel) formatEntry(entry *sshconfig.HostEntry, selected bool) strin
 	// Format: Host name (main line)
 	//         IP/hostname (smaller text below)
 
-	hostname := entry.HostName
+	// Search matches are highlighted in the colors of the line they're on
+	mainColor, subColor := fgColor, subtleColor
+	if selected {
+		mainColor, subColor = accentColor, accentColor
+	}
+
+	// An IPv6 HostName is bracketed when a port follows it
+	hostText := entry.HostName
+	if !entry.IsDefaultPort() {
+		hostText = sshconfig.BracketIPv6(hostText)
+	}
+	hostname := highlightMatch(hostText, m.searchTerm, lipgloss.NewStyle().Foreground(subColor))
 	if hostname == "" {
 		hostname = entry.Host
+		if entry.IsPattern() {
+			hostname = "applies to matching hosts"
+		}
 	}
 
-	// Add port if present
-	if entry.Port != "" && entry.Port != "22" {
+	// Add port only when it isn't the default
+	if !entry.IsDefaultPort() {
 		hostname += ":" + entry.Port
 	}
 
 	// Main line: Host alias with tags
-	hostAlias := entry.Host
+	hostAlias := m.markPrefix(entry) + displayAlias(entry)
 	// Add tag badges
 	var tagBadges []string
-	for _, tag := range entry.Tags {
+	for _, tag := range sshconfig.UniqueTags(entry.Tags) {
 		tagBadges = append(tagBadges, formatTagBadge(tag))
 	}
 
-	mainLine := hostAlias
+	var badges string
+	if entry.IsPattern() {
+		badges += " " + patternBadgeStyle.Render("[pattern]")
+	}
 	var tagLine string
 	if len(tagBadges) > 0 {
 		if len(tagBadges) > 2 {
@@ -239,9 +532,14 @@ func (m *ListModel) formatEntry(entry *sshconfig.HostEntry, selected bool) strin
 			tagLine = "  " + strings.Join(tagBadges, " ")
 		} else {
 			// 2 or fewer tags: all on main line
-			mainLine += " " + strings.Join(tagBadges, " ")
+			badges += " " + strings.Join(tagBadges, " ")
 		}
 	}
+	aliasStyle := lipgloss.NewStyle().Foreground(mainColor)
+	mainLine := highlightMatch(hostAlias, m.searchTerm, aliasStyle) + badges
+	if count := m.visitCounts[entry.Host]; count > 0 {
+		mainLine = m.withVisitCount(hostAlias, badges, count, aliasStyle)
+	}
 	if selected {
 		mainLine = "▶ " + mainLine
 	} else {
@@ -281,6 +579,217 @@ func (m *ListModel) formatEntry(entry *sshconfig.HostEntry, selected bool) strin
 	return lipgloss.JoinVertical(lipgloss.Left, linesToJoin...)
 }
 
+// formatCompactEntry formats an entry on a single line: alias, hostname and
+// tags, truncated to the panel width (tags are dropped first, then the hostname)
+func (m *ListModel) formatCompactEntry(entry *sshconfig.HostEntry, selected bool) string {
+	mainColor, subColor := fgColor, subtleColor
+	if selected {
+		mainColor, subColor = accentColor, accentColor
+	}
+
+	hostText := entry.HostName
+	if hostText == "" && entry.IsPattern() {
+		hostText = "applies to matching hosts"
+	}
+	if hostText != "" && !entry.IsDefaultPort() {
+		hostText = sshconfig.BracketIPv6(hostText) + ":" + entry.Port
+	}
+
+	// Panel padding (2 per side), item indent (2) and the "▶ " marker (2)
+	room := m.width - 4 - 2 - 2
+	if m.width <= 0 {
+		room = math.MaxInt // Not sized yet
+	}
+	alias := truncate(m.markPrefix(entry)+displayAlias(entry), max(1, room))
+	room -= lipgloss.Width(alias)
+
+	line := highlightMatch(alias, m.searchTerm, lipgloss.NewStyle().Foreground(mainColor))
+	if hostText != "" && room > 2 {
+		hostText = truncate(hostText, room-2)
+		room -= 2 + lipgloss.Width(hostText)
+		line += "  " + highlightMatch(hostText, m.searchTerm, lipgloss.NewStyle().Foreground(subColor))
+	}
+	var tagBadges []string
+	for _, tag := range sshconfig.UniqueTags(entry.Tags) {
+		tagBadges = append(tagBadges, formatTagBadge(tag))
+	}
+	if tags := strings.Join(tagBadges, " "); tags != "" && lipgloss.Width(tags)+2 <= room {
+		line += "  " + tags
+	}
+
+	if selected {
+		return listItemSelectedStyle.Render("▶ " + line)
+	}
+	return listItemStyle.Render("  " + line)
+}
+
+// withVisitCount right-aligns the visit count on an entry's main line,
+// shortening the alias if the line would otherwise overflow the panel. Search
+// matches in the (shortened) alias are highlighted on aliasStyle.
+func (m *ListModel) withVisitCount(alias, badges string, count int, aliasStyle lipgloss.Style) string {
+	countText := storage.FormatCount(count)
+	// Panel padding (2 per side), item indent (2) and the "▶ " marker (2)
+	width := m.width - 4 - 2 - 2
+	if width <= 0 {
+		return highlightMatch(alias, m.searchTerm, aliasStyle) + badges + " " + countText
+	}
+
+	room := width - lipgloss.Width(countText) - 1
+	if lipgloss.Width(alias+badges) > room {
+		alias = truncate(alias, max(1, room-lipgloss.Width(badges)))
+	}
+	line := highlightMatch(alias, m.searchTerm, aliasStyle) + badges
+	gap := max(1, width-lipgloss.Width(line)-lipgloss.Width(countText))
+	return line + strings.Repeat(" ", gap) + countText
+}
+
+// displayAlias returns the entry's primary alias, with any extra aliases of
+// the Host line summarized as "(+N)"
+func displayAlias(entry *sshconfig.HostEntry) string {
+	if entry.IsPattern() {
+		// Every pattern matters (e.g. "*.internal !bastion.internal")
+		return entry.Host
+	}
+	alias := entry.PrimaryAlias()
+	if extra := len(entry.Aliases()) - 1; extra > 0 {
+		alias += fmt.Sprintf(" (+%d)", extra)
+	}
+	return alias
+}
+
+// tableColumns are the column headers of the table layout
+var tableColumns = []string{"ALIAS", "HOST", "USER", "PORT", "TAGS"}
+
+// tableRow returns the plain cell values of an entry for the table layout
+func tableRow(entry *sshconfig.HostEntry) []string {
+	port := entry.Port
+	if port == "" {
+		port = sshconfig.DefaultPort
+	}
+	hostname := entry.HostName
+	if hostname == "" && entry.IsPattern() {
+		hostname = "(pattern)"
+	}
+	return []string{
+		displayAlias(entry),
+		hostname,
+		entry.User,
+		port,
+		strings.Join(sshconfig.UniqueTags(entry.Tags), ","),
+	}
+}
+
+// viewTable renders the list as aligned columns, one row per host
+func (m *ListModel) viewTable() string {
+	var lines []string
+	lines = append(lines, titleStyle.Render(m.listTitle()))
+
+	// Panel padding (2 top/bottom), title with margin (2) and the header row (1)
+	availableHeight := m.height - 2 - 2
+	visibleRows := max(1, availableHeight-2-1)
+
+	rows := make([][]string, len(m.filtered))
+	for i, entry := range m.filtered {
+		rows[i] = tableRow(entry)
+		rows[i][0] = m.markPrefix(entry) + rows[i][0]
+	}
+	// Rows are prefixed like list items (2 chars); panel padding is 2 per side
+	widths := tableColumnWidths(rows, m.width-4-2)
+
+	lines = append(lines, listItemStyle.Copy().Foreground(subtleColor).Render(formatTableCells(tableColumns, widths)))
+
+	// Group headers take a line each, so scroll over rendered lines
+	var body []string
+	selectedLine := 0
+	for i := range m.filtered {
+		if header := m.groupHeader(i); header != "" {
+			body = append(body, strings.Split(header, "\n")...)
+		}
+		row := formatTableCells(rows[i], widths)
+		if i == m.selected {
+			selectedLine = len(body)
+			body = append(body, listItemSelectedStyle.Render(row))
+		} else {
+			body = append(body, listItemStyle.Render(row))
+		}
+	}
+	if header := m.groupHeader(len(m.filtered)); header != "" {
+		body = append(body, strings.Split(header, "\n")...)
+	}
+	start := max(0, min(selectedLine-visibleRows/2, len(body)-visibleRows))
+	end := min(len(body), start+visibleRows)
+	lines = append(lines, body[start:end]...)
+
+	// Fill remaining space to ensure consistent height and proper border rendering
+	for len(lines) < availableHeight {
+		lines = append(lines, "")
+	}
+
+	content := strings.Join(lines, "\n")
+	return listPanelStyle.Width(m.width).Height(m.height).Render(content)
+}
+
+// tableColumnGap separates table columns
+const tableColumnGap = "  "
+
+// tableColumnWidths sizes each column to its widest cell (including the
+// header), then shrinks the widest columns until the row fits in width
+func tableColumnWidths(rows [][]string, width int) []int {
+	widths := make([]int, len(tableColumns))
+	for i, header := range tableColumns {
+		widths[i] = lipgloss.Width(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+
+	available := width - len(tableColumnGap)*(len(widths)-1)
+	for {
+		total, widest := 0, 0
+		for i, w := range widths {
+			total += w
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if total <= available || widths[widest] <= 1 {
+			return widths
+		}
+		widths[widest]--
+	}
+}
+
+// formatTableCells pads or truncates each cell to its column width
+func formatTableCells(cells []string, widths []int) string {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = truncate(cell, widths[i])
+		padded[i] = cell + strings.Repeat(" ", widths[i]-lipgloss.Width(cell))
+	}
+	return strings.TrimRight(strings.Join(padded, tableColumnGap), " ")
+}
+
+// truncate shortens s to at most width cells, marking the cut with "…"
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		if lipgloss.Width(b.String()+string(r)) > width-1 {
+			break
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + "…"
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
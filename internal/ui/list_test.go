@@ -0,0 +1,446 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+func TestListModel_TableLayoutFitsWidth(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "web", HostName: "web.example.com", User: "deploy", Tags: []string{"prod", "web"}},
+		{Host: "a-very-long-alias-for-the-database-primary", HostName: "db-primary.internal.example.com", User: "postgres", Port: "5432"},
+	}
+
+	m := NewListModel(entries, map[string]int{})
+	m.SetLayout(LayoutTable)
+	m.SetSize(50, 12)
+
+	view := m.View()
+	for _, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > 52 {
+			t.Errorf("Line wider than the panel (%d): %q", w, line)
+		}
+	}
+	for _, want := range []string{"ALIAS", "PORT", "a-very-", "5432", "…"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Table should contain %q, got:\n%s", want, view)
+		}
+	}
+}
+
+func TestListModel_CompactLayout(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "web", HostName: "web.example.com", Tags: []string{"prod"}},
+		{Host: "a-very-long-alias-for-the-database-primary", HostName: "db-primary.internal.example.com", Port: "5432"},
+	}
+
+	m := NewListModel(entries, map[string]int{})
+	m.SetLayout(LayoutCompact)
+	m.SetSize(40, 12)
+
+	if line := m.formatCompactEntry(entries[0], false); strings.Contains(line, "\n") || !strings.Contains(line, "web  web.example.com  [prod]") {
+		t.Errorf("Expected alias, hostname and tags on one line, got %q", line)
+	}
+	selected := m.formatCompactEntry(entries[0], true)
+	if !strings.Contains(selected, "│") || !strings.Contains(selected, "▶ web") {
+		t.Errorf("Selected entry should keep the left border and marker, got %q", selected)
+	}
+
+	view := m.View()
+	for _, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > 42 {
+			t.Errorf("Line wider than the panel (%d): %q", w, line)
+		}
+	}
+	if !strings.Contains(view, "a-very-") || !strings.Contains(view, "…") {
+		t.Errorf("Long aliases should be truncated, got:\n%s", view)
+	}
+}
+
+func TestListModel_VisitCounts(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "a-very-long-alias-for-the-database-primary", HostName: "db.example.com"},
+		{Host: "web", HostName: "web.example.com"},
+		{Host: "never", HostName: "never.example.com"},
+	}
+
+	m := NewListModel(entries, map[string]int{"a-very-long-alias-for-the-database-primary": 42, "web": 7})
+	m.SetSize(30, 20)
+
+	view := m.View()
+	for _, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > 32 {
+			t.Errorf("Line wider than the panel (%d): %q", w, line)
+		}
+	}
+
+	lines := strings.Split(m.formatEntry(entries[1], false), "\n")
+	if main := strings.TrimRight(lines[0], " "); !strings.HasSuffix(main, "7") || lipgloss.Width(main) != 30-4 {
+		t.Errorf("Visit count should be right-aligned, got %q", main)
+	}
+	if main := m.formatEntry(entries[0], true); !strings.Contains(main, "…") || !strings.Contains(main, "42") {
+		t.Errorf("Long alias should be shortened to keep the count, got %q", main)
+	}
+	if main := strings.Split(m.formatEntry(entries[2], false), "\n")[0]; strings.TrimSpace(main) != "never" {
+		t.Errorf("No count should be shown for unvisited hosts, got %q", main)
+	}
+
+	// Large counts are abbreviated like in the details panel
+	m.visitCounts["web"] = 12345
+	if main := strings.TrimRight(strings.Split(m.formatEntry(entries[1], false), "\n")[0], " "); !strings.HasSuffix(main, " 12.3k") {
+		t.Errorf("Large visit count should be abbreviated, got %q", main)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"example", 10, "example"},
+		{"example", 7, "example"},
+		{"example", 5, "exam…"},
+		{"example", 1, "…"},
+		{"example", 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.width); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestListModel_TagFilter(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "web-prod", Tags: []string{"prod", "web"}},
+		{Host: "db-prod", Tags: []string{"Prod", "db"}},
+		{Host: "web-dev", Tags: []string{"dev", "web"}},
+	}
+
+	m := NewListModel(entries, map[string]int{})
+
+	hosts := func() []string {
+		var names []string
+		for _, e := range m.filtered {
+			names = append(names, e.Host)
+		}
+		return names
+	}
+
+	m.SetTagFilter([]string{"prod"})
+	if got := hosts(); len(got) != 2 || got[0] != "web-prod" || got[1] != "db-prod" {
+		t.Errorf("prod: got %v", got)
+	}
+
+	// Multiple tags AND together
+	m.SetTagFilter([]string{"prod", "web"})
+	if got := hosts(); len(got) != 1 || got[0] != "web-prod" {
+		t.Errorf("prod+web: got %v", got)
+	}
+
+	// Text search applies on top of the tag filter
+	m.SetTagFilter([]string{"web"})
+	m.SetSearchTerm("dev")
+	if got := hosts(); len(got) != 1 || got[0] != "web-dev" {
+		t.Errorf("web + search dev: got %v", got)
+	}
+
+	m.SetSearchTerm("")
+	m.SetTagFilter(nil)
+	if got := hosts(); len(got) != 3 {
+		t.Errorf("No filter: got %v", got)
+	}
+}
+
+func TestListModel_GroupsPatternsLast(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "*.internal", User: "ops"},
+		{Host: "web", HostName: "web.example.com"},
+		{Host: "!prod-* staging-*"},
+		{Host: "db", HostName: "db.example.com"},
+	}
+	m := NewListModel(entries, map[string]int{})
+
+	var got []string
+	for _, entry := range m.filtered {
+		got = append(got, entry.Host)
+	}
+	want := []string{"web", "db", "*.internal", "!prod-* staging-*"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Order: got %q, want %q", got, want)
+	}
+
+	m.SetSize(60, 30)
+	view := m.View()
+	if !strings.Contains(view, "[pattern]") || !strings.Contains(view, "!prod-* staging-*") {
+		t.Errorf("Pattern entries should be badged and show the full Host line:\n%s", view)
+	}
+}
+
+func TestGroupEntries(t *testing.T) {
+	web := &sshconfig.HostEntry{Host: "web", Tags: []string{"prod", "frontend"}}
+	db := &sshconfig.HostEntry{Host: "db", Tags: []string{"prod"}}
+	box := &sshconfig.HostEntry{Host: "box"}
+	dev := &sshconfig.HostEntry{Host: "dev", Tags: []string{"dev"}}
+	entries := []*sshconfig.HostEntry{web, db, box, dev}
+
+	if grouped, headers := groupEntries(entries, GroupNone, nil); len(grouped) != 4 || headers != nil {
+		t.Errorf("GroupNone should leave entries alone, got %d entries and %v", len(grouped), headers)
+	}
+
+	grouped, headers := groupEntries(entries, GroupByTag, nil)
+	want := []*sshconfig.HostEntry{dev, web, web, db, box}
+	if len(grouped) != len(want) {
+		t.Fatalf("GroupByTag: got %d entries, want %d", len(grouped), len(want))
+	}
+	for i := range want {
+		if grouped[i] != want[i] {
+			t.Errorf("GroupByTag[%d]: got %s, want %s", i, grouped[i].Host, want[i].Host)
+		}
+	}
+	wantHeaders := map[int]string{0: "dev", 1: "frontend", 2: "prod", 4: untaggedGroup}
+	for i, name := range wantHeaders {
+		if len(headers[i]) != 1 || headers[i][0].name != name {
+			t.Errorf("GroupByTag header at %d: got %v, want %q", i, headers[i], name)
+		}
+	}
+
+	grouped, headers = groupEntries(entries, GroupByFirstTag, nil)
+	if len(grouped) != 4 || grouped[1] != web || headers[1][0].name != "prod" || headers[3][0].name != untaggedGroup {
+		t.Errorf("GroupByFirstTag: unexpected grouping %v", headers)
+	}
+
+	// A collapsed group keeps its header before the next group's, without its hosts
+	grouped, headers = groupEntries(entries, GroupByFirstTag, map[string]bool{"prod": true})
+	if len(grouped) != 2 || grouped[1] != box {
+		t.Fatalf("Collapsed prod: got %d entries", len(grouped))
+	}
+	if groups := headers[1]; len(groups) != 2 || groups[0] != (listGroup{name: "prod", count: 2, collapsed: true}) || groups[1].name != untaggedGroup {
+		t.Errorf("Collapsed prod: unexpected headers %v", groups)
+	}
+}
+
+func TestListModel_CollapsedGroup(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "dev", HostName: "dev.example.com", Tags: []string{"dev"}},
+		{Host: "web", HostName: "web.example.com", Tags: []string{"prod"}},
+		{Host: "db", HostName: "db.example.com", Tags: []string{"prod"}},
+		{Host: "box", HostName: "box.example.com"},
+	}
+	m := NewListModel(entries, map[string]int{})
+	m.SetSize(60, 40)
+	m.SetGrouping(GroupByTag)
+	m.SetSelected(2) // db
+
+	if name, collapsed := m.ToggleGroup(); name != "prod" || !collapsed {
+		t.Fatalf("Expected prod to collapse, got %q (%v)", name, collapsed)
+	}
+	if got := m.GetSelected(); got == nil || got.Host != "box" {
+		t.Errorf("Selection should move past the collapsed group, got %+v", got)
+	}
+	view := m.View()
+	if !strings.Contains(view, "prod (2 hidden)") || strings.Contains(view, "web.example.com") {
+		t.Errorf("Collapsed group should only show its header:\n%s", view)
+	}
+
+	// j/k skip over the collapsed group
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	if got := m.GetSelected(); got == nil || got.Host != "dev" {
+		t.Errorf("Expected dev after k, got %+v", got)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if got := m.GetSelected(); got == nil || got.Host != "box" {
+		t.Errorf("Expected box after j, got %+v", got)
+	}
+
+	// Right below the collapsed header, toggling expands it again
+	if name, collapsed := m.ToggleGroup(); name != "prod" || collapsed {
+		t.Fatalf("Expected prod to expand, got %q (%v)", name, collapsed)
+	}
+	if got := m.GetSelected(); got == nil || got.Host != "web" {
+		t.Errorf("Selection should land on the first host of the expanded group, got %+v", got)
+	}
+
+	// Collapsing the last group leaves its header at the end of the list
+	m.SetSelected(3)
+	m.ToggleGroup()
+	for _, layout := range []ListLayout{LayoutCards, LayoutTable} {
+		m.SetLayout(layout)
+		if view := m.View(); !strings.Contains(view, untaggedGroup+" (1 hidden)") {
+			t.Errorf("Trailing collapsed group should be shown:\n%s", view)
+		}
+	}
+	if !m.ExpandGroups() || len(m.filtered) != 4 {
+		t.Errorf("ExpandGroups should show every host again, got %d", len(m.filtered))
+	}
+	if m.ExpandGroups() {
+		t.Error("Nothing left to expand")
+	}
+}
+
+func TestListModel_GroupingKeepsSelection(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "web", HostName: "web.example.com", Tags: []string{"prod"}},
+		{Host: "box", HostName: "box.example.com"},
+		{Host: "dev", HostName: "dev.example.com", Tags: []string{"dev"}},
+	}
+	m := NewListModel(entries, map[string]int{})
+	m.SetSize(40, 30)
+	m.SetSelected(1)
+
+	m.SetGrouping(GroupByTag)
+	if got := m.GetSelected(); got == nil || got.Host != "box" {
+		t.Errorf("Selection should stay on box, got %+v", got)
+	}
+	view := m.View()
+	for _, want := range []string{"── dev", "── prod", "── " + untaggedGroup, "by tag"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the grouped view:\n%s", want, view)
+		}
+	}
+
+	// Headers aren't selectable: moving down from the first entry lands on the next host
+	m.SetSelected(0)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if got := m.GetSelected(); got == nil || got.Host != "web" {
+		t.Errorf("Expected web after j, got %+v", got)
+	}
+
+	m.SetLayout(LayoutTable)
+	if view := m.View(); !strings.Contains(view, "── prod") {
+		t.Errorf("Table layout should show group headers:\n%s", view)
+	}
+}
+
+func TestMatchRanges(t *testing.T) {
+	tests := []struct {
+		text, term string
+		want       [][2]int
+	}{
+		{"web.example.com", "", nil},
+		{"web.example.com", "xyz", nil},
+		{"web.example.com", "EX", [][2]int{{4, 6}}},
+		{"aaaa", "aa", [][2]int{{0, 2}, {2, 4}}},
+		{"héllo-hÉ", "hé", [][2]int{{0, 2}, {6, 8}}},
+	}
+	for _, tt := range tests {
+		got := matchRanges(tt.text, tt.term)
+		if len(got) != len(tt.want) {
+			t.Errorf("matchRanges(%q, %q) = %v, want %v", tt.text, tt.term, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("matchRanges(%q, %q) = %v, want %v", tt.text, tt.term, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestListModel_SearchHighlightKeepsLayout(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "a-very-long-alias-for-the-web-frontend", HostName: "web.example.com", Port: "2222", Tags: []string{"prod"}},
+	}
+	m := NewListModel(entries, map[string]int{"a-very-long-alias-for-the-web-frontend": 3})
+	m.SetSize(30, 20)
+
+	plain := m.formatEntry(entries[0], true)
+	m.SetSearchTerm("WEB")
+	highlighted := m.formatEntry(entries[0], true)
+
+	plainLines := strings.Split(plain, "\n")
+	highlightedLines := strings.Split(highlighted, "\n")
+	if len(plainLines) != len(highlightedLines) {
+		t.Fatalf("Highlighting changed the number of lines:\n%s\nvs\n%s", plain, highlighted)
+	}
+	for i := range plainLines {
+		if lipgloss.Width(plainLines[i]) != lipgloss.Width(highlightedLines[i]) {
+			t.Errorf("Line %d changed width: %q vs %q", i, plainLines[i], highlightedLines[i])
+		}
+	}
+	if !strings.Contains(highlighted, "web.example.com:2222") {
+		t.Errorf("Hostname and port should be kept, got:\n%s", highlighted)
+	}
+}
+
+func TestListModel_BracketsIPv6WithPort(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "v6", HostName: "2001:db8::1", Port: "2222"},
+		{Host: "v6-default", HostName: "2001:db8::2"},
+	}
+	m := NewListModel(entries, nil)
+	m.SetSize(60, 20)
+
+	if got := m.formatEntry(entries[0], false); !strings.Contains(got, "[2001:db8::1]:2222") {
+		t.Errorf("Expected a bracketed address, got %q", got)
+	}
+	if got := m.formatEntry(entries[1], false); strings.Contains(got, "[") || !strings.Contains(got, "2001:db8::2") {
+		t.Errorf("Expected a bare address without a port, got %q", got)
+	}
+}
+
+func TestListModel_UntaggedSearch(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "web-prod", Tags: []string{"prod"}},
+		{Host: "web-old"},
+		{Host: "db-old"},
+	}
+
+	m := NewListModel(entries, map[string]int{})
+	hosts := func() []string {
+		var names []string
+		for _, e := range m.filtered {
+			names = append(names, e.Host)
+		}
+		return names
+	}
+
+	m.SetSearchTerm("#untagged")
+	if got := hosts(); len(got) != 2 || got[0] != "web-old" || got[1] != "db-old" {
+		t.Errorf("#untagged: got %v", got)
+	}
+	if !strings.Contains(m.listTitle(), "[untagged]") {
+		t.Errorf("Title should show the untagged filter, got %q", m.listTitle())
+	}
+
+	// Composes with the text search, in any position and case
+	m.SetSearchTerm("web #Untagged")
+	if got := hosts(); len(got) != 1 || got[0] != "web-old" {
+		t.Errorf("web #untagged: got %v", got)
+	}
+	if m.searchTerm != "web" {
+		t.Errorf("The token should not be searched (or highlighted), got term %q", m.searchTerm)
+	}
+
+	m.SetSearchTerm("web")
+	if got := hosts(); len(got) != 2 {
+		t.Errorf("Plain search: got %v", got)
+	}
+}
+
+func TestTagStyle_StablePerName(t *testing.T) {
+	color := func(tag string) lipgloss.TerminalColor {
+		return tagStyle(strings.ToLower(tag)).GetForeground()
+	}
+
+	if color("db") != color("DB") || color("db") != tagStyle("db").GetForeground() {
+		t.Errorf("A tag should always get the same color")
+	}
+	distinct := map[lipgloss.TerminalColor]bool{}
+	for _, tag := range []string{"db", "cache", "eu-west"} {
+		distinct[color(tag)] = true
+	}
+	if len(distinct) < 2 {
+		t.Errorf("Different tags should not all share one color")
+	}
+	if got := formatTagBadge("prod"); got != tagProdStyle.Render("[prod]") {
+		t.Errorf("prod should keep its fixed style, got %q", got)
+	}
+}
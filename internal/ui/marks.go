@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+// toggleMark adds or removes the selected host from the bulk selection
+func (m *Model) toggleMark() {
+	entry := m.listModel.GetSelected()
+	if entry == nil {
+		return
+	}
+	if m.marked[entry.Host] {
+		delete(m.marked, entry.Host)
+	} else {
+		m.marked[entry.Host] = true
+	}
+	if len(m.marked) == 0 {
+		m.statusMsg = "Selection cleared"
+		return
+	}
+	m.statusMsg = fmt.Sprintf("%d selected (d: delete, Esc: clear)", len(m.marked))
+}
+
+// markedHosts returns the marked hosts in a stable order
+func (m *Model) markedHosts() []string {
+	hosts := make([]string, 0, len(m.marked))
+	for host := range m.marked {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// pruneMarks drops marks for hosts that are no longer in the config
+func (m *Model) pruneMarks() {
+	present := make(map[string]bool, len(m.entries))
+	for _, e := range m.entries {
+		present[e.Host] = true
+	}
+	for host := range m.marked {
+		if !present[host] {
+			delete(m.marked, host)
+		}
+	}
+}
+
+// confirmBulkDelete deletes every marked host, writing each file once
+func (m *Model) confirmBulkDelete() (tea.Model, tea.Cmd) {
+	hosts := m.markedHosts()
+
+	// One snapshot per file the hosts live in, so undo restores all of them
+	var snapshots []*sshconfig.Snapshot
+	seen := make(map[string]bool)
+	for _, e := range m.entries {
+		if !m.marked[e.Host] {
+			continue
+		}
+		file := m.entryFile(e)
+		if !seen[file] {
+			seen[file] = true
+			snapshots = append(snapshots, m.takeSnapshot(file))
+		}
+	}
+
+	deleted, err := sshconfig.DeleteEntries(m.configPath, hosts)
+	if err != nil {
+		m.err = err
+		m.mode = ModeList
+		return m, nil
+	}
+	clear(m.marked)
+	if deleted == 0 {
+		// Already gone from the file (edited elsewhere) - just refresh the list
+		m.statusMsg = "The selected hosts were already removed from the config"
+	} else {
+		m.rememberUndoAll(snapshots, fmt.Sprintf("deleting %d hosts", deleted), hosts[0])
+		m.statusMsg = fmt.Sprintf("Deleted %d hosts (u: undo)", deleted)
+	}
+
+	if err := m.reloadEntries(); err != nil {
+		m.err = err
+		m.mode = ModeList
+		return m, nil
+	}
+
+	m.mode = ModeList
+	m.deleteConfirm = false
+	if current := m.listModel.GetSelectedIndex(); current >= len(m.entries) {
+		m.listModel.SetSelected(max(0, len(m.entries)-1))
+	}
+	m.updateDetailView()
+	return m, nil
+}
@@ -1,11 +1,16 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/nicklasos/gosshit/internal/sshconfig"
@@ -22,6 +27,16 @@ const (
 	ModeAdd
 	ModeDelete
 	ModeClearVisits
+	ModePalette
+	ModeComments
+	ModeExport
+	ModeDescription
+	ModeTagFilter
+	ModePreview
+	ModeHelp
+	ModeRaw
+	ModeNotes
+	ModeOverride
 )
 
 // Model represents the main application model
@@ -29,13 +44,61 @@ type Model struct {
 	listModel   *ListModel
 	detailModel *DetailModel
 	editorModel *EditorModel
+	palette     *PaletteModel
+	comments    *CommentsEditorModel
+	tagPicker   *TagPickerModel
+	preview     *DiffPreviewModel
+	help        *HelpModel
+	raw         *RawViewModel
+	notesEditor *NotesEditorModel
 	tracker     *storage.VisitTracker
+	state       *storage.State
+	notes       *storage.Notes
 	entries     []*sshconfig.HostEntry // Display entries (Host * filtered out)
+	configOrder []*sshconfig.HostEntry // Display entries in config file order
 	configPath  string
 
+	standaloneComments []string // Comment block at the top of the config file
+
 	mode          Mode
 	searchInput   textinput.Model
+	exportInput   textinput.Model // Destination path prompt for exporting a host
+	descInput     textinput.Model // Inline Description prompt
+	overrideInput textinput.Model // One-off user/port prompt for connecting
 	deleteConfirm bool
+	deleteAlias   string         // With a multi-alias host: the one alias to delete ("" for the whole block)
+	deleteView    viewport.Model // Config lines the pending delete removes
+	previewReturn Mode           // Editor mode to return to from the diff preview
+	sortMode      SortMode
+	sortReverse   bool         // Reverse the sort order (e.g. least visited first)
+	statusMsg     string       // One-shot message shown above the status bar
+	undo          *undoState   // Last config change, restored with u
+	tmuxConnect   bool         // Connect through a per-host tmux session by default
+	wrapAround    bool         // j on the last host goes to the first, k on the first to the last
+	resolveHosts  bool         // Resolve DNS HostNames so search matches their addresses
+	watchConfig   bool         // Reload when the config file changes on disk
+	configStamp   configStamps // The config files as last loaded, to notice outside changes
+	pendingStamp  configStamps // An outside change waiting to settle before it is reloaded (nil if none)
+	countSuccess  bool         // Count a visit only once its session exits zero (GOSSHIT_COUNT_VISITS=success)
+	configLabel   string       // Shown in the status bar when a non-default config is active
+	readOnly      bool         // The config file can't be saved, so changes are disabled
+
+	controlStatuses map[string]controlStatus // ControlMaster status per host
+	checkedHost     string                   // Host the last selection checks were started for
+	reachability    map[string]reachability  // Reachability per host
+	fingerprints    map[string]fingerprint   // Key fingerprint per IdentityFile
+	marked          map[string]bool          // Hosts selected for a bulk delete, by Host
+	pinned          []string                 // Hosts always listed first, in pin order
+	resolving       map[string]bool          // HostNames looked up (or being looked up) for search
+
+	jumpBuffer string // Digits typed so far for a numeric jump
+	jumpSeq    int    // Invalidates stale jump timeouts
+	gPending   bool   // A g was pressed and may start gg
+	gSeq       int    // Invalidates stale g prefix timeouts
+
+	typeAhead       bool   // A ' was pressed: typed text moves to the matching host
+	typeAheadBuffer string // Text typed so far for the type-ahead
+	typeAheadSeq    int    // Invalidates stale type-ahead timeouts
 
 	width  int
 	height int
@@ -45,21 +108,16 @@ type Model struct {
 // InitialModel creates the initial model
 func InitialModel(configPath string) (*Model, error) {
 	// Load SSH config
-	entries, _, err := sshconfig.ParseConfig(configPath)
+	stamp := statFile(configPath)
+	entries, standaloneComments, err := sshconfig.ParseConfig(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse SSH config: %w", err)
 	}
+	configStamp := stampConfig(configPath, stamp, entries)
 
-	// Filter out Host * entries from display (they're global config, not specific hosts)
-	// But keep them in the entries list for preservation
-	displayEntries := make([]*sshconfig.HostEntry, 0, len(entries))
-	allEntries := make([]*sshconfig.HostEntry, 0, len(entries))
-	for _, entry := range entries {
-		allEntries = append(allEntries, entry)
-		if entry.Host != "*" {
-			displayEntries = append(displayEntries, entry)
-		}
-	}
+	// Host * entries are global config, not specific hosts; the writer keeps
+	// them in the file but they aren't listed
+	displayEntries := sshconfig.DisplayHosts(entries)
 
 	// Load visit tracker
 	tracker, err := storage.NewVisitTracker()
@@ -67,15 +125,29 @@ func InitialModel(configPath string) (*Model, error) {
 		return nil, fmt.Errorf("failed to load visit tracker: %w", err)
 	}
 
+	// Load persisted UI state
+	state, err := storage.NewState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+
+	// Load private per-host notes
+	notes, err := storage.NewNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notes: %w", err)
+	}
+
 	// Get visit counts (only for display entries)
 	visitCounts := make(map[string]int)
 	for _, entry := range displayEntries {
 		visitCounts[entry.Host] = tracker.GetCount(entry.Host)
 	}
 
-	// Sort entries by visit count (only display entries)
-	sortedHosts := tracker.SortByVisits(getHostNames(displayEntries))
-	sortedEntries := sortEntriesByHosts(displayEntries, sortedHosts)
+	// Sort entries by the remembered sort order, visits by default (only display entries)
+	sortMode, _ := ParseSortMode(state.Get(storage.SortModeKey))
+	sortReverse := state.Get(storage.SortReverseKey) == "yes"
+	pinned := loadPins(state)
+	sortedEntries := pinFirst(sortEntriesDirected(displayEntries, sortMode, sortReverse, tracker), pinned)
 
 	// Initialize models
 	listModel := NewListModel(sortedEntries, visitCounts)
@@ -86,18 +158,68 @@ func InitialModel(configPath string) (*Model, error) {
 	searchInput := textinput.New()
 	searchInput.Placeholder = "Search..."
 
+	// Initialize export path input
+	exportInput := textinput.New()
+	exportInput.Placeholder = "~/.ssh/config.d/host.conf"
+	exportInput.CharLimit = 4096
+
+	// Initialize inline description input
+	descInput := textinput.New()
+	descInput.Placeholder = "Description"
+
+	// Initialize the connect-as prompt
+	overrideInput := textinput.New()
+
 	model := &Model{
-		listModel:     listModel,
-		detailModel:   detailModel,
-		editorModel:   editorModel,
-		tracker:       tracker,
-		entries:       sortedEntries, // Display entries (without Host *)
-		configPath:    configPath,
-		mode:          ModeList,
-		searchInput:   searchInput,
-		deleteConfirm: false,
+		listModel:          listModel,
+		detailModel:        detailModel,
+		editorModel:        editorModel,
+		palette:            NewPaletteModel(),
+		comments:           NewCommentsEditorModel(),
+		tagPicker:          NewTagPickerModel(),
+		preview:            NewDiffPreviewModel(),
+		help:               NewHelpModel(),
+		raw:                NewRawViewModel(),
+		notesEditor:        NewNotesEditorModel(),
+		tracker:            tracker,
+		state:              state,
+		notes:              notes,
+		entries:            sortedEntries, // Display entries (without Host *)
+		configOrder:        displayEntries,
+		configPath:         configPath,
+		configStamp:        configStamp,
+		standaloneComments: standaloneComments,
+		mode:               ModeList,
+		searchInput:        searchInput,
+		exportInput:        exportInput,
+		descInput:          descInput,
+		overrideInput:      overrideInput,
+		deleteConfirm:      false,
+		deleteView:         viewport.New(0, 0),
+		countSuccess:       countVisitsOnSuccess(),
+		readOnly:           sshconfig.CheckWritable(configPath) != nil,
+		sortMode:           sortMode,
+		sortReverse:        sortReverse,
+		pinned:             pinned,
+		controlStatuses:    make(map[string]controlStatus),
+		reachability:       make(map[string]reachability),
+		fingerprints:       make(map[string]fingerprint),
+		marked:             make(map[string]bool),
+		resolving:          make(map[string]bool),
+	}
+	listModel.SetMarked(model.marked)
+	listModel.SetPinned(pinned)
+
+	switch state.Get(storage.ListLayoutKey) {
+	case "table":
+		listModel.SetLayout(LayoutTable)
+	case "compact":
+		listModel.SetLayout(LayoutCompact)
 	}
 
+	// Reopen on the host selected last time, if it still exists
+	model.selectHost(state.Get(storage.LastSelectedKey))
+
 	// Set initial selected entry
 	if len(sortedEntries) > 0 {
 		model.updateDetailView()
@@ -106,18 +228,147 @@ func InitialModel(configPath string) (*Model, error) {
 	return model, nil
 }
 
+// SetTmuxConnect makes Enter connect through a per-host tmux session
+func (m *Model) SetTmuxConnect(enabled bool) {
+	m.tmuxConnect = enabled
+}
+
+// SetWrapAround makes j/k wrap from the last host to the first and back
+func (m *Model) SetWrapAround(enabled bool) {
+	m.wrapAround = enabled
+}
+
+// SetConfigLabel sets a label describing the active config file (e.g. a project config)
+func (m *Model) SetConfigLabel(label string) {
+	m.configLabel = label
+}
+
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.listModel.Init(),
 		m.editorModel.Init(),
 		textinput.Blink,
+		m.watchTick(),
 	)
 }
 
 // Update handles updates
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	return model, tea.Batch(cmd, m.checkSelectedHost(), m.checkFingerprint(), m.resolveHostNames())
+}
+
+// checkFingerprint starts fingerprinting the selected host's IdentityFile
+// the first time it is shown
+func (m *Model) checkFingerprint() tea.Cmd {
+	entry := m.listModel.GetSelected()
+	if entry == nil || entry.IdentityFile == "" {
+		return nil
+	}
+	if _, requested := m.fingerprints[entry.IdentityFile]; requested {
+		return nil
+	}
+	m.fingerprints[entry.IdentityFile] = fingerprint{}
+	return loadFingerprint(entry.IdentityFile)
+}
+
+// checkSelectedHost starts the ControlMaster and reachability checks when the
+// selection moved to another host. The last known results are shown until
+// they complete.
+func (m *Model) checkSelectedHost() tea.Cmd {
+	entry := m.listModel.GetSelected()
+	if entry == nil || entry.Host == m.checkedHost {
+		return nil
+	}
+	m.checkedHost = entry.Host
+	if entry.IsPattern() {
+		// Nothing to dial or multiplex for a pattern
+		return nil
+	}
+	return tea.Batch(
+		checkControlMaster(entry.Host, m.sshArgs("-O", "check", entry.PrimaryAlias())),
+		m.refreshReachability(entry),
+	)
+}
+
+// refreshReachability starts a reachability check for entry
+func (m *Model) refreshReachability(entry *sshconfig.HostEntry) tea.Cmd {
+	if m.reachability[entry.Host] == reachabilityUnknown {
+		m.reachability[entry.Host] = reachabilityChecking
+		m.updateDetailView()
+	}
+	return checkReachability(entry)
+}
+
+// update handles updates; see Update
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case configEditedMsg:
+		return m.reloadAfterEdit(msg)
+
+	case sessionEndedMsg:
+		if m.countSuccess && msg.success {
+			m.tracker.Increment(msg.host)
+		}
+		m.tracker.AddDuration(msg.host, msg.duration)
+		// Best effort: we're about to quit, so there's nowhere to report a failure
+		_ = m.tracker.Save()
+		return m, tea.Quit
+
+	case resolvedMsg:
+		m.listModel.SetResolved(msg.hostname, msg.addrs)
+		m.updateDetailView()
+		return m, nil
+
+	case watchTickMsg:
+		return m.checkConfigChanged()
+
+	case jumpTimeoutMsg:
+		m.clearJump(msg)
+		return m, nil
+
+	case gPrefixTimeoutMsg:
+		m.resolveGPrefix(msg)
+		return m, nil
+
+	case typeAheadTimeoutMsg:
+		m.expireTypeAhead(msg)
+		return m, nil
+
+	case fingerprintMsg:
+		m.fingerprints[msg.identityFile] = fingerprint{art: msg.art, done: true}
+		m.updateDetailView()
+		return m, nil
+
+	case reachabilityMsg:
+		m.reachability[msg.host] = msg.status
+		m.updateDetailView()
+		return m, nil
+
+	case clipboardMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Couldn't copy the %s: %v", msg.what, msg.err)
+		} else {
+			m.statusMsg = fmt.Sprintf("Copied %s: %s", msg.what, msg.text)
+		}
+		return m, nil
+
+	case authTestMsg:
+		m.statusMsg = fmt.Sprintf("'%s': %s", msg.host, msg.result)
+		if msg.result != authOK && msg.detail != "" {
+			m.statusMsg += " (" + msg.detail + ")"
+		}
+		return m, nil
+
+	case controlStatusMsg:
+		m.controlStatuses[msg.host] = msg.status
+		m.updateDetailView()
+		if msg.status == controlInactive && m.statusMsg == "Closing master connection..." {
+			m.statusMsg = fmt.Sprintf("Closed master connection to '%s'", msg.host)
+		}
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -144,6 +395,61 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateDetailView()
 		return m, cmd
 
+	case ModeExport:
+		var cmd tea.Cmd
+		m.exportInput, cmd = m.exportInput.Update(msg)
+		return m, cmd
+
+	case ModeDescription:
+		var cmd tea.Cmd
+		m.descInput, cmd = m.descInput.Update(msg)
+		return m, cmd
+
+	case ModeOverride:
+		var cmd tea.Cmd
+		m.overrideInput, cmd = m.overrideInput.Update(msg)
+		return m, cmd
+
+	case ModeTagFilter:
+		var cmd tea.Cmd
+		m.tagPicker, cmd = m.tagPicker.Update(msg)
+		return m, cmd
+
+	case ModePalette:
+		var cmd tea.Cmd
+		m.palette, cmd = m.palette.Update(msg)
+		return m, cmd
+
+	case ModeComments:
+		var cmd tea.Cmd
+		m.comments, cmd = m.comments.Update(msg)
+		return m, cmd
+
+	case ModeNotes:
+		var cmd tea.Cmd
+		m.notesEditor, cmd = m.notesEditor.Update(msg)
+		return m, cmd
+
+	case ModePreview:
+		var cmd tea.Cmd
+		m.preview, cmd = m.preview.Update(msg)
+		return m, cmd
+
+	case ModeHelp:
+		var cmd tea.Cmd
+		m.help, cmd = m.help.Update(msg)
+		return m, cmd
+
+	case ModeRaw:
+		var cmd tea.Cmd
+		m.raw, cmd = m.raw.Update(msg)
+		return m, cmd
+
+	case ModeDelete:
+		var cmd tea.Cmd
+		m.deleteView, cmd = m.deleteView.Update(msg)
+		return m, cmd
+
 	case ModeEdit, ModeAdd:
 		var cmd tea.Cmd
 		var updatedEditor *EditorModel
@@ -183,8 +489,16 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Model, tea.Cmd) {
 		return false, m, nil
 
 	case ModeEdit, ModeAdd:
+		if m.editorModel.SelectingKey() {
+			// The key selector handles its own keys (Enter, Esc)
+			return false, m, nil
+		}
 		switch msg.String() {
-		case "enter":
+		case "enter", "ctrl+s":
+			if msg.String() == "enter" && m.editorModel.ExtraFocused() {
+				// Enter adds a line to the extra directives; let the editor handle it
+				return false, m, nil
+			}
 			if err := m.editorModel.Validate(); err != nil {
 				m.editorModel.SetError(err.Error())
 				return true, m, nil
@@ -198,6 +512,34 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Model, tea.Cmd) {
 		}
 		return false, m, nil
 
+	case ModePreview:
+		switch msg.String() {
+		case "y", "Y", "enter":
+			model, cmd := m.applyChange()
+			return true, model, cmd
+		case "n", "N", "esc":
+			m.mode = m.previewReturn
+			m.preview.Close()
+			return true, m, nil
+		}
+		return false, m, nil
+
+	case ModeHelp:
+		switch msg.String() {
+		case "?", "esc", "q":
+			m.mode = ModeList
+			return true, m, nil
+		}
+		return false, m, nil
+
+	case ModeRaw:
+		switch msg.String() {
+		case "R", "esc", "q":
+			m.mode = ModeList
+			return true, m, nil
+		}
+		return false, m, nil
+
 	case ModeDelete:
 		switch msg.String() {
 		case "y", "Y":
@@ -207,6 +549,9 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Model, tea.Cmd) {
 			m.mode = ModeList
 			m.deleteConfirm = false
 			return true, m, nil
+		case "tab":
+			m.cycleDeleteAlias()
+			return true, m, nil
 		}
 		return false, m, nil
 
@@ -221,7 +566,100 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Model, tea.Cmd) {
 		}
 		return false, m, nil
 
+	case ModePalette:
+		switch msg.String() {
+		case "esc":
+			m.mode = ModeList
+			m.palette.Close()
+			return true, m, nil
+		case "enter":
+			m.mode = ModeList
+			m.palette.Close()
+			action := m.palette.GetSelected()
+			if action == nil {
+				return true, m, nil
+			}
+			m.palette.RecordUse(action.name)
+			_, model, cmd := m.handleListKeyPress(keyMsgFor(action.key))
+			return true, model, cmd
+		}
+		return false, m, nil
+
+	case ModeExport:
+		switch msg.String() {
+		case "enter":
+			model, cmd := m.exportEntry()
+			return true, model, cmd
+		case "esc":
+			m.mode = ModeList
+			m.exportInput.Blur()
+			return true, m, nil
+		}
+		return false, m, nil
+
+	case ModeDescription:
+		switch msg.String() {
+		case "enter":
+			model, cmd := m.saveDescription()
+			return true, model, cmd
+		case "esc":
+			m.mode = ModeList
+			m.descInput.Blur()
+			return true, m, nil
+		}
+		return false, m, nil
+
+	case ModeOverride:
+		switch msg.String() {
+		case "enter":
+			model, cmd := m.connectWithOverride()
+			return true, model, cmd
+		case "esc":
+			m.mode = ModeList
+			m.overrideInput.Blur()
+			return true, m, nil
+		}
+		return false, m, nil
+
+	case ModeTagFilter:
+		switch msg.String() {
+		case "enter":
+			m.mode = ModeList
+			m.listModel.SetTagFilter(m.tagPicker.Checked())
+			m.updateDetailView()
+			return true, m, nil
+		case "esc":
+			m.mode = ModeList
+			return true, m, nil
+		}
+		return false, m, nil
+
+	case ModeComments:
+		switch msg.String() {
+		case "ctrl+s":
+			model, cmd := m.saveComments()
+			return true, model, cmd
+		case "esc":
+			m.mode = ModeList
+			m.comments.Blur()
+			return true, m, nil
+		}
+		return false, m, nil
+
+	case ModeNotes:
+		switch msg.String() {
+		case "ctrl+s":
+			model, cmd := m.saveNote()
+			return true, model, cmd
+		case "esc":
+			m.mode = ModeList
+			m.notesEditor.Blur()
+			return true, m, nil
+		}
+		return false, m, nil
+
 	case ModeList:
+		m.statusMsg = ""
 		handled, model, cmd := m.handleListKeyPress(msg)
 		return handled, model, cmd
 	}
@@ -230,14 +668,49 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Model, tea.Cmd) {
 }
 
 // handleListKeyPress handles key presses in list mode
+// configChangingKeys are the list keys that write the config file, disabled
+// when it isn't writable
+var configChangingKeys = map[string]bool{
+	"a": true, // add
+	"c": true, // duplicate
+	"e": true, // edit
+	"*": true, // Host * block
+	"D": true, // description
+	"d": true, // delete
+	"u": true, // undo
+	"P": true, // toggle port
+	"S": true, // split
+	"H": true, // header comments
+}
+
 func (m *Model) handleListKeyPress(msg tea.KeyMsg) (bool, tea.Model, tea.Cmd) {
+	if handled, cmd := m.handleTypeAheadKey(msg); handled {
+		return true, m, cmd
+	}
+	if handled, cmd := m.handleJumpKey(msg); handled {
+		return true, m, cmd
+	}
+	if handled, cmd := m.handleGotoKey(msg); handled {
+		return true, m, cmd
+	}
+
+	if m.readOnly && configChangingKeys[msg.String()] {
+		m.statusMsg = "The config file is read-only; check its permissions to make changes"
+		return true, m, nil
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c":
+		m.rememberSelection()
 		return true, m, tea.Quit
 
 	case "j", "down":
 		current := m.listModel.GetSelectedIndex()
-		m.listModel.SetSelected(current + 1)
+		if m.wrapAround && current >= len(m.listModel.filtered)-1 {
+			m.listModel.SetSelected(0)
+		} else {
+			m.listModel.SetSelected(current + 1)
+		}
 		m.updateDetailView()
 		return true, m, nil
 
@@ -245,10 +718,35 @@ func (m *Model) handleListKeyPress(msg tea.KeyMsg) (bool, tea.Model, tea.Cmd) {
 		current := m.listModel.GetSelectedIndex()
 		if current > 0 {
 			m.listModel.SetSelected(current - 1)
+		} else if m.wrapAround {
+			m.listModel.SetSelected(len(m.listModel.filtered) - 1)
 		}
 		m.updateDetailView()
 		return true, m, nil
 
+	case "ctrl+p":
+		m.mode = ModePalette
+		return true, m, m.palette.Open()
+
+	case "?":
+		m.mode = ModeHelp
+		m.help.Open()
+		return true, m, nil
+
+	case "n":
+		if entry := m.listModel.GetSelected(); entry != nil {
+			m.mode = ModeNotes
+			return true, m, m.notesEditor.SetNote(entry.Host, m.notes.Get(entry.Host))
+		}
+		return true, m, nil
+
+	case "R":
+		if entry := m.listModel.GetSelected(); entry != nil {
+			m.mode = ModeRaw
+			m.raw.Open(entry)
+		}
+		return true, m, nil
+
 	case "/":
 		m.mode = ModeSearch
 		m.searchInput.Focus()
@@ -259,19 +757,60 @@ func (m *Model) handleListKeyPress(msg tea.KeyMsg) (bool, tea.Model, tea.Cmd) {
 		m.editorModel.SetEntry(nil)
 		return true, m, nil
 
+	case "*":
+		model, cmd := m.editGlobalBlock()
+		return true, model, cmd
+
+	case "c":
+		entry := m.listModel.GetSelected()
+		if entry != nil {
+			m.mode = ModeAdd
+			m.editorModel.SetClone(entry)
+		}
+		return true, m, nil
+
 	case "e":
 		entry := m.listModel.GetSelected()
 		if entry != nil {
 			m.mode = ModeEdit
 			m.editorModel.SetEntry(entry)
+			if file := m.includedFile(entry); file != "" {
+				m.editorModel.SetSourceNote(fmt.Sprintf("Defined in %s, an included file that may be shared with other configs", file))
+			}
 		}
 		return true, m, nil
 
-	case "d":
+	case "D":
 		entry := m.listModel.GetSelected()
 		if entry != nil {
+			m.mode = ModeDescription
+			m.descInput.SetValue(entry.Description)
+			m.descInput.CursorEnd()
+			m.descInput.Focus()
+			return true, m, textinput.Blink
+		}
+		return true, m, nil
+
+	case " ":
+		m.toggleMark()
+		return true, m, nil
+
+	case "esc":
+		if len(m.marked) > 0 {
+			clear(m.marked)
+			m.statusMsg = "Selection cleared"
+		}
+		return true, m, nil
+
+	case "d":
+		entry := m.listModel.GetSelected()
+		if entry != nil || len(m.marked) > 0 {
 			m.mode = ModeDelete
 			m.deleteConfirm = false
+			m.deleteAlias = ""
+			m.deleteView.SetContent(m.deletedLines())
+			m.deleteView.GotoTop()
+			m.sizeDeleteView()
 		}
 		return true, m, nil
 
@@ -279,30 +818,208 @@ func (m *Model) handleListKeyPress(msg tea.KeyMsg) (bool, tea.Model, tea.Cmd) {
 		m.mode = ModeClearVisits
 		return true, m, nil
 
-	case "enter":
+	case "u":
+		model, cmd := m.undoLast()
+		return true, model, cmd
+
+	case "s":
+		m.cycleSortMode()
+		return true, m, nil
+
+	case "o":
+		m.toggleSortDirection()
+		return true, m, nil
+
+	case "p":
+		m.togglePin()
+		return true, m, nil
+
+	case "T":
+		m.mode = ModeTagFilter
+		m.tagPicker.Open(m.entries, m.listModel.TagFilter())
+		return true, m, nil
+
+	case "v":
+		m.toggleLayout()
+		return true, m, nil
+
+	case "z":
+		m.toggleGroup()
+		return true, m, nil
+
+	case "Z":
+		if m.listModel.ExpandGroups() {
+			m.updateDetailView()
+			m.statusMsg = "Expanded all groups"
+		}
+		return true, m, nil
+
+	case "m":
+		m.toggleCompact()
+		return true, m, nil
+
+	case "r":
 		entry := m.listModel.GetSelected()
 		if entry != nil {
-			model, cmd := m.connectToHost(entry)
+			m.reachability[entry.Host] = reachabilityChecking
+			m.updateDetailView()
+			return true, m, checkReachability(entry)
+		}
+		return true, m, nil
+
+	case "A":
+		entry := m.listModel.GetSelected()
+		if entry != nil {
+			model, cmd := m.testAuth(entry)
 			return true, model, cmd
 		}
 		return true, m, nil
-	}
 
-	return false, m, nil
-}
+	case "C":
+		model, cmd := m.editConfigFile()
+		return true, model, cmd
 
-// updateDetailView updates the detail view with the currently selected entry
+	case "ctrl+r":
+		model, cmd := m.reloadConfig()
+		return true, model, cmd
+
+	case "H":
+		m.mode = ModeComments
+		return true, m, m.comments.SetComments(m.standaloneComments)
+
+	case "enter":
+		entry := m.listModel.GetSelected()
+		if entry != nil {
+			if m.tmuxConnect {
+				model, cmd := m.connectWithTmux(entry)
+				return true, model, cmd
+			}
+			model, cmd := m.connectToHost(entry)
+			return true, model, cmd
+		}
+		return true, m, nil
+
+	case "w":
+		entry := m.listModel.GetSelected()
+		if entry != nil {
+			if msg := notConnectableMsg(entry); msg != "" {
+				m.statusMsg = msg
+				return true, m, nil
+			}
+			m.mode = ModeOverride
+			m.overrideInput.SetValue("")
+			m.overrideInput.Placeholder = overridePlaceholder(entry)
+			m.overrideInput.Focus()
+			return true, m, textinput.Blink
+		}
+		return true, m, nil
+
+	case "t":
+		entry := m.listModel.GetSelected()
+		if entry != nil {
+			model, cmd := m.connectWithTmux(entry)
+			return true, model, cmd
+		}
+		return true, m, nil
+
+	case "l":
+		entry := m.listModel.GetSelected()
+		if entry != nil {
+			model, cmd := m.openLogs(entry)
+			return true, model, cmd
+		}
+		return true, m, nil
+
+	case "i":
+		entry := m.listModel.GetSelected()
+		if entry != nil {
+			m.jumpToSameIdentity(entry)
+		}
+		return true, m, nil
+
+	case "y":
+		if entry := m.listModel.GetSelected(); entry != nil {
+			return true, m, copyToClipboard("ssh command", entry.GetSSHCommand())
+		}
+		return true, m, nil
+
+	case "Y":
+		if entry := m.listModel.GetSelected(); entry != nil {
+			return true, m, copyToClipboard("connection string", entry.GetConnectionString())
+		}
+		return true, m, nil
+
+	case "O":
+		entry := m.listModel.GetSelected()
+		if entry != nil {
+			model, cmd := m.closeControlMaster(entry)
+			return true, model, cmd
+		}
+		return true, m, nil
+
+	case "P":
+		entry := m.listModel.GetSelected()
+		if entry != nil {
+			model, cmd := m.togglePort(entry)
+			return true, model, cmd
+		}
+		return true, m, nil
+
+	case "S":
+		entry := m.listModel.GetSelected()
+		if entry != nil {
+			model, cmd := m.splitEntry(entry)
+			return true, model, cmd
+		}
+		return true, m, nil
+
+	case "E":
+		entry := m.listModel.GetSelected()
+		if entry != nil {
+			m.mode = ModeExport
+			m.exportInput.SetValue(fmt.Sprintf("~/.ssh/config.d/%s.conf", entry.PrimaryAlias()))
+			m.exportInput.CursorEnd()
+			m.exportInput.Focus()
+			return true, m, textinput.Blink
+		}
+		return true, m, nil
+	}
+
+	return false, m, nil
+}
+
+// updateDetailView updates the detail view with the currently selected entry
 func (m *Model) updateDetailView() {
 	entry := m.listModel.GetSelected()
 	if entry != nil {
 		m.detailModel.SetEntry(entry)
 		m.detailModel.SetVisitCount(m.tracker.GetCount(entry.Host))
+		m.detailModel.SetLastVisit(m.tracker.GetLastVisit(entry.Host))
+		m.detailModel.SetTotalDuration(m.tracker.GetTotalDuration(entry.Host))
+		m.detailModel.SetControlStatus(m.controlStatuses[entry.Host])
+		m.detailModel.SetReachability(m.reachability[entry.Host])
+		m.detailModel.SetFingerprint(m.fingerprints[entry.IdentityFile])
+		m.detailModel.SetNote(m.notes.Get(entry.Host))
+		m.detailModel.SetSourceFile(m.includedFile(entry))
 	}
 }
 
+// includedFile returns the file defining entry relative to the main config's
+// directory (e.g. "config.d/work"), or "" if it's the main config itself
+func (m *Model) includedFile(entry *sshconfig.HostEntry) string {
+	file := filepath.Clean(m.entryFile(entry))
+	if file == filepath.Clean(m.configPath) {
+		return ""
+	}
+	if rel, err := filepath.Rel(filepath.Dir(m.configPath), file); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return file
+}
+
 // updateSizes updates the sizes of all UI components
 func (m *Model) updateSizes() {
-	listWidth := 40
+	listWidth := listPaneWidth(m.width, m.listModel.Layout())
 	detailWidth := m.width - listWidth - 6
 	height := m.height - 4
 
@@ -311,121 +1028,710 @@ func (m *Model) updateSizes() {
 	// Editor needs space for borders and padding, similar to other panels
 	// Reduce by a bit to ensure borders are visible
 	m.editorModel.SetSize(m.width-4, m.height-4)
+	m.comments.SetSize(m.width-4, m.height-4)
+	m.notesEditor.SetSize(m.width-4, m.height-4)
+	m.preview.SetSize(m.width-4, m.height-4)
+	m.help.SetSize(m.width-4, m.height-4)
+	m.raw.SetSize(m.width-4, m.height-4)
+	m.sizeDeleteView()
+	m.tagPicker.SetSize(min(50, m.width-4), m.height-4)
+	m.palette.SetSize(min(70, m.width-4), min(len(paletteActions)+10, m.height-4))
+}
+
+// List pane width bounds; between them the list takes a third of the terminal
+const (
+	minListWidth = 30
+	maxListWidth = 60
+)
+
+// listPaneWidth returns the width of the list pane for a terminal width, the
+// detail panel taking the remainder
+func listPaneWidth(width int, layout ListLayout) int {
+	listWidth := min(max(width/3, minListWidth), maxListWidth)
+	if layout == LayoutTable {
+		// Columns need more room than cards; give the table most of the width
+		listWidth = max(listWidth, width*3/5)
+	}
+	return listWidth
 }
 
-// saveEntry saves the current entry from the editor
+// saveEntry plans the save of the current entry from the editor and shows
+// the resulting diff for confirmation; nothing is written yet
 func (m *Model) saveEntry() (tea.Model, tea.Cmd) {
 	entry := m.editorModel.GetEntry()
+	var change *sshconfig.Change
 	var err error
 
 	if m.mode == ModeAdd {
-		err = sshconfig.AddEntry(m.configPath, entry)
+		change, err = sshconfig.PlanAddEntry(m.configPath, entry)
 	} else {
 		oldEntry := m.editorModel.entry
 		if oldEntry != nil {
-			err = sshconfig.UpdateEntry(m.configPath, oldEntry.Host, entry)
+			change, err = sshconfig.PlanUpdateEntry(m.configPath, oldEntry.Host, entry)
 		}
 	}
 
+	if errors.Is(err, sshconfig.ErrHostNotFound) {
+		m.editorModel.SetError(fmt.Sprintf("'%s' is no longer in the config (renamed or removed elsewhere?) - nothing was saved", m.editorModel.entry.Host))
+		return m, nil
+	}
 	if err != nil {
 		m.editorModel.SetError(err.Error())
 		return m, nil
 	}
 
-	// Reload config
-	allNewEntries, _, err := sshconfig.ParseConfig(m.configPath)
+	if change == nil || change.Old == change.New {
+		model, cmd := m.finishSave(entry.Host)
+		m.statusMsg = "No changes to save"
+		return model, cmd
+	}
+
+	m.previewReturn = m.mode
+	m.mode = ModePreview
+	m.preview.Open(change)
+	return m, nil
+}
+
+// applyChange writes the previewed change and returns to the list
+func (m *Model) applyChange() (tea.Model, tea.Cmd) {
+	change := m.preview.Change()
+	m.preview.Close()
+	if change == nil {
+		m.mode = m.previewReturn
+		return m, nil
+	}
+
+	snapshot := m.takeSnapshot(change.Path)
+	err := change.Apply()
+	if errors.Is(err, sshconfig.ErrConfigChanged) {
+		m.mode = m.previewReturn
+		m.editorModel.SetError("The config file changed on disk after the preview - nothing was saved, save again to review the new changes")
+		return m, nil
+	}
 	if err != nil {
+		m.mode = m.previewReturn
+		m.editorModel.SetError(err.Error())
+		return m, nil
+	}
+
+	host := m.editorModel.GetEntry().Host
+	label := fmt.Sprintf("adding '%s'", host)
+	if m.previewReturn == ModeEdit {
+		label = fmt.Sprintf("editing '%s'", m.editorModel.entry.Host)
+	}
+	m.rememberUndo(snapshot, label, host)
+
+	return m.finishSave(host)
+}
+
+// finishSave reloads the config, closes the editor and selects host
+func (m *Model) finishSave(host string) (tea.Model, tea.Cmd) {
+	// Reload config
+	if err := m.reloadEntries(); err != nil {
 		m.err = err
 		return m, nil
 	}
 
-	// Filter out Host * entries from display
-	displayEntries := make([]*sshconfig.HostEntry, 0, len(allNewEntries))
-	for _, e := range allNewEntries {
-		if e.Host != "*" {
-			displayEntries = append(displayEntries, e)
-		}
+	m.mode = ModeList
+	m.editorModel.SetEntry(nil)
+
+	// Select the saved entry
+	m.selectHost(host)
+
+	m.updateDetailView()
+	return m, nil
+}
+
+// reloadEntries re-reads the config file and refreshes the sorted list
+func (m *Model) reloadEntries() error {
+	stamp := statFile(m.configPath)
+	allNewEntries, standaloneComments, err := sshconfig.ParseConfig(m.configPath)
+	if err != nil {
+		return err
 	}
+	m.configStamp = stampConfig(m.configPath, stamp, allNewEntries)
+	m.standaloneComments = standaloneComments
+
+	displayEntries := sshconfig.DisplayHosts(allNewEntries)
 
 	// Get visit counts and sort (only for display entries)
 	visitCounts := make(map[string]int)
 	for _, e := range displayEntries {
 		visitCounts[e.Host] = m.tracker.GetCount(e.Host)
 	}
-	sortedHosts := m.tracker.SortByVisits(getHostNames(displayEntries))
-	sortedEntries := sortEntriesByHosts(displayEntries, sortedHosts)
+	sortedEntries := m.sortedEntries(displayEntries)
 
+	m.configOrder = displayEntries
 	m.entries = sortedEntries
 	m.listModel.SetEntries(sortedEntries)
 	m.listModel.SetVisitCounts(visitCounts)
-	m.mode = ModeList
-	m.editorModel.SetEntry(nil)
+	m.pruneMarks()
+	return nil
+}
 
-	// Select the saved entry
-	for i, e := range sortedEntries {
-		if e.Host == entry.Host {
+// cycleSortMode switches to the next sort mode, keeping the direction
+func (m *Model) cycleSortMode() {
+	m.sortMode = m.sortMode.Next()
+	m.applySort()
+}
+
+// toggleSortDirection reverses the current sort order
+func (m *Model) toggleSortDirection() {
+	m.sortReverse = !m.sortReverse
+	m.applySort()
+}
+
+// applySort re-sorts the list, keeping the selection on the same host, and
+// remembers the sort order for the next session
+func (m *Model) applySort() {
+	var selectedHost string
+	if entry := m.listModel.GetSelected(); entry != nil {
+		selectedHost = entry.Host
+	}
+
+	m.entries = m.sortedEntries(m.configOrder)
+	m.listModel.SetEntries(m.entries)
+	m.selectHost(selectedHost)
+	m.updateDetailView()
+	m.statusMsg = "Sorted by " + m.sortMode.String()
+	if m.sortReverse {
+		m.statusMsg += " (reversed)"
+	}
+
+	reverse := ""
+	if m.sortReverse {
+		reverse = "yes"
+	}
+	m.state.Set(storage.SortModeKey, m.sortMode.String())
+	m.state.Set(storage.SortReverseKey, reverse)
+	if err := m.state.Save(); err != nil {
+		m.statusMsg = err.Error()
+	}
+}
+
+// cycleGrouping switches to the next list grouping, keeping the selection
+func (m *Model) cycleGrouping() {
+	grouping := m.listModel.Grouping().Next()
+	m.listModel.SetGrouping(grouping)
+	m.updateDetailView()
+	if grouping == GroupNone {
+		m.statusMsg = "Grouping off"
+		return
+	}
+	m.statusMsg = "Grouped by " + grouping.String()
+}
+
+// toggleGroup collapses the selected host's group, or expands the collapsed
+// group right above it
+func (m *Model) toggleGroup() {
+	name, collapsed := m.listModel.ToggleGroup()
+	m.updateDetailView()
+	switch {
+	case name == "":
+		m.statusMsg = "The list isn't grouped (press g)"
+	case collapsed:
+		m.statusMsg = "Collapsed " + name
+	default:
+		m.statusMsg = "Expanded " + name
+	}
+}
+
+// toggleLayout switches the list between cards and a table and remembers the choice
+func (m *Model) toggleLayout() {
+	layout := LayoutTable
+	if m.listModel.Layout() == LayoutTable {
+		layout = LayoutCards
+	}
+	m.setLayout(layout)
+}
+
+// toggleCompact switches the list between compact one-line entries and cards
+func (m *Model) toggleCompact() {
+	layout := LayoutCompact
+	if m.listModel.Layout() == LayoutCompact {
+		layout = LayoutCards
+	}
+	m.setLayout(layout)
+}
+
+// setLayout applies and remembers the list layout
+func (m *Model) setLayout(layout ListLayout) {
+	value := ""
+	switch layout {
+	case LayoutTable:
+		value = "table"
+	case LayoutCompact:
+		value = "compact"
+	}
+
+	m.listModel.SetLayout(layout)
+	m.updateSizes()
+
+	m.state.Set(storage.ListLayoutKey, value)
+	if err := m.state.Save(); err != nil {
+		m.statusMsg = err.Error()
+	}
+}
+
+// rememberSelection stores the selected Host so the next session reopens on it
+func (m *Model) rememberSelection() {
+	entry := m.listModel.GetSelected()
+	if entry == nil {
+		return
+	}
+	m.state.Set(storage.LastSelectedKey, entry.Host)
+	// Best effort: we're about to quit, so there's nowhere to report a failure
+	_ = m.state.Save()
+}
+
+// selectHost moves the list selection to the entry with the given Host
+func (m *Model) selectHost(host string) {
+	for i, e := range m.listModel.filtered {
+		if e.Host == host {
 			m.listModel.SetSelected(i)
-			break
+			return
 		}
 	}
+}
 
+// saveComments writes the edited header comments back to the config file
+func (m *Model) saveComments() (tea.Model, tea.Cmd) {
+	snapshot := m.takeSnapshot(m.configPath)
+	if err := sshconfig.UpdateStandaloneComments(m.configPath, m.comments.GetComments()); err != nil {
+		m.comments.SetError(err.Error())
+		return m, nil
+	}
+
+	selected := m.listModel.GetSelected()
+	var selectedHost string
+	if selected != nil {
+		selectedHost = selected.Host
+	}
+	m.rememberUndo(snapshot, "editing the header comments", selectedHost)
+
+	if err := m.reloadEntries(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if selected != nil {
+		m.selectHost(selected.Host)
+	}
 	m.updateDetailView()
+
+	m.mode = ModeList
+	m.comments.Blur()
+	m.statusMsg = "Header comments saved"
+	return m, nil
+}
+
+// saveNote stores the edited private note of a host
+func (m *Model) saveNote() (tea.Model, tea.Cmd) {
+	host := m.notesEditor.Host()
+	previous := m.notes.Get(host)
+	m.notes.Set(host, m.notesEditor.Note())
+	if err := m.notes.Save(); err != nil {
+		m.notes.Set(host, previous)
+		m.notesEditor.SetError(err.Error())
+		return m, nil
+	}
+
+	m.mode = ModeList
+	m.notesEditor.Blur()
+	m.updateDetailView()
+	m.statusMsg = fmt.Sprintf("Notes for '%s' saved", host)
+	return m, nil
+}
+
+// deletedLines renders the config lines a delete of the selected (or every
+// marked) host removes, comments included
+func (m *Model) deletedLines() string {
+	if len(m.marked) == 0 {
+		if entry := m.listModel.GetSelected(); entry != nil {
+			return renderRawLines(entry.RawLines)
+		}
+		return ""
+	}
+
+	var blocks []string
+	for _, e := range m.entries {
+		if m.marked[e.Host] {
+			blocks = append(blocks, renderRawLines(e.RawLines))
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// sizeDeleteView fits the delete confirmation's lines to their content, up
+// to the room left by the panel borders/padding, title, question, alias
+// choice and help text
+func (m *Model) sizeDeleteView() {
+	reserved := 14
+	if m.deleteAliasChoices() != nil {
+		reserved += 2
+	}
+	m.deleteView.Width = max(10, m.width-8)
+	m.deleteView.Height = max(1, min(m.deleteView.TotalLineCount(), m.height-reserved))
+}
+
+// deleteAliasChoices returns the aliases of the host being deleted when one
+// of them can be deleted on its own, or nil
+func (m *Model) deleteAliasChoices() []string {
+	if len(m.marked) > 0 {
+		return nil
+	}
+	entry := m.listModel.GetSelected()
+	if entry == nil || len(entry.Aliases()) < 2 {
+		return nil
+	}
+	return entry.Aliases()
+}
+
+// cycleDeleteAlias switches what the delete confirmation removes: the whole
+// block, then each alias on its own
+func (m *Model) cycleDeleteAlias() {
+	aliases := m.deleteAliasChoices()
+	if aliases == nil {
+		return
+	}
+	choices := append([]string{""}, aliases...)
+	for i, choice := range choices {
+		if choice == m.deleteAlias {
+			m.deleteAlias = choices[(i+1)%len(choices)]
+			return
+		}
+	}
+	m.deleteAlias = ""
+}
+
+// confirmDeleteAlias removes the chosen alias from entry's Host line, keeping
+// the block for its other aliases
+func (m *Model) confirmDeleteAlias(entry *sshconfig.HostEntry) (tea.Model, tea.Cmd) {
+	alias := m.deleteAlias
+	m.mode = ModeList
+	m.deleteAlias = ""
+
+	snapshot := m.takeSnapshot(m.entryFile(entry))
+	if err := sshconfig.DeleteAlias(m.configPath, entry.Host, alias); err != nil {
+		m.statusMsg = err.Error()
+		return m, nil
+	}
+	m.rememberUndo(snapshot, fmt.Sprintf("deleting alias '%s'", alias), entry.Host)
+
+	var remaining []string
+	for _, a := range entry.Aliases() {
+		if a != alias {
+			remaining = append(remaining, a)
+		}
+	}
+
+	if err := m.reloadEntries(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.selectHost(strings.Join(remaining, " "))
+	m.updateDetailView()
+	m.statusMsg = fmt.Sprintf("Deleted alias '%s' (u: undo)", alias)
 	return m, nil
 }
 
 // confirmDelete confirms and deletes the selected entry
 func (m *Model) confirmDelete() (tea.Model, tea.Cmd) {
+	if len(m.marked) > 0 {
+		return m.confirmBulkDelete()
+	}
+
 	entry := m.listModel.GetSelected()
 	if entry == nil {
 		m.mode = ModeList
 		return m, nil
 	}
+	if m.deleteAlias != "" {
+		return m.confirmDeleteAlias(entry)
+	}
 
+	snapshot := m.takeSnapshot(m.entryFile(entry))
 	err := sshconfig.DeleteEntry(m.configPath, entry.Host)
-	if err != nil {
+	if err != nil && !errors.Is(err, sshconfig.ErrHostNotFound) {
 		m.err = err
 		m.mode = ModeList
 		return m, nil
 	}
+	if err != nil {
+		// Already gone from the file (edited elsewhere) - just refresh the list
+		m.statusMsg = fmt.Sprintf("'%s' was already removed from the config", entry.Host)
+	} else {
+		m.rememberUndo(snapshot, fmt.Sprintf("deleting '%s'", entry.Host), entry.Host)
+		m.statusMsg = fmt.Sprintf("Deleted '%s' (u: undo)", entry.Host)
+	}
 
 	// Reload config
-	allNewEntries, _, err := sshconfig.ParseConfig(m.configPath)
-	if err != nil {
+	if err := m.reloadEntries(); err != nil {
 		m.err = err
 		m.mode = ModeList
 		return m, nil
 	}
 
-	// Filter out Host * entries from display
-	displayEntries := make([]*sshconfig.HostEntry, 0, len(allNewEntries))
-	for _, e := range allNewEntries {
-		if e.Host != "*" {
-			displayEntries = append(displayEntries, e)
+	m.mode = ModeList
+	m.deleteConfirm = false
+
+	// Adjust selection
+	current := m.listModel.GetSelectedIndex()
+	if current >= len(m.entries) && len(m.entries) > 0 {
+		m.listModel.SetSelected(len(m.entries) - 1)
+	} else if len(m.entries) == 0 {
+		m.listModel.SetSelected(0)
+	}
+	m.updateDetailView()
+	return m, nil
+}
+
+// editGlobalBlock opens the editor on the config's Host * block (hidden from
+// the list), or on a new one if there is none
+func (m *Model) editGlobalBlock() (tea.Model, tea.Cmd) {
+	entries, _, err := sshconfig.ParseConfig(m.configPath)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return m, nil
+	}
+
+	for _, entry := range entries {
+		if entry.Host == "*" {
+			m.mode = ModeEdit
+			m.editorModel.SetEntry(entry)
+			return m, nil
 		}
 	}
+	m.mode = ModeAdd
+	m.editorModel.SetGlobal()
+	return m, nil
+}
 
-	// Get visit counts and sort (only for display entries)
-	visitCounts := make(map[string]int)
-	for _, e := range displayEntries {
-		visitCounts[e.Host] = m.tracker.GetCount(e.Host)
+// jumpToSameIdentity moves the selection to the next host (in list order)
+// that uses the same IdentityFile as entry, wrapping around
+func (m *Model) jumpToSameIdentity(entry *sshconfig.HostEntry) {
+	if entry.IdentityFile == "" {
+		m.statusMsg = fmt.Sprintf("'%s' has no IdentityFile", entry.Host)
+		return
 	}
-	sortedHosts := m.tracker.SortByVisits(getHostNames(displayEntries))
-	sortedEntries := sortEntriesByHosts(displayEntries, sortedHosts)
 
-	m.entries = sortedEntries
-	m.listModel.SetEntries(sortedEntries)
-	m.listModel.SetVisitCounts(visitCounts)
+	var matches []int
+	position := 0
+	for i, e := range m.listModel.filtered {
+		if e.IdentityFile == entry.IdentityFile {
+			if e == entry {
+				position = len(matches)
+			}
+			matches = append(matches, i)
+		}
+	}
+
+	next := (position + 1) % len(matches)
+	m.listModel.SetSelected(matches[next])
+	m.updateDetailView()
+	m.statusMsg = fmt.Sprintf("%d of %d using %s", next+1, len(matches), entry.IdentityFile)
+}
+
+// togglePort swaps the host's Port between the default and a remembered
+// alternate port. The alternate is stored in the state file so it round-trips.
+func (m *Model) togglePort(entry *sshconfig.HostEntry) (tea.Model, tea.Cmd) {
+	updated := *entry
+	key := storage.AltPortKey(entry.Host)
+
+	if entry.IsDefaultPort() {
+		alt := m.state.Get(key)
+		if alt == "" {
+			m.statusMsg = fmt.Sprintf("No alternate port saved for '%s' (set a custom port first)", entry.Host)
+			return m, nil
+		}
+		updated.Port = alt
+	} else {
+		m.state.Set(key, entry.Port)
+		if err := m.state.Save(); err != nil {
+			m.statusMsg = err.Error()
+			return m, nil
+		}
+		updated.Port = ""
+	}
+
+	snapshot := m.takeSnapshot(m.entryFile(entry))
+	if err := sshconfig.UpdateEntry(m.configPath, entry.Host, &updated); err != nil {
+		m.statusMsg = err.Error()
+		return m, nil
+	}
+	m.rememberUndo(snapshot, fmt.Sprintf("the port change of '%s'", entry.Host), entry.Host)
+
+	if err := m.reloadEntries(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.selectHost(entry.Host)
+	m.updateDetailView()
+
+	if updated.Port == "" {
+		m.statusMsg = fmt.Sprintf("'%s' now uses the default port (alternate %s saved)", entry.Host, entry.Port)
+	} else {
+		m.statusMsg = fmt.Sprintf("'%s' now uses port %s", entry.Host, updated.Port)
+	}
+	return m, nil
+}
+
+// configEditedMsg is sent when the external editor opened by editConfigFile exits
+type configEditedMsg struct {
+	err error
+}
+
+// editConfigFile suspends the UI and opens the config file in the user's editor
+func (m *Model) editConfigFile() (tea.Model, tea.Cmd) {
+	argv, err := editorCommand(m.configPath)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return m, nil
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return configEditedMsg{err: err}
+	})
+}
+
+// reloadAfterEdit re-reads the config once the external editor exits
+func (m *Model) reloadAfterEdit(msg configEditedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Editor failed: %v", msg.err)
+		return m, nil
+	}
+	return m.reloadConfig()
+}
+
+// reloadConfig re-reads the config from disk, e.g. after it was changed in
+// another terminal, keeping the selection on the same alias when it still
+// exists. Parse errors are shown instead of replacing the list.
+func (m *Model) reloadConfig() (tea.Model, tea.Cmd) {
+	var selectedHost string
+	if entry := m.listModel.GetSelected(); entry != nil {
+		selectedHost = entry.Host
+	}
+
+	// Undoing the last UI change would throw away what was changed outside
+	m.undo = nil
+
+	if err := m.reloadEntries(); err != nil {
+		m.statusMsg = fmt.Sprintf("Config not reloaded: %v", err)
+		return m, nil
+	}
+	m.readOnly = sshconfig.CheckWritable(m.configPath) != nil
+
+	m.selectHost(selectedHost)
+	m.updateDetailView()
+	m.statusMsg = "Config reloaded"
+	return m, nil
+}
+
+// closeControlMaster stops the host's multiplexed master connection
+func (m *Model) closeControlMaster(entry *sshconfig.HostEntry) (tea.Model, tea.Cmd) {
+	if m.controlStatuses[entry.Host] != controlActive {
+		m.statusMsg = fmt.Sprintf("No master connection running for '%s'", entry.Host)
+		return m, nil
+	}
+
+	m.statusMsg = "Closing master connection..."
+	return m, exitControlMaster(entry.Host, m.sshArgs("-O", "exit", entry.PrimaryAlias()), m.sshArgs("-O", "check", entry.PrimaryAlias()))
+}
+
+// testAuth checks in the background that a non-interactive login to the host
+// succeeds, catching key and permission problems a port check misses
+func (m *Model) testAuth(entry *sshconfig.HostEntry) (tea.Model, tea.Cmd) {
+	if msg := notConnectableMsg(entry); msg != "" {
+		m.statusMsg = msg
+		return m, nil
+	}
+
+	m.statusMsg = fmt.Sprintf("Testing SSH login to '%s'...", entry.Host)
+	return m, testAuth(entry.Host, m.sshArgs(authTestArgs(entry.PrimaryAlias())...))
+}
+
+// splitEntry splits a multi-alias Host into one entry per alias
+func (m *Model) splitEntry(entry *sshconfig.HostEntry) (tea.Model, tea.Cmd) {
+	aliases := entry.Aliases()
+	if len(aliases) < 2 {
+		m.statusMsg = fmt.Sprintf("'%s' has only one alias", entry.Host)
+		return m, nil
+	}
+
+	snapshot := m.takeSnapshot(m.entryFile(entry))
+	if err := sshconfig.SplitEntry(m.configPath, entry.Host); err != nil {
+		m.statusMsg = err.Error()
+		return m, nil
+	}
+	m.rememberUndo(snapshot, fmt.Sprintf("splitting '%s'", entry.Host), entry.Host)
+
+	if err := m.reloadEntries(); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.selectHost(aliases[0])
+	m.updateDetailView()
+	m.statusMsg = fmt.Sprintf("Split '%s' into %d entries", entry.Host, len(aliases))
+	return m, nil
+}
+
+// saveDescription writes the Description entered in the inline prompt
+func (m *Model) saveDescription() (tea.Model, tea.Cmd) {
 	m.mode = ModeList
-	m.deleteConfirm = false
+	m.descInput.Blur()
 
-	// Adjust selection
-	current := m.listModel.GetSelectedIndex()
-	if current >= len(sortedEntries) && len(sortedEntries) > 0 {
-		m.listModel.SetSelected(len(sortedEntries) - 1)
-	} else if len(sortedEntries) == 0 {
-		m.listModel.SetSelected(0)
+	entry := m.listModel.GetSelected()
+	if entry == nil {
+		return m, nil
+	}
+
+	description := strings.TrimSpace(m.descInput.Value())
+	if description == entry.Description {
+		return m, nil
 	}
+
+	updated := *entry
+	updated.Description = description
+	snapshot := m.takeSnapshot(m.entryFile(entry))
+	if err := sshconfig.UpdateEntry(m.configPath, entry.Host, &updated); err != nil {
+		m.statusMsg = err.Error()
+		return m, nil
+	}
+	m.rememberUndo(snapshot, fmt.Sprintf("the description change of '%s'", entry.Host), entry.Host)
+
+	if err := m.reloadEntries(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.selectHost(entry.Host)
 	m.updateDetailView()
+
+	m.statusMsg = fmt.Sprintf("Updated description of '%s'", entry.Host)
+	return m, nil
+}
+
+// exportEntry writes the selected host to the path entered in the export prompt
+func (m *Model) exportEntry() (tea.Model, tea.Cmd) {
+	m.mode = ModeList
+	m.exportInput.Blur()
+
+	entry := m.listModel.GetSelected()
+	path := strings.TrimSpace(m.exportInput.Value())
+	if entry == nil || path == "" {
+		return m, nil
+	}
+
+	if err := sshconfig.ExportEntry(path, entry); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			m.statusMsg = fmt.Sprintf("%s already exists - nothing was exported", path)
+		} else {
+			m.statusMsg = err.Error()
+		}
+		return m, nil
+	}
+
+	m.statusMsg = fmt.Sprintf("Exported '%s' to %s", entry.Host, path)
 	return m, nil
 }
 
@@ -438,9 +1744,8 @@ func (m *Model) confirmClearVisits() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Re-sort entries (now they'll be in alphabetical order since all counts are 0)
-	sortedHosts := m.tracker.SortByVisits(getHostNames(m.entries))
-	sortedEntries := sortEntriesByHosts(m.entries, sortedHosts)
+	// Re-sort entries (by visits they'll now be in alphabetical order since all counts are 0)
+	sortedEntries := m.sortedEntries(m.configOrder)
 
 	// Reset visit counts display
 	visitCounts := make(map[string]int)
@@ -460,24 +1765,145 @@ func (m *Model) confirmClearVisits() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// connectToHost connects to the selected host via SSH
-func (m *Model) connectToHost(entry *sshconfig.HostEntry) (tea.Model, tea.Cmd) {
-	// Increment visit count
-	m.tracker.Increment(entry.Host)
-	if err := m.tracker.Save(); err != nil {
+// sshArgs prepends "-F <config>" to args when the active config isn't the
+// default ~/.ssh/config, so ssh resolves aliases from the same file
+func (m *Model) sshArgs(args ...string) []string {
+	if m.configPath == sshconfig.GetSSHConfigPath() {
+		return args
+	}
+	return append([]string{"-F", m.configPath}, args...)
+}
+
+// sshCommand returns the full command line for an interactive ssh session:
+// the configured ssh binary and extra args, then sshArgs(args...)
+func (m *Model) sshCommand(args ...string) ([]string, error) {
+	prefix, err := sshCommandPrefix()
+	if err != nil {
+		return nil, err
+	}
+	return append(prefix, m.sshArgs(args...)...), nil
+}
+
+// sessionEndedMsg is sent when an ssh session started from the list exits
+type sessionEndedMsg struct {
+	host     string
+	duration time.Duration
+	success  bool // The command exited zero
+}
+
+// countVisitsEnv selects when a connection counts as a visit: on every
+// attempt (the default), or with "success" only once ssh exits zero
+const countVisitsEnv = "GOSSHIT_COUNT_VISITS"
+
+// countVisitsOnSuccess reports whether GOSSHIT_COUNT_VISITS asks for
+// successful sessions only
+func countVisitsOnSuccess() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv(countVisitsEnv)), "success")
+}
+
+// recordVisit counts a visit to entry right away, unless visits are only
+// counted once the session succeeds, and remembers the selection
+func (m *Model) recordVisit(entry *sshconfig.HostEntry) error {
+	if !m.countSuccess {
+		m.tracker.Increment(entry.Host)
+		if err := m.tracker.Save(); err != nil {
+			return err
+		}
+	}
+	m.rememberSelection()
+	return nil
+}
+
+// runSession hands the terminal to cmd and quits once it exits, adding the
+// session's length to the host's time connected (and, with
+// GOSSHIT_COUNT_VISITS=success, counting the visit if it exited zero)
+func (m *Model) runSession(host string, cmd *exec.Cmd) tea.Cmd {
+	start := time.Now()
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return sessionEndedMsg{host: host, duration: time.Since(start), success: err == nil}
+	})
+}
+
+// connectToHost connects to the selected host via SSH; overrides are extra
+// flags for this connection only (e.g. -l user from the connect-as prompt)
+func (m *Model) connectToHost(entry *sshconfig.HostEntry, overrides ...string) (tea.Model, tea.Cmd) {
+	if msg := notConnectableMsg(entry); msg != "" {
+		m.statusMsg = msg
+		return m, nil
+	}
+	argv, err := m.sshCommand(append(overrides, entry.PrimaryAlias())...)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return m, nil
+	}
+
+	if err := m.recordVisit(entry); err != nil {
 		m.err = err
 		return m, nil
 	}
 
 	// Build SSH command
-	cmd := exec.Command("ssh", entry.Host)
+	cmd := exec.Command(argv[0], argv[1:]...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
-		return tea.Quit()
-	})
+	return m, m.runSession(entry.Host, cmd)
+}
+
+// connectWithTmux connects to the host inside a tmux session named after its alias
+func (m *Model) connectWithTmux(entry *sshconfig.HostEntry) (tea.Model, tea.Cmd) {
+	if msg := notConnectableMsg(entry); msg != "" {
+		m.statusMsg = msg
+		return m, nil
+	}
+	if !tmuxAvailable() {
+		m.statusMsg = "tmux not found in PATH"
+		return m, nil
+	}
+
+	argv, err := m.sshCommand(entry.PrimaryAlias())
+	if err != nil {
+		m.statusMsg = err.Error()
+		return m, nil
+	}
+
+	if err := m.recordVisit(entry); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	cmd := tmuxCommand(entry.PrimaryAlias(), argv)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return m, m.runSession(entry.Host, cmd)
+}
+
+// openLogs connects to the host and runs its logs command in a TTY
+func (m *Model) openLogs(entry *sshconfig.HostEntry) (tea.Model, tea.Cmd) {
+	if msg := notConnectableMsg(entry); msg != "" {
+		m.statusMsg = msg
+		return m, nil
+	}
+	argv, err := m.sshCommand("-t", entry.PrimaryAlias(), entry.GetLogsCommand())
+	if err != nil {
+		m.statusMsg = err.Error()
+		return m, nil
+	}
+
+	if err := m.recordVisit(entry); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return m, m.runSession(entry.Host, cmd)
 }
 
 // View renders the model
@@ -495,11 +1921,35 @@ func (m *Model) View() string {
 		return m.renderDeleteConfirm()
 	case ModeClearVisits:
 		return m.renderClearVisitsConfirm()
+	case ModeExport:
+		return m.renderPrompt("Export to: ", m.exportInput, "Enter: export | Esc: cancel")
+	case ModeDescription:
+		return m.renderPrompt("Description: ", m.descInput, "Enter: save | Esc: cancel")
+	case ModeOverride:
+		return m.renderPrompt("Connect as: ", m.overrideInput, "Enter: connect (this time only) | Esc: cancel")
+	case ModeComments:
+		return "\n" + lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Top, m.comments.View())
+	case ModeNotes:
+		return "\n" + lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Top, m.notesEditor.View())
+	case ModeTagFilter:
+		return "\n" + lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Top, m.tagPicker.View())
+	case ModePalette:
+		return "\n" + lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Top, m.palette.View())
+	case ModePreview:
+		return "\n" + lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Top, m.preview.View())
+	case ModeHelp:
+		return "\n" + lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Top, m.help.View())
+	case ModeRaw:
+		return "\n" + lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Top, m.raw.View())
 	default:
 		return m.renderList()
 	}
 }
 
+// listStatusKeys are the core keys shown in the list's status bar; every other
+// binding is listed in the ? overlay so the bar fits a normal terminal
+const listStatusKeys = "j/k: move | /: search | enter: connect | ctrl+p: palette | ?: help | q: quit"
+
 // renderList renders the list view
 func (m *Model) renderList() string {
 	listView := m.listModel.View()
@@ -511,7 +1961,20 @@ func (m *Model) renderList() string {
 	status := lipgloss.NewStyle().
 		Foreground(fgColor).
 		Padding(0, 1).
-		Render("j/k: navigate | /: search | a: add | e: edit | d: delete | x: clear visits | enter: connect | q: quit")
+		Render(listStatusKeys)
+
+	if m.readOnly {
+		status = lipgloss.JoinHorizontal(lipgloss.Top, errorStyle.Copy().Padding(0, 1).Render("read-only"), status)
+	}
+
+	if m.statusMsg != "" {
+		msg := warningStyle.Copy().Padding(0, 1).Render(m.statusMsg)
+		return lipgloss.JoinVertical(lipgloss.Left, content, msg, status)
+	}
+	if m.configLabel != "" {
+		label := statusBarModeStyle.Copy().Padding(0, 1).Render(m.configLabel)
+		return lipgloss.JoinVertical(lipgloss.Left, content, label, status)
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, content, status)
 }
@@ -536,6 +1999,21 @@ func (m *Model) renderSearch() string {
 	return lipgloss.JoinVertical(lipgloss.Left, content, status)
 }
 
+// renderPrompt renders the list view with a single-line prompt as status bar
+func (m *Model) renderPrompt(label string, input textinput.Model, help string) string {
+	listView := m.listModel.View()
+	detailView := m.detailModel.View()
+
+	content := lipgloss.JoinHorizontal(lipgloss.Top, listView, detailView)
+
+	status := lipgloss.NewStyle().
+		Foreground(fgColor).
+		Padding(0, 1).
+		Render(label + input.View() + " | " + help)
+
+	return lipgloss.JoinVertical(lipgloss.Left, content, status)
+}
+
 // renderEditor renders the editor view
 func (m *Model) renderEditor() string {
 	editorView := m.editorModel.View()
@@ -545,16 +2023,70 @@ func (m *Model) renderEditor() string {
 
 // renderDeleteConfirm renders the delete confirmation view
 func (m *Model) renderDeleteConfirm() string {
+	if len(m.marked) > 0 {
+		hosts := m.markedHosts()
+		msg := fmt.Sprintf("Delete %d hosts? (y/n)", len(hosts))
+		return detailPanelStyle.Width(m.width - 4).Render(
+			titleStyle.Render("Confirm Delete") + "\n\n" +
+				warningStyle.Render(msg) + "\n" +
+				lipgloss.NewStyle().Width(m.width-8).Render(strings.Join(hosts, ", ")) + "\n\n" +
+				helpStyle.Render("These lines will be removed:") + "\n" +
+				m.deleteView.View() + "\n\n" +
+				helpStyle.Render("y: confirm | n/Esc: cancel | j/k: scroll"),
+		)
+	}
+
 	entry := m.listModel.GetSelected()
 	if entry == nil {
 		return ""
 	}
 
-	msg := fmt.Sprintf("Delete host '%s'? (y/n)", entry.Host)
-	return detailPanelStyle.Width(m.width - 4).Height(10).Render(
+	aliases := m.deleteAliasChoices()
+	if aliases == nil {
+		msg := fmt.Sprintf("Delete host '%s'? (y/n)", entry.Host)
+		return detailPanelStyle.Width(m.width - 4).Render(
+			titleStyle.Render("Confirm Delete") + "\n\n" +
+				warningStyle.Render(msg) + "\n\n" +
+				helpStyle.Render("These lines will be removed:") + "\n" +
+				m.deleteView.View() + "\n\n" +
+				helpStyle.Render("y: confirm | n/Esc: cancel | j/k: scroll"),
+		)
+	}
+
+	// Choice between the whole block and each alias on its own
+	choices := append([]string{"whole block"}, aliases...)
+	var options []string
+	for i, choice := range choices {
+		if (i == 0 && m.deleteAlias == "") || (i > 0 && choice == m.deleteAlias) {
+			options = append(options, selectedStyle.Render(choice))
+		} else {
+			options = append(options, valueStyle.Render(choice))
+		}
+	}
+	choiceLine := labelStyle.Render("Delete: ") + strings.Join(options, " ")
+
+	var msg, details string
+	if m.deleteAlias == "" {
+		msg = fmt.Sprintf("Delete host '%s'? (y/n)", entry.Host)
+		details = helpStyle.Render("These lines will be removed:") + "\n" + m.deleteView.View()
+	} else {
+		var remaining []string
+		for _, alias := range aliases {
+			if alias != m.deleteAlias {
+				remaining = append(remaining, alias)
+			}
+		}
+		msg = fmt.Sprintf("Delete alias '%s' from '%s'? (y/n)", m.deleteAlias, entry.Host)
+		details = helpStyle.Render("The block and its settings stay as:") + "\n" +
+			valueStyle.Render("Host "+strings.Join(remaining, " "))
+	}
+
+	return detailPanelStyle.Width(m.width - 4).Render(
 		titleStyle.Render("Confirm Delete") + "\n\n" +
 			warningStyle.Render(msg) + "\n\n" +
-			helpStyle.Render("y: confirm | n/Esc: cancel"),
+			choiceLine + "\n\n" +
+			details + "\n\n" +
+			helpStyle.Render("y: confirm | tab: whole block or one alias | n/Esc: cancel | j/k: scroll"),
 	)
 }
 
@@ -568,40 +2100,37 @@ func (m *Model) renderClearVisitsConfirm() string {
 }
 
 // Helper functions
-func getHostNames(entries []*sshconfig.HostEntry) []string {
-	names := make([]string, len(entries))
-	for i, entry := range entries {
-		names[i] = entry.Host
-	}
-	return names
-}
 
-func sortEntriesByHosts(entries []*sshconfig.HostEntry, sortedHosts []string) []*sshconfig.HostEntry {
-	entryMap := make(map[string]*sshconfig.HostEntry)
-	for _, entry := range entries {
-		entryMap[entry.Host] = entry
+// notConnectableMsg explains why ssh would fail for entry before the UI
+// exits, or returns "" if the entry looks connectable
+func notConnectableMsg(entry *sshconfig.HostEntry) string {
+	if entry.IsPattern() {
+		return fmt.Sprintf("'%s' is a Host pattern - it applies to matching hosts and can't be connected to", entry.Host)
 	}
-
-	sorted := make([]*sshconfig.HostEntry, 0, len(entries))
-	for _, host := range sortedHosts {
-		if entry, ok := entryMap[host]; ok {
-			sorted = append(sorted, entry)
-		}
+	hostname := strings.TrimSpace(entry.HostName)
+	if hostname == "" {
+		return fmt.Sprintf("'%s' has no HostName - edit it (e) before connecting", entry.Host)
 	}
-
-	// Add any entries not in sortedHosts (shouldn't happen, but safety check)
-	for _, entry := range entries {
-		found := false
-		for _, host := range sortedHosts {
-			if entry.Host == host {
-				found = true
-				break
-			}
-		}
-		if !found {
-			sorted = append(sorted, entry)
-		}
+	if strings.ContainsAny(hostname, " \t") {
+		return fmt.Sprintf("'%s' has an invalid HostName %q - edit it (e) before connecting", entry.Host, hostname)
 	}
+	return ""
+}
 
-	return sorted
+// keyMsgFor builds the key message produced by pressing key, so palette
+// actions can be dispatched through the regular key handlers
+func keyMsgFor(key string) tea.KeyMsg {
+	switch key {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "space":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	case "ctrl+r":
+		return tea.KeyMsg{Type: tea.KeyCtrlR}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
 }
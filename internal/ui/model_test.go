@@ -0,0 +1,630 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+	"github.com/nicklasos/gosshit/internal/storage"
+)
+
+func newTestModel(t *testing.T, config string) *Model {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+
+	configPath := filepath.Join(home, "config")
+	if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	m, err := InitialModel(configPath)
+	if err != nil {
+		t.Fatalf("InitialModel failed: %v", err)
+	}
+	return m
+}
+
+func keyRunes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestModel_RestoresLastSelectedHost(t *testing.T) {
+	config := "Host alpha\n    HostName a.example.com\n\nHost beta\n    HostName b.example.com\n\nHost gamma\n    HostName c.example.com\n"
+	m := newTestModel(t, config)
+
+	m.selectHost("gamma")
+	m.Update(keyRunes("q"))
+
+	reopened, err := InitialModel(m.configPath)
+	if err != nil {
+		t.Fatalf("InitialModel failed: %v", err)
+	}
+	if entry := reopened.listModel.GetSelected(); entry == nil || entry.Host != "gamma" {
+		t.Errorf("Expected gamma to be selected, got %+v", entry)
+	}
+
+	// A host that no longer exists leaves the selection at the top
+	reopened.state.Set(storage.LastSelectedKey, "removed")
+	reopened.state.Save()
+	again, err := InitialModel(m.configPath)
+	if err != nil {
+		t.Fatalf("InitialModel failed: %v", err)
+	}
+	if got := again.listModel.GetSelectedIndex(); got != 0 {
+		t.Errorf("Expected the first entry for a missing host, got index %d", got)
+	}
+}
+
+func TestModel_SavePreviewsDiff(t *testing.T) {
+	config := "Host alpha\n    HostName a.example.com\n"
+	m := newTestModel(t, config)
+
+	m.Update(keyRunes("e"))
+	m.editorModel.fields[fieldUser].SetValue("deploy")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+
+	if m.mode != ModePreview {
+		t.Fatalf("Expected the diff preview after saving, got mode %d", m.mode)
+	}
+	if diff := m.preview.Change().Diff(); !strings.Contains(diff, "+    User deploy\n") {
+		t.Errorf("Unexpected diff:\n%s", diff)
+	}
+	if data, _ := os.ReadFile(m.configPath); string(data) != config {
+		t.Errorf("Config written before confirmation: %q", data)
+	}
+
+	// Esc goes back to the editor with the form intact
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != ModeEdit || m.editorModel.fields[fieldUser].Value() != "deploy" {
+		t.Fatalf("Expected to return to the editor, got mode %d", m.mode)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m.Update(keyRunes("y"))
+	if m.mode != ModeList {
+		t.Fatalf("Expected the list after confirming, got mode %d", m.mode)
+	}
+	if data, _ := os.ReadFile(m.configPath); !strings.Contains(string(data), "User deploy") {
+		t.Errorf("Confirmed change was not written: %q", data)
+	}
+}
+
+func TestModel_UndoDelete(t *testing.T) {
+	config := "# Description: Web\nHost web\n    HostName web.example.com\n\nHost db\n    HostName db.example.com\n"
+	m := newTestModel(t, config)
+
+	m.Update(keyRunes("u"))
+	if m.statusMsg != "Nothing to undo" {
+		t.Errorf("Expected nothing to undo, got %q", m.statusMsg)
+	}
+
+	m.selectHost("web")
+	m.Update(keyRunes("d"))
+	m.Update(keyRunes("y"))
+	if data, _ := os.ReadFile(m.configPath); strings.Contains(string(data), "Host web") {
+		t.Fatalf("web was not deleted: %q", data)
+	}
+
+	m.Update(keyRunes("u"))
+	if data, _ := os.ReadFile(m.configPath); string(data) != config {
+		t.Errorf("Undo didn't restore the config\ngot:\n%s\nwant:\n%s", data, config)
+	}
+	if entry := m.listModel.GetSelected(); entry == nil || entry.Host != "web" {
+		t.Errorf("Expected the restored host to be selected, got %+v", entry)
+	}
+	if !strings.HasPrefix(m.statusMsg, "Undid deleting 'web'") {
+		t.Errorf("Unexpected status: %q", m.statusMsg)
+	}
+
+	// Only one level
+	m.Update(keyRunes("u"))
+	if m.statusMsg != "Nothing to undo" {
+		t.Errorf("Expected a single level of undo, got %q", m.statusMsg)
+	}
+}
+
+func TestModel_UndoRefusesAfterOutsideEdit(t *testing.T) {
+	m := newTestModel(t, "Host web\n    HostName web.example.com\n\nHost db\n    HostName db.example.com\n")
+
+	m.selectHost("web")
+	m.Update(keyRunes("d"))
+	m.Update(keyRunes("y"))
+
+	// Edited in another terminal, without a reload
+	edited := "Host db\n    HostName db.example.com\n    User admin\n"
+	if err := os.WriteFile(m.configPath, []byte(edited), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Update(keyRunes("u"))
+	if data, _ := os.ReadFile(m.configPath); string(data) != edited {
+		t.Errorf("Undo must not overwrite the outside edit, got:\n%s", data)
+	}
+	if !strings.Contains(m.statusMsg, "changed on disk") {
+		t.Errorf("Undo should explain why it refused, got %q", m.statusMsg)
+	}
+}
+
+func TestModel_DeleteSingleAlias(t *testing.T) {
+	config := "Host web1 web2\n    HostName web.example.com\n    User deploy\n\nHost db\n    HostName db.example.com\n"
+	m := newTestModel(t, config)
+	m.width, m.height = 100, 40
+
+	m.selectHost("web1 web2")
+	m.Update(keyRunes("d"))
+	if view := m.renderDeleteConfirm(); !strings.Contains(view, "whole block") {
+		t.Fatalf("A multi-alias host should offer to delete one alias:\n%s", view)
+	}
+
+	// tab cycles: whole block -> web1 -> web2 -> whole block -> web1
+	for _, want := range []string{"web1", "web2", "", "web1"} {
+		m.Update(keyMsgFor("tab"))
+		if m.deleteAlias != want {
+			t.Fatalf("Expected alias %q to be chosen, got %q", want, m.deleteAlias)
+		}
+	}
+	if view := m.renderDeleteConfirm(); !strings.Contains(view, "Host web2") {
+		t.Errorf("The confirmation should show what stays:\n%s", view)
+	}
+
+	m.Update(keyRunes("y"))
+	want := strings.Replace(config, "Host web1 web2", "Host web2", 1)
+	if data, _ := os.ReadFile(m.configPath); string(data) != want {
+		t.Errorf("Only web1 should be removed, got:\n%s", data)
+	}
+	if entry := m.listModel.GetSelected(); entry == nil || entry.Host != "web2" {
+		t.Errorf("The remaining block should stay selected, got %+v", entry)
+	}
+	if m.statusMsg != "Deleted alias 'web1' (u: undo)" {
+		t.Errorf("Unexpected status %q", m.statusMsg)
+	}
+
+	m.Update(keyRunes("u"))
+	if data, _ := os.ReadFile(m.configPath); string(data) != config {
+		t.Errorf("Undo should restore the alias, got:\n%s", data)
+	}
+
+	// Without choosing an alias the whole block goes, as before
+	m.selectHost("web1 web2")
+	m.Update(keyRunes("d"))
+	m.Update(keyRunes("y"))
+	if data, _ := os.ReadFile(m.configPath); strings.Contains(string(data), "web") {
+		t.Errorf("The whole block should be deleted, got:\n%s", data)
+	}
+}
+
+func TestModel_BulkDelete(t *testing.T) {
+	config := "Host web\n    HostName web.example.com\n\nHost db\n    HostName db.example.com\n\nHost cache\n    HostName cache.example.com\n"
+	m := newTestModel(t, config)
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	m.selectHost("web")
+	m.Update(space)
+	m.selectHost("db")
+	m.Update(space)
+	if len(m.marked) != 2 {
+		t.Fatalf("Expected 2 marked hosts, got %v", m.marked)
+	}
+
+	m.Update(keyRunes("d"))
+	if !strings.Contains(m.renderDeleteConfirm(), "Delete 2 hosts?") {
+		t.Errorf("Expected a bulk confirmation, got:\n%s", m.renderDeleteConfirm())
+	}
+	m.Update(keyRunes("y"))
+
+	data, _ := os.ReadFile(m.configPath)
+	if strings.Contains(string(data), "Host web") || strings.Contains(string(data), "Host db") || !strings.Contains(string(data), "Host cache") {
+		t.Errorf("Expected only cache to remain, got:\n%s", data)
+	}
+	if len(m.marked) != 0 {
+		t.Errorf("Selection should be cleared after deleting, got %v", m.marked)
+	}
+
+	m.Update(keyRunes("u"))
+	if data, _ := os.ReadFile(m.configPath); string(data) != config {
+		t.Errorf("Undo didn't restore the config\ngot:\n%s\nwant:\n%s", data, config)
+	}
+}
+
+func TestModel_RefusesToConnectWithoutHostName(t *testing.T) {
+	m := newTestModel(t, "Host web\n    HostName web.example.com\n")
+	broken := &sshconfig.HostEntry{Host: "broken", HostName: "  "}
+
+	_, cmd := m.connectToHost(broken)
+	if cmd != nil {
+		t.Error("No ssh command should be started for an entry without HostName")
+	}
+	if !strings.Contains(m.statusMsg, "has no HostName") {
+		t.Errorf("Unexpected status: %q", m.statusMsg)
+	}
+	if got := m.tracker.GetCount("broken"); got != 0 {
+		t.Errorf("Visit count should not change, got %d", got)
+	}
+
+	m.connectToHost(&sshconfig.HostEntry{Host: "spaced", HostName: "a b"})
+	if !strings.Contains(m.statusMsg, "invalid HostName") {
+		t.Errorf("Unexpected status: %q", m.statusMsg)
+	}
+
+	for _, entry := range []*sshconfig.HostEntry{{Host: "ok", HostName: "ok.example.com"}, {Host: "*.internal"}} {
+		msg := notConnectableMsg(entry)
+		if (msg == "") != !entry.IsPattern() {
+			t.Errorf("notConnectableMsg(%q) = %q", entry.Host, msg)
+		}
+	}
+}
+
+func TestModel_Notes(t *testing.T) {
+	config := "Host web\n    HostName web.example.com\n"
+	m := newTestModel(t, config)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+
+	m.Update(keyRunes("n"))
+	if m.mode != ModeNotes {
+		t.Fatalf("Expected notes mode, got %d", m.mode)
+	}
+	m.notesEditor.textarea.SetValue("password: see vault\nticket OPS-42")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.mode != ModeList {
+		t.Fatalf("Ctrl+S should save and return to the list, got mode %d", m.mode)
+	}
+
+	if view := m.detailModel.View(); !strings.Contains(view, "ticket OPS-42") {
+		t.Errorf("Note should be shown in the detail panel:\n%s", view)
+	}
+	if data, _ := os.ReadFile(m.configPath); string(data) != config {
+		t.Errorf("Notes must not touch the SSH config, got:\n%s", data)
+	}
+
+	notes, err := storage.NewNotes()
+	if err != nil {
+		t.Fatalf("NewNotes failed: %v", err)
+	}
+	if got := notes.Get("web"); got != "password: see vault\nticket OPS-42" {
+		t.Errorf("Note not persisted, got %q", got)
+	}
+}
+
+func TestModel_RecordsSessionDuration(t *testing.T) {
+	m := newTestModel(t, "Host web\n    HostName web.example.com\n")
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+
+	_, cmd := m.Update(sessionEndedMsg{host: "web", duration: 90 * time.Minute})
+	if cmd == nil {
+		t.Fatal("A finished session should quit")
+	}
+	if got := m.tracker.GetTotalDuration("web"); got != 90*time.Minute {
+		t.Errorf("Duration not recorded, got %v", got)
+	}
+
+	tracker, err := storage.NewVisitTracker()
+	if err != nil {
+		t.Fatalf("NewVisitTracker failed: %v", err)
+	}
+	if got := tracker.GetTotalDuration("web"); got != 90*time.Minute {
+		t.Errorf("Duration not persisted, got %v", got)
+	}
+
+	m.updateDetailView()
+	if view := m.detailModel.View(); !strings.Contains(view, "1h 30m") {
+		t.Errorf("Time connected should be shown in the detail panel:\n%s", view)
+	}
+}
+
+func TestModel_EditGlobalBlock(t *testing.T) {
+	m := newTestModel(t, "Host web\n    HostName web.example.com\n")
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 60})
+
+	// No Host * yet: the editor adds one
+	m.Update(keyRunes("*"))
+	if m.mode != ModeAdd || m.editorModel.fields[fieldHost].Value() != "*" {
+		t.Fatalf("Expected to add a Host * block, got mode %d host %q", m.mode, m.editorModel.fields[fieldHost].Value())
+	}
+	m.editorModel.fields[fieldAddKeysToAgent].SetValue("yes")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m.Update(keyRunes("y"))
+	if m.mode != ModeList {
+		t.Fatalf("Expected to return to the list, got mode %d", m.mode)
+	}
+
+	// Now it exists and is edited in place
+	m.Update(keyRunes("*"))
+	if m.mode != ModeEdit || m.editorModel.fields[fieldAddKeysToAgent].Value() != "yes" {
+		t.Fatalf("Expected to edit the Host * block, got mode %d", m.mode)
+	}
+	m.editorModel.fields[fieldUseKeychain].SetValue("yes")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m.Update(keyRunes("y"))
+
+	data, err := os.ReadFile(m.configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	want := "Host web\n    HostName web.example.com\n\nHost *\n    AddKeysToAgent yes\n    UseKeychain yes\n"
+	if string(data) != want {
+		t.Errorf("Unexpected config\ngot:  %q\nwant: %q", data, want)
+	}
+	if len(m.entries) != 1 {
+		t.Errorf("Host * should stay out of the list, got %d entries", len(m.entries))
+	}
+}
+
+func TestModel_ReverseSortIsRemembered(t *testing.T) {
+	config := "Host alpha\n    HostName a.example.com\n\nHost beta\n    HostName b.example.com\n\nHost gamma\n    HostName c.example.com\n"
+	m := newTestModel(t, config)
+	m.tracker.Increment("gamma")
+	m.tracker.Increment("gamma")
+	m.tracker.Increment("alpha")
+
+	m.Update(keyRunes("o"))
+	hosts := func(m *Model) []string {
+		var names []string
+		for _, e := range m.entries {
+			names = append(names, e.Host)
+		}
+		return names
+	}
+	if got := strings.Join(hosts(m), ","); got != "beta,alpha,gamma" {
+		t.Errorf("Reversed visit order = %s, want beta,alpha,gamma", got)
+	}
+	if m.statusMsg != "Sorted by visits (reversed)" {
+		t.Errorf("Unexpected status %q", m.statusMsg)
+	}
+
+	m.Update(keyRunes("s"))
+	if got := strings.Join(hosts(m), ","); got != "gamma,beta,alpha" {
+		t.Errorf("Reversed alphabetical order = %s, want gamma,beta,alpha", got)
+	}
+
+	reopened, err := InitialModel(m.configPath)
+	if err != nil {
+		t.Fatalf("InitialModel failed: %v", err)
+	}
+	if reopened.sortMode != SortAlphabetical || !reopened.sortReverse {
+		t.Errorf("Sort order not remembered: mode %v reverse %v", reopened.sortMode, reopened.sortReverse)
+	}
+	if got := strings.Join(hosts(reopened), ","); got != "gamma,beta,alpha" {
+		t.Errorf("Reopened order = %s, want gamma,beta,alpha", got)
+	}
+}
+
+func TestModel_CountVisitsOnSuccess(t *testing.T) {
+	t.Setenv(countVisitsEnv, "success")
+	config := "Host alpha\n    HostName a.example.com\n"
+	m := newTestModel(t, config)
+	entry := m.listModel.GetSelected()
+
+	if _, cmd := m.connectToHost(entry); cmd == nil {
+		t.Fatal("Expected a command to run ssh")
+	}
+	if got := m.tracker.GetCount("alpha"); got != 0 {
+		t.Errorf("Visit counted before the session ended: %d", got)
+	}
+
+	m.Update(sessionEndedMsg{host: "alpha", duration: time.Second, success: false})
+	if got := m.tracker.GetCount("alpha"); got != 0 {
+		t.Errorf("Failed session should not count, got %d", got)
+	}
+	m.Update(sessionEndedMsg{host: "alpha", duration: time.Second, success: true})
+	if got := m.tracker.GetCount("alpha"); got != 1 {
+		t.Errorf("Successful session should count once, got %d", got)
+	}
+}
+
+func TestModel_CountVisitsOnAttempt(t *testing.T) {
+	config := "Host alpha\n    HostName a.example.com\n"
+	m := newTestModel(t, config)
+
+	m.connectToHost(m.listModel.GetSelected())
+	m.Update(sessionEndedMsg{host: "alpha", duration: time.Second, success: true})
+	if got := m.tracker.GetCount("alpha"); got != 1 {
+		t.Errorf("Attempt should count exactly once, got %d", got)
+	}
+}
+
+func TestModel_PinHost(t *testing.T) {
+	config := "Host alpha\n    HostName a.example.com\n\nHost beta\n    HostName b.example.com\n\nHost gamma\n    HostName c.example.com\n"
+	m := newTestModel(t, config)
+	m.tracker.Increment("alpha")
+	m.tracker.Increment("alpha")
+	m.tracker.Increment("beta")
+
+	hosts := func(m *Model) string {
+		var names []string
+		for _, e := range m.entries {
+			names = append(names, e.Host)
+		}
+		return strings.Join(names, ",")
+	}
+
+	m.selectHost("gamma")
+	m.Update(keyRunes("p"))
+	m.selectHost("beta")
+	m.Update(keyRunes("p"))
+	if got := hosts(m); got != "gamma,beta,alpha" {
+		t.Errorf("Pinned hosts should come first in pin order, got %s", got)
+	}
+	if entry := m.listModel.GetSelected(); entry == nil || entry.Host != "beta" {
+		t.Errorf("Selection should stay on beta, got %+v", entry)
+	}
+	if line := m.listModel.formatEntry(m.entries[0], false); !strings.Contains(line, "★ gamma") {
+		t.Errorf("Pinned host should have a marker, got %q", line)
+	}
+
+	// Reversing the sort keeps the pins on top
+	m.Update(keyRunes("o"))
+	if got := hosts(m); got != "gamma,beta,alpha" {
+		t.Errorf("Pins should stay first when reversed, got %s", got)
+	}
+
+	reopened, err := InitialModel(m.configPath)
+	if err != nil {
+		t.Fatalf("InitialModel failed: %v", err)
+	}
+	if got := hosts(reopened); got != "gamma,beta,alpha" {
+		t.Errorf("Pins not remembered, got %s", got)
+	}
+
+	// Unpin
+	reopened.selectHost("gamma")
+	reopened.Update(keyRunes("p"))
+	if got := hosts(reopened); got != "beta,gamma,alpha" {
+		t.Errorf("Unpinned gamma should drop back into the (reversed) sort, got %s", got)
+	}
+}
+
+func TestModel_ReadOnlyConfig(t *testing.T) {
+	m := newTestModel(t, "Host alpha\n    HostName a.example.com\n")
+	if m.readOnly {
+		t.Fatal("A writable config should not be read-only")
+	}
+
+	m.readOnly = true
+	m.width, m.height = 200, 20
+	for _, key := range []string{"a", "e", "d"} {
+		m.Update(keyRunes(key))
+		if m.mode != ModeList {
+			t.Errorf("%s should be disabled for a read-only config, got mode %v", key, m.mode)
+		}
+		if !strings.Contains(m.statusMsg, "read-only") {
+			t.Errorf("%s should explain why it is disabled, got %q", key, m.statusMsg)
+		}
+	}
+	if !strings.Contains(m.renderList(), "read-only") {
+		t.Error("Status bar should show the read-only indicator")
+	}
+
+	// Browsing still works
+	m.Update(keyRunes("/"))
+	if m.mode != ModeSearch {
+		t.Errorf("Search should still be available, got mode %v", m.mode)
+	}
+}
+
+func TestModel_ReloadConfig(t *testing.T) {
+	m := newTestModel(t, "Host alpha\n    HostName a.example.com\n\nHost beta\n    HostName b.example.com\n")
+	m.selectHost("beta")
+
+	// Edited in another terminal: a host added before the selection
+	config := "Host aaa\n    HostName new.example.com\n\nHost alpha\n    HostName a.example.com\n\nHost beta\n    HostName b2.example.com\n"
+	if err := os.WriteFile(m.configPath, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Update(keyMsgFor("ctrl+r"))
+	if len(m.entries) != 3 {
+		t.Fatalf("Expected 3 hosts after reload, got %d", len(m.entries))
+	}
+	selected := m.listModel.GetSelected()
+	if selected == nil || selected.Host != "beta" || selected.HostName != "b2.example.com" {
+		t.Errorf("Selection should stay on the reloaded beta, got %+v", selected)
+	}
+	if m.statusMsg != "Config reloaded" {
+		t.Errorf("Unexpected status %q", m.statusMsg)
+	}
+}
+
+func TestListPaneWidth(t *testing.T) {
+	tests := []struct {
+		width  int
+		layout ListLayout
+		want   int
+	}{
+		{width: 60, layout: LayoutCards, want: minListWidth},
+		{width: 120, layout: LayoutCards, want: 40},
+		{width: 150, layout: LayoutCards, want: 50},
+		{width: 300, layout: LayoutCards, want: maxListWidth},
+		{width: 300, layout: LayoutTable, want: 180},
+	}
+	for _, tt := range tests {
+		if got := listPaneWidth(tt.width, tt.layout); got != tt.want {
+			t.Errorf("listPaneWidth(%d, %v) = %d, want %d", tt.width, tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestModel_WrapAroundNavigation(t *testing.T) {
+	config := "Host alpha\n    HostName a.example.com\n\nHost beta\n    HostName b.example.com\n"
+	m := newTestModel(t, config)
+	m.sortMode = SortAlphabetical
+	m.applySort()
+	m.selectHost("beta")
+
+	// Off by default: j stays on the last host
+	m.Update(keyRunes("j"))
+	if entry := m.listModel.GetSelected(); entry.Host != "beta" {
+		t.Errorf("Without wrap-around j should stay on the last host, got %s", entry.Host)
+	}
+
+	m.SetWrapAround(true)
+	m.Update(keyRunes("j"))
+	if entry := m.listModel.GetSelected(); entry.Host != "alpha" {
+		t.Errorf("j on the last host should wrap to the first, got %s", entry.Host)
+	}
+	m.Update(keyRunes("k"))
+	if entry := m.listModel.GetSelected(); entry.Host != "beta" {
+		t.Errorf("k on the first host should wrap to the last, got %s", entry.Host)
+	}
+}
+
+func TestModel_ShowsIncludedSourceFile(t *testing.T) {
+	m := newTestModel(t, "Include config.d/*\n\nHost main\n    HostName main.example.com\n")
+	dir := filepath.Dir(m.configPath)
+	if err := os.MkdirAll(filepath.Join(dir, "config.d"), 0700); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.d", "work"), []byte("Host work\n    HostName work.example.com\n"), 0600); err != nil {
+		t.Fatalf("Failed to write included config: %v", err)
+	}
+	m, err := InitialModel(m.configPath)
+	if err != nil {
+		t.Fatalf("InitialModel failed: %v", err)
+	}
+	m.width, m.height = 200, 40
+	m.updateSizes()
+
+	m.selectHost("main")
+	m.updateDetailView()
+	if strings.Contains(m.detailModel.View(), "Defined in:") {
+		t.Error("Hosts in the main config should not show their file")
+	}
+
+	m.selectHost("work")
+	m.updateDetailView()
+	if view := m.detailModel.View(); !strings.Contains(view, "Defined in:") || !strings.Contains(view, filepath.Join("config.d", "work")) {
+		t.Errorf("Detail panel should show the included file, got:\n%s", view)
+	}
+
+	m.Update(keyRunes("e"))
+	if !strings.Contains(m.editorModel.View(), "Defined in config.d/work, an included file") {
+		t.Errorf("Editor should warn about the included file, got:\n%s", m.editorModel.View())
+	}
+}
+
+func TestModel_DeleteConfirmShowsBlock(t *testing.T) {
+	config := "# Description: Production web\nHost web\n    HostName web.example.com\n    ServerAliveInterval 30\n"
+	m := newTestModel(t, config)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	m.Update(keyRunes("d"))
+	if m.mode != ModeDelete {
+		t.Fatalf("d should ask for confirmation, got mode %v", m.mode)
+	}
+	view := m.renderDeleteConfirm()
+	for _, want := range []string{"Delete host 'web'?", "# Description: Production web", "ServerAliveInterval 30"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Confirmation should show %q, got:\n%s", want, view)
+		}
+	}
+
+	// Scrolling keys move the lines rather than answering
+	m.Update(keyRunes("j"))
+	if m.mode != ModeDelete {
+		t.Errorf("j should scroll, not leave the confirmation (mode %v)", m.mode)
+	}
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// NotesEditorModel edits a host's private note, which is stored by gosshit
+// rather than in the SSH config
+type NotesEditorModel struct {
+	textarea textarea.Model
+	host     string
+	width    int
+	height   int
+	errorMsg string
+}
+
+// NewNotesEditorModel creates a new notes editor
+func NewNotesEditorModel() *NotesEditorModel {
+	ta := textarea.New()
+	ta.Placeholder = "Private notes, e.g. where the password is kept or ticket links"
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+
+	return &NotesEditorModel{
+		textarea: ta,
+	}
+}
+
+// SetNote loads host's note into the editor and focuses it
+func (m *NotesEditorModel) SetNote(host, note string) tea.Cmd {
+	m.host = host
+	m.errorMsg = ""
+	m.textarea.SetValue(note)
+	return m.textarea.Focus()
+}
+
+// Host returns the host whose note is being edited
+func (m *NotesEditorModel) Host() string {
+	return m.host
+}
+
+// Note returns the edited note
+func (m *NotesEditorModel) Note() string {
+	return m.textarea.Value()
+}
+
+// SetError sets an error message
+func (m *NotesEditorModel) SetError(msg string) {
+	m.errorMsg = msg
+}
+
+// Blur removes focus from the editor
+func (m *NotesEditorModel) Blur() {
+	m.textarea.Blur()
+}
+
+// SetSize sets the size of the notes editor
+func (m *NotesEditorModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	// Leave room for panel borders/padding, title and help text
+	m.textarea.SetWidth(max(10, width-8))
+	m.textarea.SetHeight(max(3, height-10))
+}
+
+// Update handles updates to the notes editor
+func (m *NotesEditorModel) Update(msg tea.Msg) (*NotesEditorModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.textarea, cmd = m.textarea.Update(msg)
+	return m, cmd
+}
+
+// View renders the notes editor
+func (m *NotesEditorModel) View() string {
+	var lines []string
+	lines = append(lines, titleStyle.Render("Notes: "+m.host))
+	lines = append(lines, m.textarea.View())
+
+	if m.errorMsg != "" {
+		lines = append(lines, "")
+		lines = append(lines, errorStyle.Render("Error: "+m.errorMsg))
+	}
+
+	lines = append(lines, helpStyle.Render("Ctrl+S: save | Esc: cancel | notes stay in gosshit's data directory, not in your SSH config"))
+
+	return detailPanelStyle.Width(m.width).Height(m.height).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+// parseConnectOverride parses a one-off "[user][:port]" override typed in the
+// connect-as prompt into ssh flags (-l user, -p port)
+func parseConnectOverride(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	user, port, hasPort := strings.Cut(value, ":")
+	user = strings.TrimSpace(user)
+	port = strings.TrimSpace(port)
+
+	var args []string
+	if user != "" {
+		if strings.ContainsAny(user, " \t@") {
+			return nil, fmt.Errorf("User %q can't contain spaces or @", user)
+		}
+		args = append(args, "-l", user)
+	}
+	if hasPort {
+		if err := validatePort(port); err != nil {
+			return nil, err
+		}
+		args = append(args, "-p", port)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("Enter a user, :port or user:port")
+	}
+	return args, nil
+}
+
+// connectWithOverride connects to the selected host with the user and/or port
+// typed in the connect-as prompt; the entry itself is left unchanged
+func (m *Model) connectWithOverride() (tea.Model, tea.Cmd) {
+	m.mode = ModeList
+	m.overrideInput.Blur()
+
+	entry := m.listModel.GetSelected()
+	if entry == nil {
+		return m, nil
+	}
+	args, err := parseConnectOverride(m.overrideInput.Value())
+	if err != nil {
+		m.statusMsg = err.Error()
+		return m, nil
+	}
+	return m.connectToHost(entry, args...)
+}
+
+// overridePlaceholder suggests the entry's own user and port in the prompt
+func overridePlaceholder(entry *sshconfig.HostEntry) string {
+	user := entry.User
+	if user == "" {
+		user = "user"
+	}
+	port := entry.Port
+	if port == "" {
+		port = "22"
+	}
+	return fmt.Sprintf("%s:%s (user, :port or user:port)", user, port)
+}
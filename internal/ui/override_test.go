@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConnectOverride(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{value: "root", want: []string{"-l", "root"}},
+		{value: ":2201", want: []string{"-p", "2201"}},
+		{value: " deploy : 2201 ", want: []string{"-l", "deploy", "-p", "2201"}},
+		{value: "deploy:", wantErr: true},
+		{value: "deploy:70000", wantErr: true},
+		{value: "bad user", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseConnectOverride(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseConnectOverride(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseConnectOverride(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestModel_ConnectAsRejectsInvalidPort(t *testing.T) {
+	m := newTestModel(t, "Host web\n    HostName web.example.com\n    User deploy\n")
+
+	m.Update(keyRunes("w"))
+	if m.mode != ModeOverride {
+		t.Fatalf("w should open the connect-as prompt, got mode %v", m.mode)
+	}
+	if m.overrideInput.Placeholder != "deploy:22 (user, :port or user:port)" {
+		t.Errorf("Placeholder should suggest the host's user and port, got %q", m.overrideInput.Placeholder)
+	}
+
+	m.Update(keyRunes("root:99999"))
+	m.Update(keyMsgFor("enter"))
+	if m.mode != ModeList {
+		t.Errorf("Expected to return to the list, got mode %v", m.mode)
+	}
+	if m.statusMsg != `Port must be a number between 1 and 65535 (got "99999")` {
+		t.Errorf("Unexpected status: %q", m.statusMsg)
+	}
+	if m.tracker.GetCount("web") != 0 {
+		t.Error("A rejected override should not count a visit")
+	}
+	if entry := m.listModel.GetSelected(); entry.User != "deploy" || entry.Port != "" {
+		t.Errorf("The entry should be unchanged, got %+v", entry)
+	}
+}
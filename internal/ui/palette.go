@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteAction is a list-mode action that can be run from the command palette
+type paletteAction struct {
+	name string // Short name shown in the palette
+	key  string // List-mode key the action dispatches to
+	desc string // One-line description
+}
+
+// paletteActions lists every list-mode action available from the palette
+var paletteActions = []paletteAction{
+	{name: "connect", key: "enter", desc: "Connect to the selected host"},
+	{name: "tmux connect", key: "t", desc: "Connect inside a per-host tmux session"},
+	{name: "connect as", key: "w", desc: "Connect once with another user or port, without editing the host"},
+	{name: "logs", key: "l", desc: "Tail the selected host's logs"},
+	{name: "add", key: "a", desc: "Add a new host"},
+	{name: "duplicate", key: "c", desc: "Add a new host prefilled from the selected one"},
+	{name: "edit", key: "e", desc: "Edit the selected host"},
+	{name: "edit Host *", key: "*", desc: "Edit the global Host * block (AddKeysToAgent, UseKeychain, ...)"},
+	{name: "notes", key: "n", desc: "Edit the host's private notes (not written to the SSH config)"},
+	{name: "description", key: "D", desc: "Edit the selected host's Description inline"},
+	{name: "select", key: "space", desc: "Select the host for a bulk delete"},
+	{name: "delete", key: "d", desc: "Delete the selected host (or all selected hosts)"},
+	{name: "undo", key: "u", desc: "Restore the config from before the last change"},
+	{name: "same key", key: "i", desc: "Jump to the next host using the same IdentityFile"},
+	{name: "copy command", key: "y", desc: "Copy the full ssh command to the clipboard"},
+	{name: "copy connection string", key: "Y", desc: "Copy user@host to the clipboard"},
+	{name: "check reachability", key: "r", desc: "Re-check whether the host's SSH port is reachable"},
+	{name: "test auth", key: "A", desc: "Try a BatchMode ssh login to catch key and permission problems"},
+	{name: "close master", key: "O", desc: "Close the host's ControlMaster (multiplexed) connection"},
+	{name: "toggle port", key: "P", desc: "Swap Port between 22 and the remembered alternate"},
+	{name: "split", key: "S", desc: "Split a multi-alias host into separate entries"},
+	{name: "export", key: "E", desc: "Write the selected host to a standalone file"},
+	{name: "edit config file", key: "C", desc: "Open the config file in $EDITOR and reload it afterwards"},
+	{name: "reload", key: "ctrl+r", desc: "Re-read the config file, e.g. after editing it in another terminal"},
+	{name: "header comments", key: "H", desc: "Edit the comments at the top of the config"},
+	{name: "sort", key: "s", desc: "Cycle sorting: visits, alphabetical, config order, hostname, recent"},
+	{name: "reverse sort", key: "o", desc: "Reverse the sort order (e.g. least visited first)"},
+	{name: "pin", key: "p", desc: "Pin the host to the top of the list, whatever the sort order"},
+	{name: "group", key: "g", desc: "Cycle grouping: none, by tag, by first tag"},
+	{name: "collapse group", key: "z", desc: "Collapse the selected host's group, or expand the collapsed one above it"},
+	{name: "expand groups", key: "Z", desc: "Expand every collapsed group"},
+	{name: "table layout", key: "v", desc: "Toggle the host list between cards and a table"},
+	{name: "compact layout", key: "m", desc: "Toggle one line per host: alias, hostname and tags"},
+	{name: "raw block", key: "R", desc: "Show the host's config lines verbatim"},
+	{name: "filter tags", key: "T", desc: "Filter the list to hosts carrying all selected tags"},
+	{name: "search", key: "/", desc: "Search hosts"},
+	{name: "type-ahead jump", key: "'", desc: "Type the start of an alias to move to it, without filtering"},
+	{name: "clear visits", key: "x", desc: "Clear all visit counts"},
+	{name: "help", key: "?", desc: "Show every keybinding"},
+	{name: "quit", key: "q", desc: "Quit gosshit"},
+}
+
+// PaletteModel represents the command palette overlay
+type PaletteModel struct {
+	input    textinput.Model
+	actions  []paletteAction
+	filtered []paletteAction
+	recent   []string // Action names, most recent first
+	selected int
+	width    int
+	height   int
+}
+
+// NewPaletteModel creates a new command palette
+func NewPaletteModel() *PaletteModel {
+	input := textinput.New()
+	input.Placeholder = "Type to filter actions..."
+	input.Prompt = ": "
+
+	return &PaletteModel{
+		input:   input,
+		actions: paletteActions,
+	}
+}
+
+// Open resets the filter and focuses the palette input
+func (m *PaletteModel) Open() tea.Cmd {
+	m.input.SetValue("")
+	m.selected = 0
+	m.applyFilter()
+	return m.input.Focus()
+}
+
+// Close blurs the palette input
+func (m *PaletteModel) Close() {
+	m.input.Blur()
+}
+
+// RecordUse moves an action to the front of the recent list
+func (m *PaletteModel) RecordUse(name string) {
+	recent := []string{name}
+	for _, r := range m.recent {
+		if r != name {
+			recent = append(recent, r)
+		}
+	}
+	m.recent = recent
+}
+
+// GetSelected returns the currently highlighted action, or nil if none match
+func (m *PaletteModel) GetSelected() *paletteAction {
+	if m.selected < 0 || m.selected >= len(m.filtered) {
+		return nil
+	}
+	return &m.filtered[m.selected]
+}
+
+// applyFilter filters actions by fuzzy match, putting recently used actions first
+func (m *PaletteModel) applyFilter() {
+	recentRank := make(map[string]int)
+	for i, name := range m.recent {
+		recentRank[name] = len(m.recent) - i
+	}
+
+	type scored struct {
+		action paletteAction
+		score  int
+	}
+	var matches []scored
+	for _, action := range m.actions {
+		ok, score := fuzzyMatch(m.input.Value(), action.name)
+		if ok {
+			matches = append(matches, scored{action, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return recentRank[matches[i].action.name] > recentRank[matches[j].action.name]
+	})
+
+	m.filtered = make([]paletteAction, len(matches))
+	for i, match := range matches {
+		m.filtered[i] = match.action
+	}
+	if m.selected >= len(m.filtered) {
+		m.selected = max(0, len(m.filtered)-1)
+	}
+}
+
+// Update handles updates to the palette
+func (m *PaletteModel) Update(msg tea.Msg) (*PaletteModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "down", "ctrl+n":
+			if m.selected < len(m.filtered)-1 {
+				m.selected++
+			}
+			return m, nil
+		case "up", "ctrl+p":
+			if m.selected > 0 {
+				m.selected--
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.applyFilter()
+	return m, cmd
+}
+
+// SetSize sets the size of the palette
+func (m *PaletteModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// View renders the palette
+func (m *PaletteModel) View() string {
+	var lines []string
+	lines = append(lines, titleStyle.Render("Command Palette"))
+	lines = append(lines, m.input.View())
+	lines = append(lines, "")
+
+	if len(m.filtered) == 0 {
+		lines = append(lines, valueStyle.Foreground(subtleColor).Render("No matching actions"))
+	}
+	for i, action := range m.filtered {
+		line := action.name + "  " + labelStyle.Render("("+action.key+") "+action.desc)
+		if i == m.selected {
+			lines = append(lines, listItemSelectedStyle.Render("▶ "+line))
+		} else {
+			lines = append(lines, listItemStyle.Render("  "+line))
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, helpStyle.Render("↑↓: navigate | Enter: run | Esc: cancel"))
+
+	return detailPanelStyle.Width(m.width).Height(m.height).Render(strings.Join(lines, "\n"))
+}
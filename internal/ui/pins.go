@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+	"github.com/nicklasos/gosshit/internal/storage"
+)
+
+// loadPins reads the pinned hosts, in pin order, from state
+func loadPins(state *storage.State) []string {
+	value := state.Get(storage.PinnedKey)
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, "\t")
+}
+
+// pinFirst moves the entries whose Host is pinned to the front, in pin order,
+// keeping the order of the rest. sorted itself is not modified.
+func pinFirst(sorted []*sshconfig.HostEntry, pinned []string) []*sshconfig.HostEntry {
+	if len(pinned) == 0 {
+		return sorted
+	}
+
+	result := make([]*sshconfig.HostEntry, 0, len(sorted))
+	for _, host := range pinned {
+		for _, entry := range sorted {
+			if entry.Host == host {
+				result = append(result, entry)
+			}
+		}
+	}
+	for _, entry := range sorted {
+		if !slices.Contains(pinned, entry.Host) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// togglePin pins the selected host to the top of the list, or unpins it, and
+// remembers the pins for the next session
+func (m *Model) togglePin() {
+	entry := m.listModel.GetSelected()
+	if entry == nil {
+		return
+	}
+
+	if i := slices.Index(m.pinned, entry.Host); i >= 0 {
+		m.pinned = slices.Delete(m.pinned, i, i+1)
+		m.statusMsg = "Unpinned '" + entry.Host + "'"
+	} else {
+		m.pinned = append(m.pinned, entry.Host)
+		m.statusMsg = "Pinned '" + entry.Host + "'"
+	}
+	m.listModel.SetPinned(m.pinned)
+
+	selectedHost := entry.Host
+	m.entries = m.sortedEntries(m.configOrder)
+	m.listModel.SetEntries(m.entries)
+	m.selectHost(selectedHost)
+	m.updateDetailView()
+
+	m.state.Set(storage.PinnedKey, strings.Join(m.pinned, "\t"))
+	if err := m.state.Save(); err != nil {
+		m.statusMsg = err.Error()
+	}
+}
+
+// sortedEntries orders entries (in config file order) by the current sort
+// mode and direction, with pinned hosts first
+func (m *Model) sortedEntries(entries []*sshconfig.HostEntry) []*sshconfig.HostEntry {
+	return pinFirst(sortEntriesDirected(entries, m.sortMode, m.sortReverse, m.tracker), m.pinned)
+}
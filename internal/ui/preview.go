@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+// DiffPreviewModel shows a pending config change as a unified diff and
+// waits for confirmation before it is written
+type DiffPreviewModel struct {
+	viewport viewport.Model
+	change   *sshconfig.Change
+	width    int
+	height   int
+}
+
+// NewDiffPreviewModel creates a new diff preview
+func NewDiffPreviewModel() *DiffPreviewModel {
+	return &DiffPreviewModel{
+		viewport: viewport.New(0, 0),
+	}
+}
+
+// Open shows the diff of change, scrolled to the top
+func (m *DiffPreviewModel) Open(change *sshconfig.Change) {
+	m.change = change
+	m.viewport.SetContent(renderDiff(change.Diff()))
+	m.viewport.GotoTop()
+}
+
+// Change returns the change being previewed
+func (m *DiffPreviewModel) Change() *sshconfig.Change {
+	return m.change
+}
+
+// Close forgets the previewed change
+func (m *DiffPreviewModel) Close() {
+	m.change = nil
+}
+
+// SetSize sets the size of the preview
+func (m *DiffPreviewModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	// Leave room for panel borders/padding, title and help text
+	m.viewport.Width = max(10, width-4)
+	m.viewport.Height = max(3, height-6)
+}
+
+// Update scrolls the diff
+func (m *DiffPreviewModel) Update(msg tea.Msg) (*DiffPreviewModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the preview
+func (m *DiffPreviewModel) View() string {
+	var lines []string
+	lines = append(lines, titleStyle.Render("Review Changes"))
+	lines = append(lines, m.viewport.View())
+	lines = append(lines, helpStyle.Render("y/Enter: write | n/Esc: back to editor | j/k: scroll"))
+
+	return detailPanelStyle.Width(m.width).Height(m.height).Render(strings.Join(lines, "\n"))
+}
+
+// renderDiff colors the lines of a unified diff
+func renderDiff(diff string) string {
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			lines[i] = diffHeaderStyle.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = diffHunkStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = diffAddStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = diffRemoveStyle.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+// RawViewModel shows the selected host's block exactly as it appears in the
+// config file, including comments and directives gosshit doesn't parse
+type RawViewModel struct {
+	viewport viewport.Model
+	title    string
+	width    int
+	height   int
+}
+
+// NewRawViewModel creates a new raw view
+func NewRawViewModel() *RawViewModel {
+	return &RawViewModel{
+		viewport: viewport.New(0, 0),
+	}
+}
+
+// Open shows the raw lines of entry, scrolled to the top
+func (m *RawViewModel) Open(entry *sshconfig.HostEntry) {
+	m.title = "Raw: " + entry.Host
+	if entry.SourceFile != "" {
+		m.title += fmt.Sprintf(" (%s:%d)", filepath.Base(entry.SourceFile), entry.StartLine)
+	}
+	m.viewport.SetContent(renderRawLines(entry.RawLines))
+	m.viewport.GotoTop()
+}
+
+// SetSize sets the size of the raw view
+func (m *RawViewModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	// Leave room for panel borders/padding, title and help text
+	m.viewport.Width = max(10, width-4)
+	m.viewport.Height = max(3, height-6)
+}
+
+// Update scrolls the raw lines
+func (m *RawViewModel) Update(msg tea.Msg) (*RawViewModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the raw view
+func (m *RawViewModel) View() string {
+	var lines []string
+	lines = append(lines, titleStyle.Render(m.title))
+	lines = append(lines, m.viewport.View())
+	lines = append(lines, helpStyle.Render("j/k: scroll | R/Esc/q: close"))
+
+	return detailPanelStyle.Width(m.width).Height(m.height).Render(strings.Join(lines, "\n"))
+}
+
+// renderRawLines returns the lines verbatim, with tabs expanded to 8-column
+// tab stops so the layout matches what an editor shows
+func renderRawLines(rawLines []string) string {
+	if len(rawLines) == 0 {
+		return helpStyle.Render("(no raw lines - the host hasn't been written to the config yet)")
+	}
+
+	lines := make([]string, len(rawLines))
+	for i, line := range rawLines {
+		var b strings.Builder
+		col := 0
+		for _, r := range line {
+			if r == '\t' {
+				n := 8 - col%8
+				b.WriteString(strings.Repeat(" ", n))
+				col += n
+				continue
+			}
+			b.WriteRune(r)
+			col++
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
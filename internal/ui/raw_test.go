@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRenderRawLines_ExpandsTabs(t *testing.T) {
+	got := renderRawLines([]string{"Host web", "\tHostName web.example.com", "ab\tc"})
+	want := "Host web\n        HostName web.example.com\nab      c"
+	if got != want {
+		t.Errorf("renderRawLines:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestModel_RawView(t *testing.T) {
+	config := "# Description: Web\nHost web\n\tHostName web.example.com\n\tServerAliveInterval 30 # keepalive\n"
+	m := newTestModel(t, config)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	m.Update(keyRunes("R"))
+	if m.mode != ModeRaw {
+		t.Fatalf("Expected raw mode, got %d", m.mode)
+	}
+	view := m.View()
+	for _, want := range []string{"Raw: web (config:2)", "# Description: Web", "ServerAliveInterval 30 # keepalive"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the raw view:\n%s", want, view)
+		}
+	}
+
+	m.Update(keyMsgFor("esc"))
+	if m.mode != ModeList {
+		t.Errorf("Esc should close the raw view, got mode %d", m.mode)
+	}
+}
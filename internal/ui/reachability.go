@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"net"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+// reachabilityTimeout bounds how long a reachability check may take
+const reachabilityTimeout = 2 * time.Second
+
+// reachability describes whether a host's SSH port accepts connections
+type reachability int
+
+const (
+	reachabilityUnknown     reachability = iota // Not checked yet
+	reachabilityChecking                        // Check in progress
+	reachabilityReachable                       // TCP connect succeeded
+	reachabilityUnreachable                     // TCP connect failed or timed out
+	reachabilitySkipped                         // Behind a ProxyJump/ProxyCommand, not dialed directly
+)
+
+// reachabilityMsg carries the result of a reachability check
+type reachabilityMsg struct {
+	host   string
+	status reachability
+}
+
+// checkReachability dials the entry's HostName:Port in the background
+func checkReachability(entry *sshconfig.HostEntry) tea.Cmd {
+	host := entry.Host
+	if entry.ProxyJump != "" || entry.ProxyCommand != "" {
+		return func() tea.Msg {
+			return reachabilityMsg{host: host, status: reachabilitySkipped}
+		}
+	}
+
+	hostname := entry.HostName
+	if hostname == "" {
+		hostname = entry.PrimaryAlias()
+	}
+	port := entry.Port
+	if port == "" {
+		port = sshconfig.DefaultPort
+	}
+	address := net.JoinHostPort(hostname, port)
+
+	return func() tea.Msg {
+		conn, err := net.DialTimeout("tcp", address, reachabilityTimeout)
+		if err != nil {
+			return reachabilityMsg{host: host, status: reachabilityUnreachable}
+		}
+		conn.Close()
+		return reachabilityMsg{host: host, status: reachabilityReachable}
+	}
+}
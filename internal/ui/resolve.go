@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+// resolveTimeout bounds a single HostName lookup
+const resolveTimeout = 3 * time.Second
+
+// lookupHost resolves a hostname to its addresses (replaced in tests)
+var lookupHost = net.DefaultResolver.LookupHost
+
+// resolvedMsg carries the addresses a HostName resolved to (none on failure)
+type resolvedMsg struct {
+	hostname string
+	addrs    []string
+}
+
+// SetResolveHosts makes search also match the addresses DNS HostNames resolve
+// to; lookups run in the background, once per HostName
+func (m *Model) SetResolveHosts(enabled bool) {
+	m.resolveHosts = enabled
+}
+
+// resolveHostNames starts a lookup for every HostName that is a DNS name and
+// hasn't been looked up yet, so hosts added later are covered too
+func (m *Model) resolveHostNames() tea.Cmd {
+	if !m.resolveHosts {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, entry := range m.entries {
+		hostname := strings.ToLower(entry.HostName)
+		if hostname == "" || entry.IsPattern() || net.ParseIP(hostname) != nil || m.resolving[hostname] {
+			continue
+		}
+		m.resolving[hostname] = true
+		cmds = append(cmds, resolveHostName(hostname))
+	}
+	return tea.Batch(cmds...)
+}
+
+// resolveHostName looks up hostname in the background
+func resolveHostName(hostname string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+		defer cancel()
+		addrs, _ := lookupHost(ctx, hostname)
+		return resolvedMsg{hostname: hostname, addrs: addrs}
+	}
+}
+
+// matchesResolved reports whether term (lowercase) appears in an address the
+// entry's HostName resolved to
+func (m *ListModel) matchesResolved(entry *sshconfig.HostEntry, term string) bool {
+	for _, addr := range m.resolved[strings.ToLower(entry.HostName)] {
+		if strings.Contains(strings.ToLower(addr), term) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetResolved records the addresses hostname resolved to and refreshes an
+// active search, keeping the selected entry
+func (m *ListModel) SetResolved(hostname string, addrs []string) {
+	if len(addrs) == 0 {
+		return
+	}
+	if m.resolved == nil {
+		m.resolved = make(map[string][]string)
+	}
+	m.resolved[hostname] = addrs
+	if m.searchTerm == "" {
+		return
+	}
+
+	selected := m.GetSelected()
+	m.ApplyFilter()
+	for i, entry := range m.filtered {
+		if entry == selected {
+			m.selected = i
+			break
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+func TestListModel_SearchMatchesConnectionStringAndResolvedAddress(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "web", HostName: "web.example.com", User: "deploy"},
+		{Host: "db", HostName: "10.0.0.5"},
+	}
+	m := NewListModel(entries, map[string]int{})
+
+	m.SetSearchTerm("deploy@web")
+	if len(m.filtered) != 1 || m.filtered[0].Host != "web" {
+		t.Fatalf("Connection string search: got %d hosts", len(m.filtered))
+	}
+
+	m.SetSearchTerm("10.0.0")
+	if len(m.filtered) != 1 || m.filtered[0].Host != "db" {
+		t.Fatalf("IP HostName search: got %d hosts", len(m.filtered))
+	}
+
+	// Once web.example.com resolves, an active search picks it up
+	m.SetResolved("web.example.com", []string{"10.0.0.9"})
+	if len(m.filtered) != 2 {
+		t.Errorf("Resolved address should match the search, got %d hosts", len(m.filtered))
+	}
+}
+
+func TestModel_ResolvesHostNamesOnce(t *testing.T) {
+	original := lookupHost
+	t.Cleanup(func() { lookupHost = original })
+	var looked []string
+	lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		looked = append(looked, host)
+		return []string{"192.0.2.7"}, nil
+	}
+
+	m := newTestModel(t, "Host web\n    HostName web.example.com\n\nHost db\n    HostName 10.0.0.5\n\nHost *.internal\n    User admin\n")
+	if cmd := m.resolveHostNames(); cmd != nil {
+		t.Fatal("Nothing should resolve unless enabled")
+	}
+
+	m.SetResolveHosts(true)
+	cmd := m.resolveHostNames()
+	if cmd == nil {
+		t.Fatal("Expected a lookup for web.example.com")
+	}
+	m.Update(cmd())
+	if len(looked) != 1 || looked[0] != "web.example.com" {
+		t.Fatalf("Only DNS HostNames should be looked up, got %v", looked)
+	}
+	if cmd := m.resolveHostNames(); cmd != nil {
+		t.Error("A HostName should be looked up only once")
+	}
+
+	m.listModel.SetSearchTerm("192.0.2")
+	if m.listModel.GetSelected() == nil || m.listModel.GetSelected().Host != "web" {
+		t.Errorf("Search should find web by its resolved address")
+	}
+}
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// inputRow is one value in a RowsModel
+type inputRow struct {
+	kind  int // Index into RowsModel.kinds
+	input textinput.Model
+}
+
+// RowsModel edits a list of single-line values, one row each, such as a
+// host's port forwards or SetEnv variables. Rows may have a kind shown as a
+// label in front of them (Local/Remote for forwards). There's always at least
+// one (possibly empty) row to type into.
+type RowsModel struct {
+	rows        []inputRow
+	kinds       []string // Row labels, empty for a plain list
+	placeholder string
+	selected    int
+	focused     bool
+	width       int
+}
+
+// NewRowsModel creates an empty list whose rows can be any of kinds
+func NewRowsModel(placeholder string, kinds ...string) *RowsModel {
+	m := &RowsModel{kinds: kinds, placeholder: placeholder}
+	m.SetRows()
+	return m
+}
+
+// SetRows replaces the rows with values, one slice per kind in order
+func (m *RowsModel) SetRows(values ...[]string) {
+	m.rows = nil
+	for kind, kindValues := range values {
+		for _, value := range kindValues {
+			m.rows = append(m.rows, m.newRow(kind, value))
+		}
+	}
+	if len(m.rows) == 0 {
+		m.rows = append(m.rows, m.newRow(0, ""))
+	}
+	m.selected = 0
+	m.updateFocus()
+}
+
+// Values returns the trimmed, non-empty values, one slice per kind (a single
+// slice for a plain list)
+func (m *RowsModel) Values() [][]string {
+	values := make([][]string, max(len(m.kinds), 1))
+	for _, row := range m.rows {
+		if value := strings.TrimSpace(row.input.Value()); value != "" {
+			values[row.kind] = append(values[row.kind], value)
+		}
+	}
+	return values
+}
+
+// newRow returns a row of the given kind holding value
+func (m *RowsModel) newRow(kind int, value string) inputRow {
+	input := textinput.New()
+	input.Placeholder = m.placeholder
+	input.Width = m.width
+	input.SetValue(value)
+	return inputRow{kind: kind, input: input}
+}
+
+// Focus gives the selected row the cursor
+func (m *RowsModel) Focus() {
+	m.focused = true
+	m.updateFocus()
+}
+
+// Blur removes the cursor from every row
+func (m *RowsModel) Blur() {
+	m.focused = false
+	m.updateFocus()
+}
+
+// SetWidth sets the width of the row inputs
+func (m *RowsModel) SetWidth(width int) {
+	m.width = width
+	for i := range m.rows {
+		m.rows[i].input.Width = width
+	}
+}
+
+// updateFocus focuses the selected row's input while the list has focus
+func (m *RowsModel) updateFocus() {
+	for i := range m.rows {
+		if m.focused && i == m.selected {
+			m.rows[i].input.Focus()
+		} else {
+			m.rows[i].input.Blur()
+		}
+	}
+}
+
+// Update handles keys while the list has focus: ↑/↓ move between rows,
+// Ctrl+N adds a row, Ctrl+X removes one, Ctrl+T switches the row's kind and
+// anything else edits the selected row
+func (m *RowsModel) Update(msg tea.Msg) tea.Cmd {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "up":
+			if m.selected > 0 {
+				m.selected--
+				m.updateFocus()
+			}
+			return nil
+		case "down":
+			if m.selected < len(m.rows)-1 {
+				m.selected++
+				m.updateFocus()
+			}
+			return nil
+		case "ctrl+n":
+			// New row after the selected one, of the same kind
+			row := m.newRow(m.rows[m.selected].kind, "")
+			m.selected++
+			m.rows = append(m.rows[:m.selected], append([]inputRow{row}, m.rows[m.selected:]...)...)
+			m.updateFocus()
+			return nil
+		case "ctrl+x":
+			m.rows = append(m.rows[:m.selected], m.rows[m.selected+1:]...)
+			if len(m.rows) == 0 {
+				m.rows = append(m.rows, m.newRow(0, ""))
+			}
+			m.selected = min(m.selected, len(m.rows)-1)
+			m.updateFocus()
+			return nil
+		case "ctrl+t":
+			if len(m.kinds) > 1 {
+				m.rows[m.selected].kind = (m.rows[m.selected].kind + 1) % len(m.kinds)
+			}
+			return nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.rows[m.selected].input, cmd = m.rows[m.selected].input.Update(msg)
+	return cmd
+}
+
+// View renders one line per row, prefixed with its kind
+func (m *RowsModel) View() string {
+	var lines []string
+	for _, row := range m.rows {
+		line := row.input.View()
+		if len(m.kinds) > 0 {
+			line = labelStyle.Render(m.kinds[row.kind]) + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"bytes"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+	"github.com/nicklasos/gosshit/internal/storage"
+)
+
+// SortMode controls the order hosts are listed in
+type SortMode int
+
+const (
+	SortByVisits SortMode = iota
+	SortAlphabetical
+	SortConfigOrder
+	SortByDomain
+	SortByRecent
+	sortModeCount
+)
+
+// String returns a short label for the sort mode
+func (s SortMode) String() string {
+	switch s {
+	case SortAlphabetical:
+		return "alphabetical"
+	case SortConfigOrder:
+		return "config file order"
+	case SortByDomain:
+		return "hostname"
+	case SortByRecent:
+		return "recently used"
+	default:
+		return "visits"
+	}
+}
+
+// Next returns the sort mode that follows s, wrapping around
+func (s SortMode) Next() SortMode {
+	return (s + 1) % sortModeCount
+}
+
+// ParseSortMode returns the sort mode whose String() is name
+func ParseSortMode(name string) (SortMode, bool) {
+	for mode := SortMode(0); mode < sortModeCount; mode++ {
+		if mode.String() == name {
+			return mode, true
+		}
+	}
+	return SortByVisits, false
+}
+
+// sortEntriesDirected sorts entries like sortEntries, then reverses the
+// result when reverse is set (e.g. least visited first)
+func sortEntriesDirected(entries []*sshconfig.HostEntry, mode SortMode, reverse bool, tracker *storage.VisitTracker) []*sshconfig.HostEntry {
+	sorted := sortEntries(entries, mode, tracker)
+	if reverse {
+		slices.Reverse(sorted)
+	}
+	return sorted
+}
+
+// sortEntries returns entries ordered according to mode. entries must be in
+// config file order; the slice itself is not modified.
+func sortEntries(entries []*sshconfig.HostEntry, mode SortMode, tracker *storage.VisitTracker) []*sshconfig.HostEntry {
+	switch mode {
+	case SortAlphabetical:
+		sorted := make([]*sshconfig.HostEntry, len(entries))
+		copy(sorted, entries)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return strings.ToLower(sorted[i].Host) < strings.ToLower(sorted[j].Host)
+		})
+		return sorted
+	case SortByDomain:
+		sorted := make([]*sshconfig.HostEntry, len(entries))
+		copy(sorted, entries)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return compareHostNames(entryHostName(sorted[i]), entryHostName(sorted[j])) < 0
+		})
+		return sorted
+	case SortConfigOrder:
+		sorted := make([]*sshconfig.HostEntry, len(entries))
+		copy(sorted, entries)
+		return sorted
+	case SortByRecent:
+		return sshconfig.SortByHosts(entries, tracker.SortByRecent(sshconfig.HostNames(entries)))
+	default:
+		return sshconfig.SortByHosts(entries, tracker.SortByVisits(sshconfig.HostNames(entries)))
+	}
+}
+
+// entryHostName returns the name ssh will connect to: HostName, or the alias
+// when no HostName is set
+func entryHostName(entry *sshconfig.HostEntry) string {
+	if entry.HostName != "" {
+		return entry.HostName
+	}
+	return entry.Host
+}
+
+// compareHostNames orders hostnames so hosts in the same domain are grouped:
+// labels are compared right to left (com < example < www), so every
+// *.example.com sorts together. IP addresses sort after names, numerically.
+func compareHostNames(a, b string) int {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	switch {
+	case ipA != nil && ipB != nil:
+		return bytes.Compare(ipA.To16(), ipB.To16())
+	case ipA != nil:
+		return 1
+	case ipB != nil:
+		return -1
+	}
+
+	labelsA := strings.Split(strings.ToLower(strings.TrimSuffix(a, ".")), ".")
+	labelsB := strings.Split(strings.ToLower(strings.TrimSuffix(b, ".")), ".")
+	for i, j := len(labelsA)-1, len(labelsB)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if c := strings.Compare(labelsA[i], labelsB[j]); c != 0 {
+			return c
+		}
+	}
+	// A parent domain sorts before its subdomains
+	return len(labelsA) - len(labelsB)
+}
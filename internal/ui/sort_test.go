@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+func TestSortEntries_ByDomain(t *testing.T) {
+	entries := []*sshconfig.HostEntry{
+		{Host: "web", HostName: "www.example.com"},
+		{Host: "ip2", HostName: "10.0.0.10"},
+		{Host: "db", HostName: "db.other.org"},
+		{Host: "apex", HostName: "example.com"},
+		{Host: "api", HostName: "API.example.com"},
+		{Host: "ip1", HostName: "10.0.0.9"},
+		{Host: "mail.example.net"},
+		{Host: "deep", HostName: "a.b.example.com"},
+	}
+
+	sorted := sortEntries(entries, SortByDomain, nil)
+
+	want := []string{"apex", "api", "deep", "web", "mail.example.net", "db", "ip1", "ip2"}
+	if len(sorted) != len(want) {
+		t.Fatalf("Got %d entries, want %d", len(sorted), len(want))
+	}
+	for i, host := range want {
+		if sorted[i].Host != host {
+			t.Errorf("Position %d: got %q, want %q", i, sorted[i].Host, host)
+		}
+	}
+
+	// The input slice keeps config file order
+	if entries[0].Host != "web" {
+		t.Errorf("sortEntries modified its input: first entry is %q", entries[0].Host)
+	}
+}
+
+func TestCompareHostNames(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int // sign only
+	}{
+		{"a.example.com", "b.example.com", -1},
+		{"z.example.com", "a.example.org", -1},
+		{"example.com", "www.example.com", -1},
+		{"Example.COM", "example.com.", 0},
+		{"host.example.com", "192.168.1.1", -1},
+		{"10.0.0.2", "10.0.0.10", -1},
+	}
+
+	for _, tt := range tests {
+		got := compareHostNames(tt.a, tt.b)
+		if sign(got) != tt.want {
+			t.Errorf("compareHostNames(%q, %q) = %d, want sign %d", tt.a, tt.b, got, tt.want)
+		}
+		if sign(compareHostNames(tt.b, tt.a)) != -tt.want {
+			t.Errorf("compareHostNames(%q, %q) is not antisymmetric", tt.b, tt.a)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+func TestParseSortMode(t *testing.T) {
+	for mode := SortMode(0); mode < sortModeCount; mode++ {
+		if got, ok := ParseSortMode(mode.String()); !ok || got != mode {
+			t.Errorf("ParseSortMode(%q) = %v, %v", mode.String(), got, ok)
+		}
+	}
+	if got, ok := ParseSortMode(""); ok || got != SortByVisits {
+		t.Errorf("ParseSortMode(\"\") = %v, %v; want visits, false", got, ok)
+	}
+}
+
+func TestPinFirst(t *testing.T) {
+	entries := []*sshconfig.HostEntry{{Host: "a"}, {Host: "b"}, {Host: "c"}, {Host: "d"}}
+
+	got := pinFirst(entries, []string{"c", "removed", "a"})
+	var hosts []string
+	for _, e := range got {
+		hosts = append(hosts, e.Host)
+	}
+	if strings.Join(hosts, ",") != "c,a,b,d" {
+		t.Errorf("pinFirst = %v, want c,a,b,d", hosts)
+	}
+	if entries[0].Host != "a" {
+		t.Errorf("pinFirst modified its input: first entry is %q", entries[0].Host)
+	}
+}
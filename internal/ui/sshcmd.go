@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// sshBinaryEnv overrides the ssh binary used to connect (e.g. a wrapper or an absolute path)
+	sshBinaryEnv = "GOSSHIT_SSH"
+	// sshArgsEnv holds extra arguments passed before the host (e.g. "-v")
+	sshArgsEnv = "GOSSHIT_SSH_ARGS"
+)
+
+// sshCommandPrefix returns the ssh binary and extra arguments configured via
+// GOSSHIT_SSH and GOSSHIT_SSH_ARGS, falling back to plain "ssh"
+func sshCommandPrefix() ([]string, error) {
+	binary := strings.TrimSpace(os.Getenv(sshBinaryEnv))
+	if binary == "" {
+		binary = "ssh"
+	}
+
+	extra, err := splitArgs(os.Getenv(sshArgsEnv))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", sshArgsEnv, err)
+	}
+	return append([]string{binary}, extra...), nil
+}
+
+// editorCommand returns the command line of the user's editor ($VISUAL, then
+// $EDITOR, falling back to vi) opening path
+func editorCommand(path string) ([]string, error) {
+	editor := strings.TrimSpace(os.Getenv("VISUAL"))
+	if editor == "" {
+		editor = strings.TrimSpace(os.Getenv("EDITOR"))
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	args, err := splitArgs(editor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid editor %q: %w", editor, err)
+	}
+	return append(args, path), nil
+}
+
+// splitArgs splits s into arguments like a POSIX shell would for plain words:
+// whitespace separates arguments, single quotes are literal, double quotes
+// allow backslash escapes, and a backslash outside quotes escapes the next
+// character
+func splitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inArg = true
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
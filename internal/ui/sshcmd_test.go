@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "", want: nil},
+		{in: "  -v  ", want: []string{"-v"}},
+		{in: "-v -o ServerAliveInterval=30", want: []string{"-v", "-o", "ServerAliveInterval=30"}},
+		{in: `-o "ProxyCommand nc %h %p"`, want: []string{"-o", "ProxyCommand nc %h %p"}},
+		{in: `-i '/path/with space/key'`, want: []string{"-i", "/path/with space/key"}},
+		{in: `a\ b "c \"d\"" ''`, want: []string{"a b", `c "d"`, ""}},
+		{in: `-o "unterminated`, wantErr: true},
+		{in: `trailing\`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := splitArgs(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("splitArgs(%q): expected an error", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("splitArgs(%q): unexpected error %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSSHCommandPrefix(t *testing.T) {
+	t.Setenv(sshBinaryEnv, "")
+	t.Setenv(sshArgsEnv, "")
+	if got, err := sshCommandPrefix(); err != nil || !reflect.DeepEqual(got, []string{"ssh"}) {
+		t.Errorf("Default: got %q, %v", got, err)
+	}
+
+	t.Setenv(sshBinaryEnv, "/usr/local/bin/ssh")
+	t.Setenv(sshArgsEnv, `-v -o "LogLevel DEBUG"`)
+	want := []string{"/usr/local/bin/ssh", "-v", "-o", "LogLevel DEBUG"}
+	if got, err := sshCommandPrefix(); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Configured: got %q, %v, want %q", got, err, want)
+	}
+
+	t.Setenv(sshArgsEnv, `"-v`)
+	if _, err := sshCommandPrefix(); err == nil {
+		t.Error("Expected an error for unbalanced quotes")
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if got, err := editorCommand("/tmp/config"); err != nil || !reflect.DeepEqual(got, []string{"vi", "/tmp/config"}) {
+		t.Errorf("Fallback: got %q, %v", got, err)
+	}
+
+	t.Setenv("EDITOR", "code --wait")
+	if got, err := editorCommand("/tmp/config"); err != nil || !reflect.DeepEqual(got, []string{"code", "--wait", "/tmp/config"}) {
+		t.Errorf("EDITOR with args: got %q, %v", got, err)
+	}
+
+	t.Setenv("VISUAL", "nvim")
+	if got, err := editorCommand("/tmp/config"); err != nil || !reflect.DeepEqual(got, []string{"nvim", "/tmp/config"}) {
+		t.Errorf("VISUAL takes precedence: got %q, %v", got, err)
+	}
+}
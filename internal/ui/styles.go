@@ -12,104 +12,192 @@ var (
 	selectColor  = lipgloss.Color("4")  // Blue for selection
 	subtleColor  = lipgloss.Color("8")  // Dark gray for subtle text
 	warningColor = lipgloss.Color("3")  // Yellow for warnings
+	successColor = lipgloss.Color("2")  // Green for success
 	errorColor   = lipgloss.Color("1")  // Red for errors
 
+	// Colors custom tags are picked from by name: mid-tones that stay
+	// readable on dark and light backgrounds
+	tagPalette = []lipgloss.Color{"33", "37", "71", "97", "104", "133", "137", "166", "169", "172", "31", "140"}
+)
+
+// Styles, built from the colors above by buildStyles
+var (
+	panelStyle            lipgloss.Style
+	listPanelStyle        lipgloss.Style
+	detailPanelStyle      lipgloss.Style
+	titleStyle            lipgloss.Style
+	labelStyle            lipgloss.Style
+	valueStyle            lipgloss.Style
+	selectedStyle         lipgloss.Style
+	listItemStyle         lipgloss.Style
+	listItemSelectedStyle lipgloss.Style
+	statusBarStyle        lipgloss.Style
+	statusBarModeStyle    lipgloss.Style
+	inputStyle            lipgloss.Style
+	inputFocusedStyle     lipgloss.Style
+	helpStyle             lipgloss.Style
+	errorStyle            lipgloss.Style
+	warningStyle          lipgloss.Style
+	successStyle          lipgloss.Style
+	helpSectionStyle      lipgloss.Style
+	helpKeyStyle          lipgloss.Style
+	diffAddStyle          lipgloss.Style
+	diffRemoveStyle       lipgloss.Style
+	diffHunkStyle         lipgloss.Style
+	diffHeaderStyle       lipgloss.Style
+	tagProdStyle          lipgloss.Style
+	tagDevStyle           lipgloss.Style
+	tagStageStyle         lipgloss.Style
+	tagStyles             []lipgloss.Style // One per tagPalette color
+	patternBadgeStyle     lipgloss.Style
+	groupHeaderStyle      lipgloss.Style
+	searchMatchStyle      lipgloss.Style
+)
+
+func init() {
+	buildStyles()
+}
+
+// buildStyles (re)creates every style from the current colors, e.g. after a
+// theme was loaded
+func buildStyles() {
 	// Panel styles
 	panelStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(accentColor).
-			Padding(1, 2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accentColor).
+		Padding(1, 2)
 
 	listPanelStyle = panelStyle.Copy().
-			Width(40).
-			Height(20)
+		Width(40).
+		Height(20)
 
 	detailPanelStyle = panelStyle.Copy().
-				Width(50).
-				Height(20)
+		Width(50).
+		Height(20)
 
 	// Text styles
 	titleStyle = lipgloss.NewStyle().
-			Foreground(accentColor).
-			Bold(true).
-			MarginBottom(1)
+		Foreground(accentColor).
+		Bold(true).
+		MarginBottom(1)
 
 	labelStyle = lipgloss.NewStyle().
-			Foreground(subtleColor).
-			MarginRight(1)
+		Foreground(subtleColor).
+		MarginRight(1)
 
 	valueStyle = lipgloss.NewStyle().
-			Foreground(fgColor)
+		Foreground(fgColor)
 
 	selectedStyle = lipgloss.NewStyle().
-			Foreground(selectColor).
-			Bold(true).
-			Background(lipgloss.Color("8"))
+		Foreground(selectColor).
+		Bold(true).
+		Background(subtleColor)
 
 	// List item styles
 	// Reserve space for the border even when not selected to prevent shifting
 	// Selected: border (1 char) + padding (1 char) = 2 chars total
 	// Non-selected: padding (2 chars) to match total width
 	listItemStyle = lipgloss.NewStyle().
-			Foreground(fgColor).
-			PaddingLeft(2) // Match border + padding of selected items
+		Foreground(fgColor).
+		PaddingLeft(2) // Match border + padding of selected items
 
 	listItemSelectedStyle = lipgloss.NewStyle().
-				Foreground(accentColor).
-				Border(lipgloss.NormalBorder()).
-				BorderForeground(accentColor).
-				BorderLeft(true).
-				BorderRight(false).
-				BorderTop(false).
-				BorderBottom(false).
-				PaddingLeft(1) // Space after border
+		Foreground(accentColor).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(accentColor).
+		BorderLeft(true).
+		BorderRight(false).
+		BorderTop(false).
+		BorderBottom(false).
+		PaddingLeft(1) // Space after border
 
 	// Status bar style
 	statusBarStyle = lipgloss.NewStyle().
-			Foreground(subtleColor).
-			Background(bgColor).
-			Padding(0, 1)
+		Foreground(subtleColor).
+		Background(bgColor).
+		Padding(0, 1)
 
 	statusBarModeStyle = lipgloss.NewStyle().
-				Foreground(accentColor).
-				Bold(true)
+		Foreground(accentColor).
+		Bold(true)
 
 	// Editor styles
 	inputStyle = lipgloss.NewStyle().
-			Foreground(fgColor).
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(accentColor).
-			Padding(0, 1)
+		Foreground(fgColor).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accentColor).
+		Padding(0, 1)
 
 	inputFocusedStyle = lipgloss.NewStyle().
-				Foreground(fgColor).
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(selectColor).
-				BorderForeground(lipgloss.Color("4")).
-				Padding(0, 1)
+		Foreground(fgColor).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(selectColor).
+		Padding(0, 1)
 
 	helpStyle = lipgloss.NewStyle().
-			Foreground(subtleColor).
-			MarginTop(1)
+		Foreground(subtleColor).
+		MarginTop(1)
 
 	// Error/warning styles
 	errorStyle = lipgloss.NewStyle().
-			Foreground(errorColor).
-			Bold(true)
+		Foreground(errorColor).
+		Bold(true)
 
 	warningStyle = lipgloss.NewStyle().
-			Foreground(warningColor)
+		Foreground(warningColor)
+
+	successStyle = lipgloss.NewStyle().
+		Foreground(successColor)
+
+	// Help overlay styles
+	helpSectionStyle = lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true)
+
+	helpKeyStyle = lipgloss.NewStyle().
+		Foreground(warningColor).
+		MarginRight(1)
+
+	// Diff preview styles
+	diffAddStyle = lipgloss.NewStyle().
+		Foreground(successColor)
+
+	diffRemoveStyle = lipgloss.NewStyle().
+		Foreground(errorColor)
+
+	diffHunkStyle = lipgloss.NewStyle().
+		Foreground(accentColor)
+
+	diffHeaderStyle = lipgloss.NewStyle().
+		Foreground(subtleColor)
 
 	// Tag badge styles
 	tagProdStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("1")) // Red
+		Foreground(errorColor)
 
 	tagDevStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("2")) // Green
+		Foreground(successColor)
 
 	tagStageStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("3")) // Yellow
-
-	tagDefaultStyle = lipgloss.NewStyle().
-			Foreground(subtleColor)
-)
+		Foreground(warningColor)
+
+	tagStyles = make([]lipgloss.Style, len(tagPalette))
+	for i, color := range tagPalette {
+		tagStyles[i] = lipgloss.NewStyle().
+			Foreground(color)
+	}
+
+	patternBadgeStyle = lipgloss.NewStyle().
+		Foreground(accentColor).
+		Italic(true)
+
+	groupHeaderStyle = lipgloss.NewStyle().
+		Foreground(warningColor).
+		Bold(true)
+
+	// Part of a host matching the search term
+	searchMatchStyle = lipgloss.NewStyle().
+		Foreground(bgColor).
+		Background(warningColor).
+		Bold(true)
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+// TagPickerModel represents the tag filter picker
+type TagPickerModel struct {
+	tags     []string
+	checked  map[string]bool
+	selected int
+	width    int
+	height   int
+}
+
+// NewTagPickerModel creates a new tag picker model
+func NewTagPickerModel() *TagPickerModel {
+	return &TagPickerModel{
+		checked: make(map[string]bool),
+	}
+}
+
+// Open lists the distinct tags of entries, with the active filter tags checked
+func (m *TagPickerModel) Open(entries []*sshconfig.HostEntry, active []string) {
+	var all []string
+	for _, entry := range entries {
+		all = append(all, entry.Tags...)
+	}
+	m.tags = sshconfig.UniqueTags(all)
+	sort.SliceStable(m.tags, func(i, j int) bool {
+		return strings.ToLower(m.tags[i]) < strings.ToLower(m.tags[j])
+	})
+
+	m.checked = make(map[string]bool)
+	for _, tag := range active {
+		m.checked[strings.ToLower(tag)] = true
+	}
+	m.selected = 0
+}
+
+// Checked returns the checked tags in display order
+func (m *TagPickerModel) Checked() []string {
+	var tags []string
+	for _, tag := range m.tags {
+		if m.checked[strings.ToLower(tag)] {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// Update handles updates to the tag picker
+func (m *TagPickerModel) Update(msg tea.Msg) (*TagPickerModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "j", "down":
+		if m.selected < len(m.tags)-1 {
+			m.selected++
+		}
+	case "k", "up":
+		if m.selected > 0 {
+			m.selected--
+		}
+	case " ", "x":
+		if m.selected < len(m.tags) {
+			key := strings.ToLower(m.tags[m.selected])
+			m.checked[key] = !m.checked[key]
+		}
+	case "c":
+		m.checked = make(map[string]bool)
+	}
+	return m, nil
+}
+
+// SetSize sets the size of the picker
+func (m *TagPickerModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// View renders the tag picker
+func (m *TagPickerModel) View() string {
+	var lines []string
+	lines = append(lines, titleStyle.Render("Filter by Tags"))
+
+	if len(m.tags) == 0 {
+		lines = append(lines, valueStyle.Foreground(subtleColor).Render("No tags in the config"))
+		lines = append(lines, "")
+		lines = append(lines, helpStyle.Render("Esc: close"))
+	} else {
+		visibleCount := max(1, min(m.height-6, len(m.tags))) // Account for title, padding, help text
+		start := max(0, min(m.selected-visibleCount/2, len(m.tags)-visibleCount))
+		end := min(len(m.tags), start+visibleCount)
+
+		for i := start; i < end; i++ {
+			box := "[ ] "
+			if m.checked[strings.ToLower(m.tags[i])] {
+				box = "[x] "
+			}
+			line := box + formatTagBadge(m.tags[i])
+			if i == m.selected {
+				lines = append(lines, listItemSelectedStyle.Render(line))
+			} else {
+				lines = append(lines, listItemStyle.Render(line))
+			}
+		}
+
+		lines = append(lines, "")
+		lines = append(lines, helpStyle.Render("j/k: navigate | Space: toggle | c: clear | Enter: apply | Esc: cancel"))
+	}
+
+	content := strings.Join(lines, "\n")
+	return detailPanelStyle.Copy().
+		Width(m.width).
+		Height(m.height).
+		Render(content)
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// themeColors maps the keys of theme.toml to the colors they override
+var themeColors = map[string]*lipgloss.Color{
+	"bg":      &bgColor,
+	"fg":      &fgColor,
+	"accent":  &accentColor,
+	"select":  &selectColor,
+	"subtle":  &subtleColor,
+	"warning": &warningColor,
+	"success": &successColor,
+	"error":   &errorColor,
+}
+
+// ThemePath returns the location of the optional theme file:
+// $XDG_CONFIG_HOME/gosshit/theme.toml, or ~/.config/gosshit/theme.toml
+func ThemePath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "gosshit", "theme.toml"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "gosshit", "theme.toml"), nil
+}
+
+// LoadTheme overrides the default colors with those set in the theme file at
+// path and rebuilds the styles. A missing file keeps the defaults, as does
+// any color the file doesn't set. Call it before creating the model.
+//
+// The file uses flat TOML key/value pairs, with ANSI color numbers or hex
+// values:
+//
+//	accent = "#005f87"
+//	subtle = "244"
+func LoadTheme(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open theme: %w", err)
+	}
+	defer file.Close()
+
+	colors := make(map[string]lipgloss.Color)
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("%s:%d: expected key = \"value\"", path, lineNum)
+		}
+		key = strings.TrimSpace(key)
+		if _, known := themeColors[key]; !known {
+			return fmt.Errorf("%s:%d: unknown color %q", path, lineNum, key)
+		}
+		value, err := parseThemeValue(value)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, lineNum, err)
+		}
+		colors[key] = lipgloss.Color(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read theme: %w", err)
+	}
+
+	for key, color := range colors {
+		*themeColors[key] = color
+	}
+	buildStyles()
+	return nil
+}
+
+// parseThemeValue returns the color of a TOML value: a quoted string or a
+// bare ANSI color number, optionally followed by a comment
+func parseThemeValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if quote := value[:min(1, len(value))]; quote == `"` || quote == "'" {
+		end := strings.Index(value[1:], quote)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		rest := strings.TrimSpace(value[end+2:])
+		if rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected %q after value", rest)
+		}
+		value = value[1 : end+1]
+	} else {
+		value, _, _ = strings.Cut(value, "#")
+		value = strings.TrimSpace(value)
+	}
+	if value == "" {
+		return "", fmt.Errorf("empty color")
+	}
+	return value, nil
+}
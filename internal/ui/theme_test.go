@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// restoreTheme puts the default colors and styles back after a test
+func restoreTheme(t *testing.T) {
+	t.Helper()
+	saved := make(map[string]lipgloss.Color)
+	for key, color := range themeColors {
+		saved[key] = *color
+	}
+	t.Cleanup(func() {
+		for key, color := range saved {
+			*themeColors[key] = color
+		}
+		buildStyles()
+	})
+}
+
+func TestLoadTheme(t *testing.T) {
+	restoreTheme(t)
+	path := filepath.Join(t.TempDir(), "theme.toml")
+	content := "# light terminal\nsubtle = \"244\"\naccent = '#005f87' # blue\nerror = 9\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write theme: %v", err)
+	}
+
+	if err := LoadTheme(path); err != nil {
+		t.Fatalf("LoadTheme failed: %v", err)
+	}
+	if subtleColor != "244" || accentColor != "#005f87" || errorColor != "9" {
+		t.Errorf("Colors not applied: subtle %q accent %q error %q", subtleColor, accentColor, errorColor)
+	}
+	if fgColor != "15" {
+		t.Errorf("Colors missing from the theme should keep their defaults, got fg %q", fgColor)
+	}
+	if got := helpStyle.GetForeground(); got != lipgloss.Color("244") {
+		t.Errorf("Styles should be rebuilt with the theme, got %v", got)
+	}
+}
+
+func TestLoadTheme_MissingFile(t *testing.T) {
+	restoreTheme(t)
+	if err := LoadTheme(filepath.Join(t.TempDir(), "theme.toml")); err != nil {
+		t.Errorf("A missing theme should keep the defaults, got %v", err)
+	}
+	if accentColor != "6" {
+		t.Errorf("Expected the default accent, got %q", accentColor)
+	}
+}
+
+func TestLoadTheme_Invalid(t *testing.T) {
+	restoreTheme(t)
+	for _, content := range []string{"accent", "accnet = \"6\"", "accent = \"6", "accent = \"\"", "accent = \"6\" blue"} {
+		path := filepath.Join(t.TempDir(), "theme.toml")
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write theme: %v", err)
+		}
+		if err := LoadTheme(path); err == nil {
+			t.Errorf("Expected an error for %q", content)
+		}
+	}
+	if accentColor != "6" {
+		t.Errorf("A bad theme should not change any color, got accent %q", accentColor)
+	}
+}
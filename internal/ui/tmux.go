@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// tmuxSessionName converts a host alias into a valid tmux session name
+// (tmux doesn't allow '.' or ':' in session names)
+func tmuxSessionName(host string) string {
+	return strings.NewReplacer(".", "_", ":", "_", " ", "_").Replace(host)
+}
+
+// tmuxAvailable reports whether the tmux binary can be found in PATH
+func tmuxAvailable() bool {
+	_, err := exec.LookPath("tmux")
+	return err == nil
+}
+
+// tmuxCommand builds the command that attaches to (or creates) a per-host tmux
+// session running sshCmd (the full ssh command line). Inside an existing tmux
+// client the session is created detached and the client is switched to it,
+// to avoid nesting tmux.
+func tmuxCommand(host string, sshCmd []string) *exec.Cmd {
+	session := tmuxSessionName(host)
+
+	if os.Getenv("TMUX") == "" {
+		return exec.Command("tmux", append([]string{"new-session", "-A", "-s", session}, sshCmd...)...)
+	}
+
+	if exec.Command("tmux", "has-session", "-t", "="+session).Run() != nil {
+		_ = exec.Command("tmux", append([]string{"new-session", "-d", "-s", session}, sshCmd...)...).Run()
+	}
+	return exec.Command("tmux", "switch-client", "-t", "="+session)
+}
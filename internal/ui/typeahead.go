@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeAheadKey starts a type-ahead jump; letters are list commands otherwise
+const typeAheadKey = "'"
+
+// typeAheadTimeout is how long the type-ahead waits for another key before it
+// ends and letters go back to being commands
+const typeAheadTimeout = 1500 * time.Millisecond
+
+// typeAheadTimeoutMsg ends the type-ahead unless another key arrived since
+type typeAheadTimeoutMsg struct {
+	seq int
+}
+
+// handleTypeAheadKey moves the cursor to the first host whose alias starts
+// with the text typed after ' (or, failing that, contains it), like the
+// type-ahead of file managers. Backspace edits the text, Esc ends the
+// type-ahead, and any other non-printable key ends it and is left unhandled
+// (so Enter connects to the host found).
+func (m *Model) handleTypeAheadKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	key := msg.String()
+	if !m.typeAhead {
+		if key != typeAheadKey {
+			return false, nil
+		}
+		m.typeAhead = true
+		m.typeAheadBuffer = ""
+		m.statusMsg = "Jump to: "
+		return true, m.typeAheadTick()
+	}
+
+	switch {
+	case msg.Type == tea.KeyRunes && !msg.Alt:
+		m.typeAheadBuffer += string(msg.Runes)
+	case msg.Type == tea.KeySpace:
+		m.typeAheadBuffer += " "
+	case msg.Type == tea.KeyBackspace:
+		if m.typeAheadBuffer != "" {
+			_, size := utf8.DecodeLastRuneInString(m.typeAheadBuffer)
+			m.typeAheadBuffer = m.typeAheadBuffer[:len(m.typeAheadBuffer)-size]
+		}
+	case msg.Type == tea.KeyEsc:
+		m.endTypeAhead()
+		return true, nil
+	default:
+		m.endTypeAhead()
+		return false, nil
+	}
+
+	m.statusMsg = "Jump to: " + m.typeAheadBuffer
+	if index := m.typeAheadMatch(m.typeAheadBuffer); index >= 0 {
+		m.listModel.SetSelected(index)
+		m.updateDetailView()
+	} else if m.typeAheadBuffer != "" {
+		m.statusMsg += " (no match)"
+	}
+	return true, m.typeAheadTick()
+}
+
+// typeAheadMatch returns the index of the first visible host with an alias
+// starting with text, else the first containing it (case-insensitive), or -1
+func (m *Model) typeAheadMatch(text string) int {
+	text = strings.ToLower(text)
+	if text == "" {
+		return -1
+	}
+	contains := -1
+	for i, entry := range m.listModel.filtered {
+		for _, alias := range entry.Aliases() {
+			alias = strings.ToLower(alias)
+			if strings.HasPrefix(alias, text) {
+				return i
+			}
+			if contains < 0 && strings.Contains(alias, text) {
+				contains = i
+			}
+		}
+	}
+	return contains
+}
+
+// typeAheadTick starts the idle timeout for the current type-ahead key
+func (m *Model) typeAheadTick() tea.Cmd {
+	m.typeAheadSeq++
+	seq := m.typeAheadSeq
+	return tea.Tick(typeAheadTimeout, func(time.Time) tea.Msg {
+		return typeAheadTimeoutMsg{seq: seq}
+	})
+}
+
+// endTypeAhead stops the type-ahead, clearing its status message
+func (m *Model) endTypeAhead() {
+	if strings.HasPrefix(m.statusMsg, "Jump to: ") {
+		m.statusMsg = ""
+	}
+	m.typeAhead = false
+	m.typeAheadBuffer = ""
+}
+
+// expireTypeAhead ends the type-ahead when its idle timeout fires
+func (m *Model) expireTypeAhead(msg typeAheadTimeoutMsg) {
+	if msg.seq != m.typeAheadSeq || !m.typeAhead {
+		return
+	}
+	m.endTypeAhead()
+}
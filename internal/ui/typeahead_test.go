@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModel_TypeAhead(t *testing.T) {
+	config := "Host alpha\n    HostName a.example.com\n\nHost beta\n    HostName b.example.com\n\nHost web-prod\n    HostName w.example.com\n"
+	m := newTestModel(t, config)
+	m.sortMode = SortAlphabetical
+	m.applySort()
+	m.selectHost("alpha")
+
+	selected := func() string {
+		if entry := m.listModel.GetSelected(); entry != nil {
+			return entry.Host
+		}
+		return ""
+	}
+
+	m.Update(keyRunes("'"))
+	m.Update(keyRunes("b"))
+	if got := selected(); got != "beta" {
+		t.Errorf("'b should jump to beta, got %s", got)
+	}
+	if m.mode != ModeList || m.listModel.searchTerm != "" {
+		t.Error("Type-ahead should not filter the list")
+	}
+
+	// Backspace and a substring match
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m.Update(keyRunes("prod"))
+	if got := selected(); got != "web-prod" {
+		t.Errorf("Expected a substring match on web-prod, got %s", got)
+	}
+	if m.statusMsg != "Jump to: prod" {
+		t.Errorf("Unexpected status: %q", m.statusMsg)
+	}
+
+	// The idle timeout ends it, and letters are commands again
+	m.Update(typeAheadTimeoutMsg{seq: m.typeAheadSeq})
+	if m.typeAhead || m.statusMsg != "" {
+		t.Errorf("Timeout should end the type-ahead, status %q", m.statusMsg)
+	}
+	m.Update(keyRunes("a"))
+	if m.mode != ModeAdd {
+		t.Errorf("a should add a host once the type-ahead ended, got mode %v", m.mode)
+	}
+}
+
+func TestModel_TypeAheadStaleTimeout(t *testing.T) {
+	m := newTestModel(t, "Host alpha\n    HostName a.example.com\n")
+
+	m.Update(keyRunes("'"))
+	stale := m.typeAheadSeq
+	m.Update(keyRunes("a"))
+	m.Update(typeAheadTimeoutMsg{seq: stale})
+	if !m.typeAhead {
+		t.Error("A timeout from before the last key should not end the type-ahead")
+	}
+
+	m.Update(keyMsgFor("esc"))
+	if m.typeAhead {
+		t.Error("Esc should end the type-ahead")
+	}
+}
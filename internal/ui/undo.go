@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+// undoState is the single level of undo: the config files as they were
+// before the last change made from the UI
+type undoState struct {
+	snapshots []*sshconfig.Snapshot // One per file the change wrote
+	label     string                // What the change was, e.g. "deleting 'web'"
+	host      string                // Host to select after undoing
+}
+
+// entryFile returns the config file that defines entry
+func (m *Model) entryFile(entry *sshconfig.HostEntry) string {
+	if entry.SourceFile != "" {
+		return entry.SourceFile
+	}
+	return m.configPath
+}
+
+// takeSnapshot captures path before a write. Undo is simply unavailable for
+// the write if the file can't be parsed.
+func (m *Model) takeSnapshot(path string) *sshconfig.Snapshot {
+	snapshot, err := sshconfig.TakeSnapshot(path)
+	if err != nil {
+		return nil
+	}
+	return snapshot
+}
+
+// rememberUndo makes a successful write undoable, replacing the previous one
+func (m *Model) rememberUndo(snapshot *sshconfig.Snapshot, label, host string) {
+	m.rememberUndoAll([]*sshconfig.Snapshot{snapshot}, label, host)
+}
+
+// rememberUndoAll is rememberUndo for a change that wrote several files
+func (m *Model) rememberUndoAll(snapshots []*sshconfig.Snapshot, label, host string) {
+	for _, snapshot := range snapshots {
+		if snapshot == nil || snapshot.Written() != nil {
+			m.undo = nil
+			return
+		}
+	}
+	m.undo = &undoState{snapshots: snapshots, label: label, host: host}
+}
+
+// undoLast restores the config file from before the last change. It refuses
+// if a file was edited outside gosshit since, as restoring would lose that edit.
+func (m *Model) undoLast() (tea.Model, tea.Cmd) {
+	if m.undo == nil {
+		m.statusMsg = "Nothing to undo"
+		return m, nil
+	}
+
+	undo := m.undo
+	for _, snapshot := range undo.snapshots {
+		if err := snapshot.Check(); err != nil {
+			m.undo = nil
+			if errors.Is(err, sshconfig.ErrConfigChanged) {
+				m.statusMsg = fmt.Sprintf("Can't undo %s: %s changed on disk since", undo.label, snapshot.Path)
+			} else {
+				m.statusMsg = err.Error()
+			}
+			return m, nil
+		}
+	}
+
+	var paths []string
+	for _, snapshot := range undo.snapshots {
+		if err := snapshot.Restore(); err != nil {
+			m.statusMsg = err.Error()
+			return m, nil
+		}
+		paths = append(paths, snapshot.Path)
+	}
+	m.undo = nil
+
+	if err := m.reloadEntries(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.selectHost(undo.host)
+	m.updateDetailView()
+	m.statusMsg = fmt.Sprintf("Undid %s (restored %s)", undo.label, strings.Join(paths, ", "))
+	return m, nil
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"maps"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nicklasos/gosshit/internal/sshconfig"
+)
+
+// watchInterval is how often the config files are checked for changes. A change
+// is only reloaded once the file looks the same on two checks in a row, so a
+// burst of writes (an editor saving in several steps) reloads once.
+const watchInterval = time.Second
+
+// watchTickMsg asks for the config files to be checked again
+type watchTickMsg struct{}
+
+// fileStamp identifies a version of a file well enough to notice edits
+type fileStamp struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// statFile returns the stamp of path; a missing file has the zero stamp
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{exists: true, modTime: info.ModTime(), size: info.Size()}
+}
+
+// configStamps holds the stamps of the config file and of the included files
+// its hosts come from, keyed by path
+type configStamps map[string]fileStamp
+
+// stampConfig returns configStamps for configPath, whose stamp was taken
+// before parsing it into entries, and for the other files entries came from.
+// Included files without any hosts aren't watched.
+func stampConfig(configPath string, stamp fileStamp, entries []*sshconfig.HostEntry) configStamps {
+	stamps := configStamps{configPath: stamp}
+	for _, entry := range entries {
+		if _, ok := stamps[entry.SourceFile]; !ok && entry.SourceFile != "" {
+			stamps[entry.SourceFile] = statFile(entry.SourceFile)
+		}
+	}
+	return stamps
+}
+
+// restat returns fresh stamps of the same files
+func (s configStamps) restat() configStamps {
+	stamps := make(configStamps, len(s))
+	for path := range s {
+		stamps[path] = statFile(path)
+	}
+	return stamps
+}
+
+// SetWatchConfig makes the list reload by itself when the config file, or a
+// file it includes hosts from, changes on disk
+func (m *Model) SetWatchConfig(enabled bool) {
+	m.watchConfig = enabled
+}
+
+// watchTick schedules the next check of the config files
+func (m *Model) watchTick() tea.Cmd {
+	if !m.watchConfig {
+		return nil
+	}
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		return watchTickMsg{}
+	})
+}
+
+// checkConfigChanged reloads the config once one of its files has changed on
+// disk and settled. gosshit's own writes are reloaded right away, which records
+// the new stamps, so they never look like outside changes. Nothing is reloaded while a
+// dialog or the editor is open; the change is picked up when back in the list.
+func (m *Model) checkConfigChanged() (tea.Model, tea.Cmd) {
+	stamp := m.configStamp.restat()
+	if maps.Equal(stamp, m.configStamp) {
+		m.pendingStamp = nil
+		return m, m.watchTick()
+	}
+	if m.pendingStamp == nil || !maps.Equal(m.pendingStamp, stamp) || m.mode != ModeList {
+		m.pendingStamp = stamp
+		return m, m.watchTick()
+	}
+
+	m.pendingStamp = nil
+	previous := m.configStamp
+	model, cmd := m.reloadConfig()
+	if maps.Equal(m.configStamp, previous) {
+		// Unreadable or unparsable: don't retry until it changes again
+		m.configStamp = stamp
+	} else {
+		m.statusMsg = "Config changed on disk, reloaded"
+	}
+	return model, tea.Batch(cmd, m.watchTick())
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestModel_WatchReloadsSettledChanges(t *testing.T) {
+	m := newTestModel(t, "Host alpha\n    HostName a.example.com\n")
+	m.SetWatchConfig(true)
+
+	m.Update(watchTickMsg{})
+	if m.pendingStamp != nil || len(m.entries) != 1 {
+		t.Fatal("An unchanged file should not be reloaded")
+	}
+
+	config := "Host alpha\n    HostName a.example.com\n\nHost beta\n    HostName b.example.com\n"
+	if err := os.WriteFile(m.configPath, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Make the change visible even on filesystems with coarse timestamps
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(m.configPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first check only notices the change, the next one reloads it
+	m.Update(watchTickMsg{})
+	if len(m.entries) != 1 {
+		t.Fatal("A change should settle before it is reloaded")
+	}
+	m.Update(watchTickMsg{})
+	if len(m.entries) != 2 {
+		t.Fatalf("Expected the settled change to be reloaded, got %d hosts", len(m.entries))
+	}
+	if m.statusMsg != "Config changed on disk, reloaded" {
+		t.Errorf("Unexpected status %q", m.statusMsg)
+	}
+
+	// gosshit's own writes reload right away and don't trigger the watcher
+	m.selectHost("beta")
+	m.Update(keyRunes("d"))
+	m.Update(keyRunes("y"))
+	if len(m.entries) != 1 {
+		t.Fatalf("Expected beta to be deleted, got %d hosts", len(m.entries))
+	}
+	m.statusMsg = ""
+	m.Update(watchTickMsg{})
+	m.Update(watchTickMsg{})
+	if m.statusMsg != "" || m.pendingStamp != nil {
+		t.Errorf("An own write should not be reloaded again, got status %q", m.statusMsg)
+	}
+}
+
+func TestModel_WatchReloadsIncludedFiles(t *testing.T) {
+	m := newTestModel(t, "Include work.conf\n\nHost alpha\n    HostName a.example.com\n")
+	included := filepath.Join(filepath.Dir(m.configPath), "work.conf")
+	if err := os.WriteFile(included, []byte("Host work\n    HostName work.example.com\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := InitialModel(m.configPath)
+	if err != nil {
+		t.Fatalf("InitialModel failed: %v", err)
+	}
+	m.SetWatchConfig(true)
+
+	config := "Host work\n    HostName work.example.com\n\nHost staging\n    HostName staging.example.com\n"
+	if err := os.WriteFile(included, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(included, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Update(watchTickMsg{})
+	m.Update(watchTickMsg{})
+	if len(m.entries) != 3 {
+		t.Fatalf("Expected the change to the included file to be reloaded, got %d hosts", len(m.entries))
+	}
+	if m.statusMsg != "Config changed on disk, reloaded" {
+		t.Errorf("Unexpected status %q", m.statusMsg)
+	}
+}
@@ -1,12 +1,19 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nicklasos/gosshit/internal/sshconfig"
+	"github.com/nicklasos/gosshit/internal/storage"
 	"github.com/nicklasos/gosshit/internal/ui"
 )
 
@@ -16,6 +23,22 @@ func main() {
 	// Define flags
 	showVersion := flag.Bool("version", false, "Show version information")
 	showCredits := flag.Bool("credits", false, "Show credits")
+	dumpTracker := flag.Bool("dump-tracker", false, "Print the stored visit data for every tracked host and exit")
+	dumpMetrics := flag.Bool("metrics", false, "Print the visit data in Prometheus text format and exit")
+	useGlobal := flag.Bool("global", false, "Ignore any project-local .gosshit/config and use ~/.ssh/config")
+	configFlag := flag.String("config", "", "SSH config file to use instead of ~/.ssh/config (created on the first save if missing)")
+	listHostsFlag := flag.Bool("list", false, "Print the configured hosts and exit (no TUI)")
+	listFormat := flag.String("format", "plain", "Output format for --list: plain or json")
+	exportPath := flag.String("export", "", "Write every host to a JSON file and exit")
+	importPath := flag.String("import", "", "Merge hosts from a JSON file (as written by --export) into the config and exit")
+	overwrite := flag.Bool("overwrite", false, "With --import, replace hosts that already exist instead of skipping them")
+	prune := flag.Bool("prune", false, "Remove visit data for hosts that are no longer in ~/.ssh/config and exit")
+	pruneAnyConfig := flag.Bool("prune-any-config", false, "Let --prune use the --config or project config instead of ~/.ssh/config")
+	validate := flag.Bool("validate", false, "Check the config for duplicate aliases and hosts without HostName, then exit (reads stdin when piped or with --config -)")
+	useTmux := flag.Bool("tmux", false, "Connect through a per-host tmux session (tmux new-session -A -s <alias>)")
+	watchConfig := flag.Bool("watch", false, "Reload the host list automatically when the config file (or an included file with hosts) changes on disk")
+	resolveHosts := flag.Bool("resolve", false, "Resolve HostNames in the background so searching for an IP finds hosts whose HostName resolves to it")
+	wrapAround := flag.Bool("wrap", false, "Wrap list navigation: j on the last host goes to the first, k on the first to the last")
 	flag.Parse()
 
 	// Handle --version flag
@@ -31,17 +54,272 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle --dump-tracker flag
+	if *dumpTracker {
+		tracker, err := storage.NewVisitTracker()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading visit tracker: %v\n", err)
+			os.Exit(1)
+		}
+		for _, entry := range tracker.Entries() {
+			line := fmt.Sprintf("%s\t%d", entry.Host, entry.Count)
+			if !entry.LastVisit.IsZero() || entry.TotalDuration > 0 {
+				lastVisit := "-"
+				if !entry.LastVisit.IsZero() {
+					lastVisit = entry.LastVisit.Format(time.RFC3339)
+				}
+				line += "\t" + lastVisit
+			}
+			if entry.TotalDuration > 0 {
+				line += "\t" + storage.FormatDuration(entry.TotalDuration)
+			}
+			fmt.Println(line)
+		}
+		os.Exit(0)
+	}
+
+	// Handle --metrics flag
+	if *dumpMetrics {
+		tracker, err := storage.NewVisitTracker()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading visit tracker: %v\n", err)
+			os.Exit(1)
+		}
+		if err := storage.WriteMetrics(os.Stdout, tracker.Entries()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing metrics: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// An explicit --config wins; otherwise prefer a project-local
+	// .gosshit/config in the current directory or an ancestor
 	configPath := sshconfig.GetSSHConfigPath()
+	projectConfig := ""
+	if *configFlag == "" && !*useGlobal {
+		if cwd, err := os.Getwd(); err == nil {
+			projectConfig = sshconfig.FindProjectConfig(cwd)
+		}
+	}
+	if *configFlag != "" {
+		configPath = *configFlag
+		// --config=~/... isn't expanded by the shell
+		if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(configPath, "~/") {
+			configPath = filepath.Join(home, configPath[2:])
+		}
+	} else if projectConfig != "" {
+		configPath = projectConfig
+	}
+
+	// Handle --list flag
+	if *listHostsFlag {
+		if err := listHosts(configPath, *listFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing hosts: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle --prune flag
+	if *prune {
+		// The visit data is shared by every config, so pruning against anything
+		// but the default one drops the history of all the hosts it lacks
+		if configPath != sshconfig.GetSSHConfigPath() && !*pruneAnyConfig {
+			fmt.Fprintf(os.Stderr, "Error: --prune would remove the visit data of every host not in %s, including all of %s; run it with --global, or add --prune-any-config if that's intended\n", configPath, sshconfig.GetSSHConfigPath())
+			os.Exit(1)
+		}
+		removed, err := pruneTracker(configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error pruning visit tracker: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Pruned %d tracked hosts not in %s\n", removed, configPath)
+		os.Exit(0)
+	}
+
+	// Handle --validate flag
+	if *validate {
+		// A piped config is validated unless a file was named explicitly
+		path := configPath
+		if *configFlag == "" && stdinIsPiped() {
+			path = "-"
+		}
+		problems, err := validateConfig(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error validating config: %v\n", err)
+			os.Exit(1)
+		}
+		if problems > 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle --export flag
+	if *exportPath != "" {
+		if err := exportHosts(configPath, *exportPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting hosts: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle --import flag
+	if *importPath != "" {
+		result, err := importHosts(configPath, *importPath, *overwrite)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error importing hosts: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Imported into %s: %d added, %d updated, %d skipped\n", configPath, result.Added, result.Updated, result.Skipped)
+		os.Exit(0)
+	}
+
+	// Apply the optional color theme before the UI is built
+	if themePath, err := ui.ThemePath(); err == nil {
+		if err := ui.LoadTheme(themePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading theme: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	model, err := ui.InitialModel(configPath)
+	var parseErr *sshconfig.ParseError
+	if errors.As(err, &parseErr) && parseErr.Line > 0 {
+		fmt.Fprintf(os.Stderr, "Config %s appears malformed at line %d: %s\n", parseErr.Path, parseErr.Line, parseErr.Reason)
+		os.Exit(1)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing application: %v\n", err)
 		os.Exit(1)
 	}
 
+	if *configFlag != "" {
+		label := "config: " + configPath
+		if _, err := os.Stat(configPath); os.IsNotExist(err) {
+			label += " (new file)"
+		}
+		model.SetConfigLabel(label)
+	} else if projectConfig != "" {
+		model.SetConfigLabel("project config: " + projectConfig)
+	}
+
+	model.SetTmuxConnect(*useTmux)
+	model.SetWrapAround(*wrapAround)
+	model.SetResolveHosts(*resolveHosts)
+	model.SetWatchConfig(*watchConfig)
+
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// listHosts prints every host (except Host *) to stdout, one alias per line
+// for the plain format or as a JSON array of entries for the json format
+func listHosts(configPath string, format string) error {
+	hosts, err := sshconfig.LoadHosts(configPath)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "plain":
+		for _, entry := range hosts {
+			fmt.Println(entry.Host)
+		}
+		return nil
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(hosts)
+	default:
+		return fmt.Errorf("unknown format %q (use plain or json)", format)
+	}
+}
+
+// pruneTracker removes the visit data of hosts that aren't in the config at
+// configPath and returns how many were removed
+func pruneTracker(configPath string) (int, error) {
+	// A missing config would look like one without hosts and prune everything
+	if _, err := os.Stat(configPath); err != nil {
+		return 0, err
+	}
+	entries, _, err := sshconfig.ParseConfig(configPath)
+	if err != nil {
+		return 0, err
+	}
+
+	tracker, err := storage.NewVisitTracker()
+	if err != nil {
+		return 0, err
+	}
+	removed := tracker.Prune(sshconfig.HostNames(entries))
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, tracker.Save()
+}
+
+// validateConfig reports the structural problems of the config at path (stdin
+// for "-") on stderr and returns how many there were
+func validateConfig(path string) (int, error) {
+	var r io.Reader = os.Stdin
+	name := "<stdin>"
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return 0, err
+		}
+		defer file.Close()
+		r, name = file, path
+	}
+
+	problems, hosts, err := sshconfig.ValidateConfig(r, name)
+	if err != nil {
+		return 0, err
+	}
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stderr, "%s:%d: %s\n", name, problem.Line, problem.Message)
+	}
+	if len(problems) == 0 {
+		fmt.Printf("%s: %d Host blocks, no problems found\n", name, hosts)
+	}
+	return len(problems), nil
+}
+
+// stdinIsPiped reports whether stdin is a pipe or file rather than a terminal
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// exportHosts writes every entry of the config (including Host * blocks) to
+// path as a JSON array
+func exportHosts(configPath string, path string) error {
+	entries, _, err := sshconfig.ParseConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// importHosts merges the entries of a JSON file written by exportHosts into the config
+func importHosts(configPath string, path string, overwrite bool) (sshconfig.ImportResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return sshconfig.ImportResult{}, err
+	}
+
+	var entries []*sshconfig.HostEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return sshconfig.ImportResult{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return sshconfig.ImportEntries(configPath, entries, overwrite)
+}